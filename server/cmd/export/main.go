@@ -0,0 +1,83 @@
+package main
+
+import (
+	"bufio"
+	"context"
+	"flag"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"os/signal"
+
+	"backend/internal/export"
+	"backend/internal/infrastructure/database"
+	"backend/internal/infrastructure/stream"
+	"backend/internal/logging"
+	"backend/pkg/config"
+	pkgdatabase "backend/pkg/database"
+)
+
+func main() {
+	target := flag.String("target", "shops", "export target (shops, reviews, stations)")
+	format := flag.String("format", "ndjson", "output format (ndjson, csv, geojson)")
+	out := flag.String("o", "-", "output file (\"-\" for stdout)")
+	flag.Parse()
+
+	// 標準出力はエクスポートの出力先になるので、ログは標準エラー出力に書く
+	if err := logging.Setup(os.Stderr, config.LogLevel()); err != nil {
+		fatal("Failed to setup logging", err)
+	}
+
+	t, err := export.ParseTarget(*target)
+	if err != nil {
+		fatal("Invalid target", fmt.Errorf("%q: %w", *target, err))
+	}
+
+	f, err := export.ParseFormat(*format)
+	if err != nil {
+		fatal("Invalid format", fmt.Errorf("%q: %w", *format, err))
+	}
+	if !t.Supports(f) {
+		fatal("Invalid format", fmt.Errorf("format %q is not supported for %s", *format, t))
+	}
+
+	db, err := pkgdatabase.Setup(config.MySQL())
+	if err != nil {
+		fatal("Failed to setup database", err)
+	}
+	defer db.Close()
+
+	var w io.Writer = os.Stdout
+	if *out != "-" {
+		file, err := os.Create(*out)
+		if err != nil {
+			fatal("Failed to create output file", err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	bw := bufio.NewWriter(w)
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	exporter := export.NewExporter(
+		database.NewShopRepository(db, stream.Discard),
+		database.NewReviewRepository(db, stream.Discard),
+		database.NewStationRepository(db, stream.Discard),
+	)
+	if err := exporter.Export(ctx, bw, t, f); err != nil {
+		fatal("Failed to export", err)
+	}
+
+	if err := bw.Flush(); err != nil {
+		fatal("Failed to flush output", err)
+	}
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
+}
@@ -1,24 +1,106 @@
 package main
 
 import (
-	"log"
+	"context"
+	"errors"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"sync"
+	"syscall"
 
 	"backend/cmd/server/server"
+	"backend/internal/logging"
+	"backend/internal/tracing"
 	"backend/pkg/config"
 	"backend/pkg/database"
 )
 
 func main() {
+	if err := logging.Setup(os.Stdout, config.LogLevel()); err != nil {
+		fatal("Failed to setup logging", err)
+	}
+
+	shutdownTracing, err := tracing.Setup(context.Background(), config.Tracing())
+	if err != nil {
+		fatal("Failed to setup tracing", err)
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt, syscall.SIGTERM)
+	err = serve(ctx)
+	stop()
+
+	// os.Exit は defer を実行しないので、バッチに残ったスパンは先に送っておく
+	flushCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout())
+	if err := shutdownTracing(flushCtx); err != nil {
+		slog.Error("failed to flush traces", "error", err)
+	}
+	cancel()
+
+	if err != nil {
+		fatal("Server stopped", err)
+	}
+}
+
+// serve runs the server until ctx is cancelled, then waits for in-flight
+// requests and the webhook delivery being sent to finish.
+func serve(ctx context.Context) error {
 	db, err := database.Setup(config.MySQL())
 	if err != nil {
-		log.Fatal("Failed to setup database:", err)
+		return fmt.Errorf("failed to setup database: %w", err)
 	}
 	defer db.Close()
 
 	s := server.Inject(db)
 
-	log.Printf("Server starting on %s", config.AppAddr())
-	if err := s.Router.Start(config.AppAddr()); err != nil {
-		log.Fatal("Failed to start server:", err)
+	dispatcherCtx, stopDispatcher := context.WithCancel(ctx)
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		s.WebhookDispatcher.Run(dispatcherCtx)
+	}()
+	// 送信中の配信が終わってからDBを閉じる
+	defer func() {
+		stopDispatcher()
+		wg.Wait()
+	}()
+
+	errCh := make(chan error, 2)
+	go func() {
+		slog.Info("Server starting", "addr", config.AppAddr())
+		if err := s.Router.Start(config.AppAddr()); err != nil {
+			errCh <- fmt.Errorf("failed to start server: %w", err)
+		}
+	}()
+	go func() {
+		slog.Info("Metrics server starting", "addr", s.Metrics.Addr)
+		if err := s.Metrics.ListenAndServe(); err != nil {
+			errCh <- fmt.Errorf("failed to start metrics server: %w", err)
+		}
+	}()
+
+	var serveErr error
+	select {
+	case serveErr = <-errCh:
+	case <-ctx.Done():
+		slog.Info("Server shutting down")
+	}
+
+	shutdownCtx, cancel := context.WithTimeout(context.Background(), config.ShutdownTimeout())
+	defer cancel()
+	if err := s.Router.Shutdown(shutdownCtx); err != nil {
+		serveErr = errors.Join(serveErr, fmt.Errorf("failed to shutdown server: %w", err))
+	}
+	if err := s.Metrics.Shutdown(shutdownCtx); err != nil {
+		serveErr = errors.Join(serveErr, fmt.Errorf("failed to shutdown metrics server: %w", err))
 	}
+
+	return serveErr
+}
+
+func fatal(msg string, err error) {
+	slog.Error(msg, "error", err)
+	os.Exit(1)
 }
@@ -1,10 +1,24 @@
 package server
 
 import (
+	"net/http"
+
+	"backend/internal/bot"
+	"backend/internal/contentpolicy"
+	"backend/internal/export"
 	"backend/internal/handler"
+	"backend/internal/health"
 	"backend/internal/infrastructure/database"
 	"backend/internal/infrastructure/file"
+	"backend/internal/infrastructure/stream"
+	"backend/internal/infrastructure/traq"
+	"backend/internal/infrastructure/webhook"
+	"backend/internal/metrics"
+	"backend/internal/ratelimit"
 	"backend/internal/router"
+	"backend/internal/tracing"
+	"backend/pkg/config"
+	pkgdatabase "backend/pkg/database"
 
 	"github.com/jmoiron/sqlx"
 	"github.com/labstack/echo/v4"
@@ -12,30 +26,147 @@ import (
 
 type Server struct {
 	Router *echo.Echo
+
+	// Prometheusのメトリクスを配信する、クラスタ内向けのサーバー
+	Metrics *http.Server
+
+	// Webhookの配信キューを処理するワーカー
+	WebhookDispatcher *webhook.Dispatcher
 }
 
 func Inject(db *sqlx.DB) *Server {
-	shopRepo := database.NewShopRepository(db)
-	reviewRepo := database.NewReviewRepository(db)
-	stationRepo := database.NewStationRepository(db)
-	fileRepo, err := file.NewFileRepository()
+	broker := stream.NewBroker(stream.DefaultBufferSize)
+	metrics.RegisterDB(db.DB, "mysql")
+
+	shopRepo := metrics.ShopRepository(database.NewShopRepository(db, broker))
+	reviewRepo := metrics.ReviewRepository(database.NewReviewRepository(db, broker))
+	stationRepo := metrics.StationRepository(database.NewStationRepository(db, broker))
+	userRepo := metrics.UserRepository(database.NewUserRepository(db))
+	eventRepo := metrics.EventRepository(database.NewEventRepository(db))
+	webhookRepo := metrics.WebhookRepository(database.NewWebhookRepository(db))
+	menuRepo := metrics.MenuItemRepository(database.NewMenuItemRepository(db))
+	tagRepo := metrics.TagRepository(database.NewTagRepository(db))
+	paymentMethodRepo := metrics.PaymentMethodRepository(database.NewPaymentMethodRepository(db))
+	closureReportRepo := metrics.ClosureReportRepository(database.NewClosureReportRepository(db))
+	editProposalRepo := metrics.EditProposalRepository(database.NewEditProposalRepository(db, broker))
+	moderationRepo := metrics.ModerationRepository(database.NewModerationRepository(db))
+	idempotencyRepo := metrics.IdempotencyRepository(database.NewIdempotencyRepository(db))
+	auditRepo := metrics.AuditLogRepository(database.NewAuditLogRepository(db))
+	storage, err := file.NewFileRepository()
 	if err != nil {
 		panic("failed to create file repository: " + err.Error())
 	}
+	fileRepo := metrics.FileRepository(storage)
+	reviewPolicy, err := contentpolicy.NewReviewPolicy(config.ReviewPolicy())
+	if err != nil {
+		panic("failed to create review policy: " + err.Error())
+	}
 
-	shopHandler := handler.NewShopHandler(shopRepo, fileRepo)
-	reviewHandler := handler.NewReviewHandler(reviewRepo, fileRepo)
-	stationHandler := handler.NewStationHandler(stationRepo, shopRepo)
+	limiter, err := ratelimit.NewLimiter(ratelimit.NewMemoryStore(), config.RateLimit())
+	if err != nil {
+		panic("failed to create rate limiter: " + err.Error())
+	}
+
+	// 依存先の障害で再起動されないよう、liveness には外部サービスのチェックを含めない
+	liveness := health.NewRegistry(config.HealthCheckTimeout())
+	readiness := health.NewRegistry(config.HealthCheckTimeout())
+	migrationChecker, err := pkgdatabase.NewMigrationChecker(db.DB)
+	if err != nil {
+		panic("failed to create migration checker: " + err.Error())
+	}
+	readiness.Register("mysql", health.CheckerFunc(db.PingContext))
+	readiness.Register("migrations", migrationChecker)
+	readiness.Register("s3", storage)
+
+	admins := handler.NewAdmins(config.AdminUsers())
+
+	shopHandler := handler.NewShopHandler(
+		shopRepo,
+		fileRepo,
+		webhookRepo,
+		menuRepo,
+		tagRepo,
+		paymentMethodRepo,
+		auditRepo,
+		admins,
+	)
+	reviewHandler := handler.NewReviewHandler(
+		reviewRepo,
+		fileRepo,
+		webhookRepo,
+		menuRepo,
+		reviewPolicy,
+		auditRepo,
+		admins,
+	)
+	stationHandler := handler.NewStationHandler(stationRepo, shopRepo, webhookRepo, auditRepo)
 	fileHandler := handler.NewFileHandler(fileRepo)
+	exportHandler := handler.NewExportHandler(export.NewExporter(shopRepo, reviewRepo, stationRepo))
+	userHandler := handler.NewUserHandler(userRepo, shopRepo, fileRepo, auditRepo, admins)
+	feedHandler := handler.NewFeedHandler(eventRepo)
+	webhookHandler := handler.NewWebhookHandler(webhookRepo)
+	streamHandler := handler.NewStreamHandler(broker)
+	menuHandler := handler.NewMenuHandler(menuRepo, shopRepo, fileRepo, auditRepo)
+	tagHandler := handler.NewTagHandler(tagRepo)
+	paymentMethodHandler := handler.NewPaymentMethodHandler(paymentMethodRepo)
+	shopStatusHandler := handler.NewShopStatusHandler(
+		shopRepo,
+		closureReportRepo,
+		webhookRepo,
+		auditRepo,
+		config.ClosureReportThreshold(),
+	)
+	editProposalHandler := handler.NewEditProposalHandler(
+		editProposalRepo,
+		shopRepo,
+		tagRepo,
+		paymentMethodRepo,
+		webhookRepo,
+		auditRepo,
+		admins,
+	)
+	moderationHandler := handler.NewModerationHandler(moderationRepo, reviewRepo, fileRepo)
+	auditHandler := handler.NewAuditHandler(auditRepo)
+	healthHandler := handler.NewHealthHandler(liveness, readiness)
+	botHandler := handler.NewBotHandler(
+		bot.NewBot(shopRepo, stationRepo, reviewRepo, paymentMethodRepo),
+		traq.NewTraqRepository(),
+		config.TraqBot().VerificationToken,
+	)
 
 	echoRouter := router.NewRouter(
 		shopHandler,
 		reviewHandler,
 		stationHandler,
 		fileHandler,
+		exportHandler,
+		userHandler,
+		feedHandler,
+		webhookHandler,
+		botHandler,
+		streamHandler,
+		menuHandler,
+		tagHandler,
+		paymentMethodHandler,
+		shopStatusHandler,
+		editProposalHandler,
+		moderationHandler,
+		auditHandler,
+		healthHandler,
+		handler.NewUserIDMiddleware(userRepo),
+		handler.NewAdminMiddleware(admins),
+		handler.NewRateLimitMiddleware(limiter),
+		handler.NewQuotaMiddleware(limiter),
+		handler.NewIdempotencyMiddleware(idempotencyRepo, config.IdempotencyKeyTTL()),
+		tracing.NewMiddleware(config.Tracing().ServiceName),
 	)
 
+	// 開いたままのストリームを閉じないと、停止時に Shutdown が待ち続ける
+	echoRouter.Server.RegisterOnShutdown(broker.Shutdown)
+
 	return &Server{
-		Router: echoRouter,
+		Router:            echoRouter,
+		Metrics:           metrics.NewServer(config.MetricsAddr()),
+		WebhookDispatcher: webhook.NewDispatcher(webhookRepo),
 	}
 }
@@ -0,0 +1,338 @@
+package bot
+
+import (
+	"cmp"
+	"context"
+	"errors"
+	"fmt"
+	"math/rand/v2"
+	"slices"
+	"strings"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// /lunch で表示する店舗数
+	topShopCount = 5
+	// /shop で候補として表示する店舗数
+	searchLimit = 5
+	// /shop で表示する最新レビューの件数
+	recentReviewCount = 3
+	// レビュー本文はこの文字数で切り詰める
+	reviewExcerptLength = 60
+)
+
+const helpMessage = "使い方\n" +
+	"- `/lunch 駅名`: 駅周辺の評価の高い店舗を表示します\n" +
+	"- `/shop 店舗名`: 店舗の詳細を表示します\n" +
+	"- `/random [駅名]`: 店舗をランダムに1つ選びます"
+
+// Bot answers chat commands with shop and review data.
+type Bot struct {
+	shopRepo    repository.ShopRepository
+	stationRepo repository.StationRepository
+	reviewRepo  repository.ReviewRepository
+
+	paymentMethodRepo repository.PaymentMethodRepository
+}
+
+func NewBot(
+	shopRepo repository.ShopRepository,
+	stationRepo repository.StationRepository,
+	reviewRepo repository.ReviewRepository,
+	paymentMethodRepo repository.PaymentMethodRepository,
+) *Bot {
+	return &Bot{
+		shopRepo:    shopRepo,
+		stationRepo: stationRepo,
+		reviewRepo:  reviewRepo,
+
+		paymentMethodRepo: paymentMethodRepo,
+	}
+}
+
+// Reply returns the response to a message. ok is false when the message is
+// not a command, e.g. a plain mention of the bot.
+func (b *Bot) Reply(ctx context.Context, text string) (reply string, ok bool, err error) {
+	command, arg, ok := parseCommand(text)
+	if !ok {
+		return "", false, nil
+	}
+
+	switch command {
+	case "lunch":
+		reply, err = b.lunch(ctx, arg)
+	case "shop":
+		reply, err = b.shop(ctx, arg)
+	case "random":
+		reply, err = b.random(ctx, arg)
+	default:
+		reply = helpMessage
+	}
+
+	return reply, true, err
+}
+
+// parseCommand extracts "/command args" from a message, skipping the
+// mentions that precede it.
+func parseCommand(text string) (command string, arg string, ok bool) {
+	fields := strings.Fields(text)
+	for len(fields) > 0 && strings.HasPrefix(fields[0], "@") {
+		fields = fields[1:]
+	}
+
+	if len(fields) == 0 || !strings.HasPrefix(fields[0], "/") {
+		return "", "", false
+	}
+
+	return strings.ToLower(strings.TrimPrefix(fields[0], "/")), strings.Join(fields[1:], " "), true
+}
+
+func (b *Bot) lunch(ctx context.Context, stationName string) (string, error) {
+	if stationName == "" {
+		return "駅名を指定してください（例: `/lunch 大岡山`）", nil
+	}
+
+	station, err := b.findStation(ctx, stationName)
+	if errors.Is(err, repository.ErrNotFound) {
+		return fmt.Sprintf("「%s」という駅は見つかりませんでした", stationName), nil
+	}
+	if err != nil {
+		return "", err
+	}
+
+	shops, err := b.shopRepo.FindByStation(ctx, station.ID)
+	if err != nil {
+		return "", fmt.Errorf("failed to find shops by station: %w", err)
+	}
+	shops = openShops(shops)
+	if len(shops) == 0 {
+		return fmt.Sprintf("%s駅周辺の店舗はまだ登録されていません", station.Name), nil
+	}
+
+	summaries, err := b.summarize(ctx, shops)
+	if err != nil {
+		return "", err
+	}
+
+	// 評価の平均、レビュー数の順に並べる。レビューのない店舗は最後
+	slices.SortStableFunc(shops, func(x, y *model.Shop) int {
+		sx, sy := summaries[x.ID], summaries[y.ID]
+		switch {
+		case sx == nil && sy == nil:
+			return 0
+		case sx == nil:
+			return 1
+		case sy == nil:
+			return -1
+		}
+
+		return cmp.Or(cmp.Compare(sy.Average, sx.Average), cmp.Compare(sy.Count, sx.Count))
+	})
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### %s駅周辺のおすすめ\n", station.Name)
+	for i, shop := range shops[:min(len(shops), topShopCount)] {
+		fmt.Fprintf(&sb, "%d. **%s** %s\n", i+1, shop.Name, formatRating(summaries[shop.ID]))
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+func (b *Bot) shop(ctx context.Context, keyword string) (string, error) {
+	if keyword == "" {
+		return "店舗名を指定してください（例: `/shop 店舗名`）", nil
+	}
+
+	shops, err := b.shopRepo.SearchByName(ctx, keyword, searchLimit)
+	if err != nil {
+		return "", fmt.Errorf("failed to search shops: %w", err)
+	}
+	if len(shops) == 0 {
+		return fmt.Sprintf("「%s」に一致する店舗は見つかりませんでした", keyword), nil
+	}
+
+	reply, err := b.describe(ctx, shops[0])
+	if err != nil {
+		return "", err
+	}
+
+	if len(shops) > 1 {
+		names := make([]string, 0, len(shops)-1)
+		for _, shop := range shops[1:] {
+			names = append(names, string(shop.Name))
+		}
+		reply += "\n\n他の候補: " + strings.Join(names, "、")
+	}
+
+	return reply, nil
+}
+
+func (b *Bot) random(ctx context.Context, stationName string) (string, error) {
+	var (
+		shops []*model.Shop
+		err   error
+	)
+	if stationName == "" {
+		shops, err = b.shopRepo.FindAll(ctx)
+	} else {
+		var station *model.Station
+		station, err = b.findStation(ctx, stationName)
+		if errors.Is(err, repository.ErrNotFound) {
+			return fmt.Sprintf("「%s」という駅は見つかりませんでした", stationName), nil
+		}
+		if err != nil {
+			return "", err
+		}
+		shops, err = b.shopRepo.FindByStation(ctx, station.ID)
+	}
+	if err != nil {
+		return "", fmt.Errorf("failed to find shops: %w", err)
+	}
+
+	shops = openShops(shops)
+	if len(shops) == 0 {
+		return "候補になる店舗がありません", nil
+	}
+
+	reply, err := b.describe(ctx, shops[rand.IntN(len(shops))])
+	if err != nil {
+		return "", err
+	}
+
+	return "今日のランチはここ！\n" + reply, nil
+}
+
+var closedLabels = map[model.ShopStatus]string{
+	model.ShopStatusTemporarilyClosed: "休業中",
+	model.ShopStatusPermanentlyClosed: "閉店しました",
+	model.ShopStatusMoved:             "移転しました",
+}
+
+// openShops drops closed and moved shops from the candidates.
+func openShops(shops []*model.Shop) []*model.Shop {
+	return slices.DeleteFunc(shops, func(shop *model.Shop) bool {
+		return shop.Status.IsClosed()
+	})
+}
+
+// describe formats the details of a shop along with its latest reviews.
+func (b *Bot) describe(ctx context.Context, shop *model.Shop) (string, error) {
+	summaries, err := b.summarize(ctx, []*model.Shop{shop})
+	if err != nil {
+		return "", err
+	}
+
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "### %s\n", shop.Name)
+	fmt.Fprintf(&sb, "評価: %s\n", formatRating(summaries[shop.ID]))
+
+	if label, ok := closedLabels[shop.Status]; ok {
+		fmt.Fprintf(&sb, "**%s**\n", label)
+	}
+
+	if shop.Address != "" {
+		fmt.Fprintf(&sb, "住所: %s\n", shop.Address)
+	}
+
+	stationNames := make([]string, 0, len(shop.Stations))
+	for _, id := range shop.Stations {
+		station, err := b.stationRepo.FindByID(ctx, id)
+		if err != nil {
+			return "", fmt.Errorf("failed to find station: %w", err)
+		}
+		stationNames = append(stationNames, station.Name)
+	}
+	if len(stationNames) > 0 {
+		fmt.Fprintf(&sb, "最寄り駅: %s\n", strings.Join(stationNames, "、"))
+	}
+
+	if len(shop.PaymentMethods) > 0 {
+		catalog, err := b.paymentMethodRepo.FindCatalog(ctx)
+		if err != nil {
+			return "", fmt.Errorf("failed to find payment methods: %w", err)
+		}
+		names := make([]string, len(shop.PaymentMethods))
+		for i, code := range shop.PaymentMethods {
+			names[i] = catalog.DisplayName(code)
+		}
+		fmt.Fprintf(&sb, "支払い方法: %s\n", strings.Join(names, "、"))
+	}
+
+	reviews, err := b.reviewRepo.FindRecentReviews(
+		ctx,
+		repository.ReviewFilter{ShopID: shop.ID},
+		nil,
+		recentReviewCount,
+		0,
+	)
+	if err != nil {
+		return "", fmt.Errorf("failed to find reviews: %w", err)
+	}
+	if len(reviews) > 0 {
+		sb.WriteString("最新のレビュー:\n")
+		for _, review := range reviews {
+			fmt.Fprintf(&sb, "> %s %s\n", formatStars(float64(review.Rating)), excerpt(review.Content))
+		}
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n"), nil
+}
+
+// findStation looks up a station by name, also accepting names with a
+// trailing "駅".
+func (b *Bot) findStation(ctx context.Context, name string) (*model.Station, error) {
+	station, err := b.stationRepo.FindByName(ctx, name)
+	if errors.Is(err, repository.ErrNotFound) && strings.HasSuffix(name, "駅") {
+		station, err = b.stationRepo.FindByName(ctx, strings.TrimSuffix(name, "駅"))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to find station: %w", err)
+	}
+
+	return station, nil
+}
+
+func (b *Bot) summarize(ctx context.Context, shops []*model.Shop) (map[uuid.UUID]*model.RatingSummary, error) {
+	ids := make([]uuid.UUID, len(shops))
+	for i, shop := range shops {
+		ids[i] = shop.ID
+	}
+
+	summaries, err := b.reviewRepo.SummarizeRatings(ctx, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to summarize ratings: %w", err)
+	}
+
+	return summaries, nil
+}
+
+func formatRating(summary *model.RatingSummary) string {
+	if summary == nil {
+		return "レビューなし"
+	}
+
+	return fmt.Sprintf("%s %.1f（%d件）", formatStars(summary.Average), summary.Average, summary.Count)
+}
+
+// formatStars renders a rating on the 0-3 scale as stars.
+func formatStars(rating float64) string {
+	filled := int(rating + 0.5)
+
+	return strings.Repeat("★", filled) + strings.Repeat("☆", 3-filled)
+}
+
+func excerpt(content string) string {
+	content = strings.Join(strings.Fields(content), " ")
+
+	runes := []rune(content)
+	if len(runes) <= reviewExcerptLength {
+		return content
+	}
+
+	return string(runes[:reviewExcerptLength]) + "…"
+}
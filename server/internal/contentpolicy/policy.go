@@ -0,0 +1,101 @@
+// Package contentpolicy checks user generated text such as review content
+// against a list of rules before it is published.
+package contentpolicy
+
+import (
+	"backend/internal/domain/model"
+	"strings"
+)
+
+// Action is what happens to content that breaks a rule.
+type Action string
+
+const (
+	// ActionQueue publishes nothing until a moderator approves the content.
+	ActionQueue Action = "queue"
+	// ActionReject refuses the content.
+	ActionReject Action = "reject"
+)
+
+func ParseAction(action string) (Action, bool) {
+	switch Action(action) {
+	case ActionQueue, ActionReject:
+		return Action(action), true
+	default:
+		return "", false
+	}
+}
+
+// Violation describes why content breaks a rule.
+type Violation struct {
+	Rule    string
+	Message string
+	// Reason is the report reason used when the content is queued.
+	Reason model.ReportReason
+}
+
+// Rule is a single check of the policy.
+type Rule interface {
+	Name() string
+	// Check returns nil when content complies with the rule.
+	Check(content string) *Violation
+}
+
+// Decision is the outcome of a policy for a piece of content.
+type Decision struct {
+	// Action is empty when the content complies with every rule.
+	Action     Action
+	Violations []*Violation
+}
+
+func (d *Decision) Allowed() bool {
+	return d.Action == ""
+}
+
+// Message joins the messages of the violations.
+func (d *Decision) Message() string {
+	messages := make([]string, 0, len(d.Violations))
+	for _, v := range d.Violations {
+		messages = append(messages, v.Message)
+	}
+
+	return strings.Join(messages, "; ")
+}
+
+type entry struct {
+	rule   Rule
+	action Action
+}
+
+// Policy runs rules in order. The strictest action of the broken rules
+// decides what happens to the content.
+type Policy struct {
+	entries []entry
+}
+
+func New() *Policy {
+	return &Policy{}
+}
+
+// Add registers rule with the action taken when it is broken.
+func (p *Policy) Add(rule Rule, action Action) *Policy {
+	p.entries = append(p.entries, entry{rule: rule, action: action})
+
+	return p
+}
+
+func (p *Policy) Evaluate(content string) *Decision {
+	decision := &Decision{}
+	for _, e := range p.entries {
+		violation := e.rule.Check(content)
+		if violation == nil {
+			continue
+		}
+		decision.Violations = append(decision.Violations, violation)
+		if decision.Action != ActionReject {
+			decision.Action = e.action
+		}
+	}
+
+	return decision
+}
@@ -0,0 +1,66 @@
+package contentpolicy
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"strings"
+
+	"backend/pkg/config"
+)
+
+// NewReviewPolicy builds the policy for review content. Content over the
+// length limit is always rejected; what happens to NG words and spam is
+// configurable.
+func NewReviewPolicy(cfg *config.ReviewPolicyConfig) (*Policy, error) {
+	ngWordAction, ok := ParseAction(cfg.NGWordAction)
+	if !ok {
+		return nil, fmt.Errorf("invalid NG word action %q", cfg.NGWordAction)
+	}
+	spamAction, ok := ParseAction(cfg.SpamAction)
+	if !ok {
+		return nil, fmt.Errorf("invalid spam action %q", cfg.SpamAction)
+	}
+
+	words := cfg.NGWords
+	if cfg.NGWordsFile != "" {
+		fileWords, err := readWords(cfg.NGWordsFile)
+		if err != nil {
+			return nil, err
+		}
+		words = append(words, fileWords...)
+	}
+
+	policy := New().
+		Add(&MaxLength{Chars: cfg.MaxChars}, ActionReject).
+		Add(NewNGWords(words), ngWordAction).
+		Add(&RepeatedChars{Max: cfg.MaxRepeatedChars}, spamAction).
+		Add(&URLSpam{Max: cfg.MaxURLs}, spamAction)
+
+	return policy, nil
+}
+
+// readWords reads one word per line, skipping blank lines and lines starting
+// with #.
+func readWords(path string) ([]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to open NG word list: %w", err)
+	}
+	defer f.Close()
+
+	var words []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		words = append(words, line)
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("failed to read NG word list: %w", err)
+	}
+
+	return words, nil
+}
@@ -0,0 +1,117 @@
+package contentpolicy
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"backend/internal/domain/model"
+	"backend/pkg/textnorm"
+)
+
+// MaxLength limits the number of characters, not bytes, so that Japanese
+// text gets the same allowance as ASCII text.
+type MaxLength struct {
+	Chars int
+}
+
+func (r *MaxLength) Name() string { return "max_length" }
+
+func (r *MaxLength) Check(content string) *Violation {
+	if n := utf8.RuneCountInString(content); n > r.Chars {
+		return &Violation{
+			Rule:    r.Name(),
+			Message: fmt.Sprintf("content must be at most %d characters, got %d", r.Chars, n),
+			Reason:  model.ReportReasonOther,
+		}
+	}
+
+	return nil
+}
+
+// NGWords catches words that must not appear. Words and content are folded
+// with textnorm.Fold, so full-width, half-width, katakana and hiragana
+// spellings and inserted spaces all match.
+type NGWords struct {
+	folded []string
+}
+
+func NewNGWords(words []string) *NGWords {
+	r := &NGWords{}
+	for _, word := range words {
+		if folded := textnorm.Fold(word); folded != "" {
+			r.folded = append(r.folded, folded)
+		}
+	}
+
+	return r
+}
+
+func (r *NGWords) Name() string { return "ng_words" }
+
+func (r *NGWords) Check(content string) *Violation {
+	folded := textnorm.Fold(content)
+	for _, word := range r.folded {
+		if strings.Contains(folded, word) {
+			// どの語に当たったかは返さない（回避の手がかりになるため）
+			return &Violation{
+				Rule:    r.Name(),
+				Message: "content contains a prohibited word",
+				Reason:  model.ReportReasonOffensive,
+			}
+		}
+	}
+
+	return nil
+}
+
+// RepeatedChars catches the same character repeated more than Max times in
+// a row, e.g. "うまああああああああああい" or "wwwwwwwwwwww".
+type RepeatedChars struct {
+	Max int
+}
+
+func (r *RepeatedChars) Name() string { return "repeated_chars" }
+
+func (r *RepeatedChars) Check(content string) *Violation {
+	var prev rune
+	run := 0
+	for _, c := range content {
+		if c == prev {
+			run++
+		} else {
+			prev, run = c, 1
+		}
+		if run > r.Max && c != '\n' && c != ' ' {
+			return &Violation{
+				Rule:    r.Name(),
+				Message: fmt.Sprintf("content repeats the same character more than %d times", r.Max),
+				Reason:  model.ReportReasonSpam,
+			}
+		}
+	}
+
+	return nil
+}
+
+var urlPattern = regexp.MustCompile(`(?i)(?:https?://|www\.)\S+`)
+
+// URLSpam catches content with more than Max links.
+type URLSpam struct {
+	Max int
+}
+
+func (r *URLSpam) Name() string { return "url_spam" }
+
+func (r *URLSpam) Check(content string) *Violation {
+	if n := len(urlPattern.FindAllStringIndex(content, -1)); n > r.Max {
+		return &Violation{
+			Rule:    r.Name(),
+			Message: fmt.Sprintf("content must contain at most %d links, got %d", r.Max, n),
+			Reason:  model.ReportReasonSpam,
+		}
+	}
+
+	return nil
+}
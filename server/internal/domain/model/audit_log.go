@@ -0,0 +1,92 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type AuditAction string
+
+const (
+	AuditActionCreate AuditAction = "create"
+	AuditActionUpdate AuditAction = "update"
+	AuditActionDelete AuditAction = "delete"
+)
+
+func ParseAuditAction(action string) (AuditAction, error) {
+	switch AuditAction(action) {
+	case AuditActionCreate, AuditActionUpdate, AuditActionDelete:
+		return AuditAction(action), nil
+	default:
+		return "", ErrInvalidAuditLog
+	}
+}
+
+type AuditEntityType string
+
+const (
+	AuditEntityShop    AuditEntityType = "shop"
+	AuditEntityReview  AuditEntityType = "review"
+	AuditEntityStation AuditEntityType = "station"
+	AuditEntityImage   AuditEntityType = "image"
+)
+
+func ParseAuditEntityType(entityType string) (AuditEntityType, error) {
+	switch AuditEntityType(entityType) {
+	case AuditEntityShop, AuditEntityReview, AuditEntityStation, AuditEntityImage:
+		return AuditEntityType(entityType), nil
+	default:
+		return "", ErrInvalidAuditLog
+	}
+}
+
+// AuditLog records who changed what. Entries are only ever appended, and
+// they stay after the entity is deleted.
+type AuditLog struct {
+	ID         uuid.UUID
+	Actor      UserID
+	Action     AuditAction
+	EntityType AuditEntityType
+	EntityID   uuid.UUID
+	// Before and After are JSON snapshots of the entity as the API returns
+	// it. Before is nil for creations and After is nil for deletions.
+	Before    []byte
+	After     []byte
+	RequestID string
+	CreatedAt time.Time
+}
+
+func NewAuditLog(
+	actor UserID,
+	action AuditAction,
+	entityType AuditEntityType,
+	entityID uuid.UUID,
+	before []byte,
+	after []byte,
+	requestID string,
+) (*AuditLog, error) {
+	if _, err := ParseAuditAction(string(action)); err != nil {
+		return nil, err
+	}
+	if _, err := ParseAuditEntityType(string(entityType)); err != nil {
+		return nil, err
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &AuditLog{
+		ID:         id,
+		Actor:      actor,
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     before,
+		After:      after,
+		RequestID:  requestID,
+		CreatedAt:  time.Now(),
+	}, nil
+}
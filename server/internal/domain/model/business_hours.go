@@ -0,0 +1,120 @@
+package model
+
+import (
+	"slices"
+	"time"
+	// コンテナにタイムゾーンデータがなくても Asia/Tokyo を読み込めるようにする
+	_ "time/tzdata"
+)
+
+const minutesPerDay = 24 * 60
+
+// Tokyo is the time zone business hours are expressed in.
+var Tokyo = mustLoadLocation("Asia/Tokyo")
+
+func mustLoadLocation(name string) *time.Location {
+	loc, err := time.LoadLocation(name)
+	if err != nil {
+		panic(err)
+	}
+
+	return loc
+}
+
+// TimeRange is an opening period starting on Weekday. Start and End are
+// minutes since midnight of that day; End exceeds 24:00 for ranges that run
+// past midnight, e.g. 18:00-26:00 for a bar open until 2 am.
+type TimeRange struct {
+	Weekday time.Weekday
+	Start   int
+	End     int
+}
+
+func NewTimeRange(weekday time.Weekday, start, end int) (TimeRange, error) {
+	// 終了が開始以前なら翌日にまたがる営業とみなす
+	if end <= start {
+		end += minutesPerDay
+	}
+
+	if weekday < time.Sunday || weekday > time.Saturday ||
+		start < 0 || start >= minutesPerDay || end-start > minutesPerDay {
+		return TimeRange{}, ErrInvalidBusinessHours
+	}
+
+	return TimeRange{Weekday: weekday, Start: start, End: end}, nil
+}
+
+// Closure is a day on which the shop is closed regardless of its weekly
+// hours. Date is midnight of that day in Tokyo.
+type Closure struct {
+	Date time.Time
+	Note string
+}
+
+func NewClosure(date time.Time, note string) Closure {
+	y, m, d := date.In(Tokyo).Date()
+
+	return Closure{Date: time.Date(y, m, d, 0, 0, 0, 0, Tokyo), Note: note}
+}
+
+// BusinessHours is the weekly schedule of a shop with irregular closures.
+type BusinessHours struct {
+	Ranges   []TimeRange
+	Closures []Closure
+}
+
+func NewBusinessHours(ranges []TimeRange, closures []Closure) *BusinessHours {
+	ranges = slices.Clone(ranges)
+	slices.SortFunc(ranges, func(a, b TimeRange) int {
+		if a.Weekday != b.Weekday {
+			return int(a.Weekday) - int(b.Weekday)
+		}
+
+		return a.Start - b.Start
+	})
+	ranges = slices.CompactFunc(ranges, func(a, b TimeRange) bool {
+		return a.Weekday == b.Weekday && a.Start == b.Start
+	})
+
+	closures = slices.Clone(closures)
+	slices.SortFunc(closures, func(a, b Closure) int {
+		return a.Date.Compare(b.Date)
+	})
+	closures = slices.CompactFunc(closures, func(a, b Closure) bool {
+		return a.Date.Equal(b.Date)
+	})
+
+	return &BusinessHours{Ranges: ranges, Closures: closures}
+}
+
+// IsOpenAt reports whether the shop is open at t. A range that started the
+// previous day still counts when it runs past midnight; closures cancel the
+// ranges starting on that day.
+func (h *BusinessHours) IsOpenAt(t time.Time) bool {
+	t = t.In(Tokyo)
+	y, m, d := t.Date()
+	today := time.Date(y, m, d, 0, 0, 0, 0, Tokyo)
+	yesterday := today.AddDate(0, 0, -1)
+	minute := t.Hour()*60 + t.Minute()
+
+	for _, r := range h.Ranges {
+		if r.Weekday == today.Weekday() && r.Start <= minute && minute < r.End && !h.isClosedOn(today) {
+			return true
+		}
+		if r.Weekday == yesterday.Weekday() && minute+minutesPerDay < r.End && !h.isClosedOn(yesterday) {
+			return true
+		}
+	}
+
+	return false
+}
+
+func (h *BusinessHours) isClosedOn(day time.Time) bool {
+	for _, c := range h.Closures {
+		if c.Date.Equal(day) {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,17 @@
+package model
+
+import "context"
+
+type userIDContextKey struct{}
+
+// WithUserID returns a context carrying the authenticated user, so layers
+// below the handlers can attribute changes to it.
+func WithUserID(ctx context.Context, id UserID) context.Context {
+	return context.WithValue(ctx, userIDContextKey{}, id)
+}
+
+func UserIDFromContext(ctx context.Context) (UserID, bool) {
+	id, ok := ctx.Value(userIDContextKey{}).(UserID)
+
+	return id, ok
+}
@@ -0,0 +1,58 @@
+package model
+
+import (
+	"encoding/base64"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ReviewCursor points at the last review of a page. Reviews are ordered by
+// (created_at, id) descending, and IDs are UUIDv7, so the pair is unique and
+// stable even when new reviews are inserted between page loads.
+type ReviewCursor struct {
+	CreatedAt time.Time
+	ID        uuid.UUID
+}
+
+func NewReviewCursor(r *Review) *ReviewCursor {
+	return &ReviewCursor{
+		CreatedAt: r.CreatedAt,
+		ID:        r.ID,
+	}
+}
+
+// Encode returns an opaque token that can be handed to clients.
+func (c *ReviewCursor) Encode() string {
+	raw := c.CreatedAt.UTC().Format(time.RFC3339Nano) + "|" + c.ID.String()
+
+	return base64.RawURLEncoding.EncodeToString([]byte(raw))
+}
+
+func DecodeReviewCursor(token string) (*ReviewCursor, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAtStr, idStr, ok := strings.Cut(string(raw), "|")
+	if !ok {
+		return nil, ErrInvalidCursor
+	}
+
+	createdAt, err := time.Parse(time.RFC3339Nano, createdAtStr)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	id, err := uuid.Parse(idStr)
+	if err != nil {
+		return nil, ErrInvalidCursor
+	}
+
+	return &ReviewCursor{
+		CreatedAt: createdAt,
+		ID:        id,
+	}, nil
+}
@@ -0,0 +1,289 @@
+package model
+
+import (
+	"slices"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxEditProposalNoteLength = 255
+
+// ShopField is a field of a shop that can be changed by an edit proposal.
+type ShopField string
+
+// Values of the fields are string for name, address and post_code,
+// [2]float64{latitude, longitude} for location, []string for payment_methods
+// and []uuid.UUID for stations and tags.
+const (
+	ShopFieldName           ShopField = "name"
+	ShopFieldAddress        ShopField = "address"
+	ShopFieldPostCode       ShopField = "post_code"
+	ShopFieldLocation       ShopField = "location"
+	ShopFieldPaymentMethods ShopField = "payment_methods"
+	ShopFieldStations       ShopField = "stations"
+	ShopFieldTags           ShopField = "tags"
+)
+
+type EditProposalStatus string
+
+const (
+	EditProposalPending  EditProposalStatus = "pending"
+	EditProposalAccepted EditProposalStatus = "accepted"
+	EditProposalRejected EditProposalStatus = "rejected"
+)
+
+func ParseEditProposalStatus(status string) (EditProposalStatus, error) {
+	switch EditProposalStatus(status) {
+	case EditProposalPending, EditProposalAccepted, EditProposalRejected:
+		return EditProposalStatus(status), nil
+	default:
+		return "", ErrInvalidEditProposal
+	}
+}
+
+// FieldChange is the change of one field. Old is the value the proposer saw,
+// so the change conflicts when the field has been changed since.
+type FieldChange struct {
+	Field ShopField
+	Old   any
+	New   any
+}
+
+// NewFieldChange proposes to set field of shop to value.
+func NewFieldChange(shop *Shop, field ShopField, value any) (FieldChange, error) {
+	old, err := shop.FieldValue(field)
+	if err != nil {
+		return FieldChange{}, err
+	}
+
+	updated := *shop
+	if err := updated.SetFieldValue(field, value); err != nil {
+		return FieldChange{}, err
+	}
+	if equalFieldValues(old, value) {
+		return FieldChange{}, ErrInvalidEditProposal
+	}
+
+	return FieldChange{Field: field, Old: old, New: value}, nil
+}
+
+// EditProposal is a change to a shop suggested by a user who cannot edit the
+// shop directly. The registerer or a moderator accepts or rejects it.
+type EditProposal struct {
+	ID         uuid.UUID
+	Shop       uuid.UUID
+	Proposer   UserID
+	Changes    []FieldChange
+	Note       string
+	Status     EditProposalStatus
+	Reviewer   UserID // 承認・却下したユーザー。未処理の場合は空
+	ReviewNote string
+	CreatedAt  time.Time
+	ReviewedAt time.Time // 未処理の場合はゼロ値
+}
+
+func NewEditProposal(shop *Shop, proposer UserID, changes []FieldChange, note string) (*EditProposal, error) {
+	if len(changes) == 0 || utf8.RuneCountInString(note) > maxEditProposalNoteLength {
+		return nil, ErrInvalidEditProposal
+	}
+	for i, change := range changes {
+		if slices.ContainsFunc(changes[:i], func(c FieldChange) bool { return c.Field == change.Field }) {
+			return nil, ErrInvalidEditProposal
+		}
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &EditProposal{
+		ID:        id,
+		Shop:      shop.ID,
+		Proposer:  proposer,
+		Changes:   changes,
+		Note:      note,
+		Status:    EditProposalPending,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Conflicts returns the fields of shop that no longer have the value the
+// proposal was based on.
+func (p *EditProposal) Conflicts(shop *Shop) []ShopField {
+	var conflicts []ShopField
+	for _, change := range p.Changes {
+		current, err := shop.FieldValue(change.Field)
+		if err != nil || !equalFieldValues(current, change.Old) {
+			conflicts = append(conflicts, change.Field)
+		}
+	}
+
+	return conflicts
+}
+
+// Accept applies the proposal to shop. It fails with ErrEditConflict when
+// one of the fields has been changed since the proposal was made, in which
+// case shop is left untouched.
+func (p *EditProposal) Accept(shop *Shop, reviewer UserID, note string) error {
+	if err := p.review(reviewer, note); err != nil {
+		return err
+	}
+	if len(p.Conflicts(shop)) > 0 {
+		return ErrEditConflict
+	}
+
+	updated := *shop
+	for _, change := range p.Changes {
+		if err := updated.SetFieldValue(change.Field, change.New); err != nil {
+			return err
+		}
+	}
+	updated.UpdatedAt = time.Now()
+	*shop = updated
+
+	p.Status = EditProposalAccepted
+	p.Reviewer = reviewer
+	p.ReviewNote = note
+	p.ReviewedAt = updated.UpdatedAt
+
+	return nil
+}
+
+func (p *EditProposal) Reject(reviewer UserID, note string) error {
+	if err := p.review(reviewer, note); err != nil {
+		return err
+	}
+
+	p.Status = EditProposalRejected
+	p.Reviewer = reviewer
+	p.ReviewNote = note
+	p.ReviewedAt = time.Now()
+
+	return nil
+}
+
+func (p *EditProposal) review(reviewer UserID, note string) error {
+	if p.Status != EditProposalPending {
+		return ErrEditProposalClosed
+	}
+	if reviewer == "" || utf8.RuneCountInString(note) > maxEditProposalNoteLength {
+		return ErrInvalidEditProposal
+	}
+
+	return nil
+}
+
+// FieldValue returns the current value of field.
+func (s *Shop) FieldValue(field ShopField) (any, error) {
+	switch field {
+	case ShopFieldName:
+		return string(s.Name), nil
+	case ShopFieldAddress:
+		return s.Address, nil
+	case ShopFieldPostCode:
+		return string(s.PostCode), nil
+	case ShopFieldLocation:
+		return [2]float64{s.Latitude, s.Longitude}, nil
+	case ShopFieldPaymentMethods:
+		return slices.Clone(s.PaymentMethods), nil
+	case ShopFieldStations:
+		return slices.Clone(s.Stations), nil
+	case ShopFieldTags:
+		return slices.Clone(s.Tags), nil
+	default:
+		return nil, ErrInvalidEditProposal
+	}
+}
+
+// SetFieldValue validates value and sets field to it.
+func (s *Shop) SetFieldValue(field ShopField, value any) error {
+	var ok bool
+	switch field {
+	case ShopFieldName:
+		var name string
+		if name, ok = value.(string); ok {
+			shopName, err := NewShopName(name)
+			if err != nil {
+				return err
+			}
+			s.Name = shopName
+		}
+	case ShopFieldAddress:
+		s.Address, ok = value.(string)
+	case ShopFieldPostCode:
+		var code string
+		if code, ok = value.(string); ok {
+			postCode, err := NewPostCode(code)
+			if err != nil {
+				return err
+			}
+			s.PostCode = postCode
+		}
+	case ShopFieldLocation:
+		var location [2]float64
+		if location, ok = value.([2]float64); ok {
+			if location[0] < -90 || location[0] > 90 || location[1] < -180 || location[1] > 180 {
+				return ErrInvalidEditProposal
+			}
+			s.Latitude, s.Longitude = location[0], location[1]
+		}
+	case ShopFieldPaymentMethods:
+		var methods []string
+		if methods, ok = value.([]string); ok {
+			s.PaymentMethods = slices.Clone(methods)
+		}
+	case ShopFieldStations:
+		var stations []uuid.UUID
+		if stations, ok = value.([]uuid.UUID); ok {
+			s.Stations = slices.Clone(stations)
+		}
+	case ShopFieldTags:
+		var tags []uuid.UUID
+		if tags, ok = value.([]uuid.UUID); ok {
+			s.Tags = slices.Clone(tags)
+		}
+	}
+	if !ok {
+		return ErrInvalidEditProposal
+	}
+
+	return nil
+}
+
+// equalFieldValues compares two values of the same field. Lists are compared
+// regardless of order.
+func equalFieldValues(a, b any) bool {
+	switch a := a.(type) {
+	case []string:
+		b, ok := b.([]string)
+
+		return ok && sameElements(a, b)
+	case []uuid.UUID:
+		b, ok := b.([]uuid.UUID)
+
+		return ok && sameElements(a, b)
+	default:
+		return a == b
+	}
+}
+
+func sameElements[T comparable](a, b []T) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	counts := make(map[T]int, len(a))
+	for _, v := range a {
+		counts[v]++
+	}
+	for _, v := range b {
+		if counts[v] == 0 {
+			return false
+		}
+		counts[v]--
+	}
+
+	return true
+}
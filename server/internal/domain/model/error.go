@@ -11,3 +11,49 @@ var ErrInvalidUserID = errors.New("invalid UserID")
 var ErrInvalidShopName = errors.New("invalid ShopName")
 
 var ErrInvalidStationName = errors.New("invalid StationName")
+
+var ErrInvalidCursor = errors.New("invalid Cursor")
+
+var ErrInvalidDisplayName = errors.New("invalid DisplayName")
+
+var ErrInvalidWebhookURL = errors.New("invalid WebhookURL")
+
+var ErrInvalidWebhookEvent = errors.New("invalid WebhookEvent")
+
+var ErrInvalidBusinessHours = errors.New("invalid BusinessHours")
+
+var ErrInvalidOpeningHours = errors.New("invalid OpeningHours")
+
+var ErrInvalidMenuItemName = errors.New("invalid MenuItemName")
+
+var ErrInvalidPrice = errors.New("invalid Price")
+
+var ErrInvalidTagName = errors.New("invalid TagName")
+
+var ErrInvalidTagParent = errors.New("invalid Tag parent")
+
+var ErrInvalidTagKind = errors.New("invalid TagKind")
+
+var ErrInvalidPaymentMethod = errors.New("invalid PaymentMethod")
+
+var ErrInvalidShopStatus = errors.New("invalid ShopStatus")
+
+var ErrInvalidClosureReportNote = errors.New("invalid ClosureReport note")
+
+var ErrInvalidShopMerge = errors.New("invalid ShopMerge")
+
+var ErrInvalidEditProposal = errors.New("invalid EditProposal")
+
+var ErrEditProposalClosed = errors.New("edit proposal already reviewed")
+
+var ErrEditConflict = errors.New("shop changed since the edit was proposed")
+
+var ErrInvalidReviewReport = errors.New("invalid ReviewReport")
+
+var ErrInvalidModerationAction = errors.New("invalid ModerationAction")
+
+var ErrInvalidIdempotencyKey = errors.New("invalid Idempotency-Key")
+
+var ErrInvalidAuditLog = errors.New("invalid AuditLog")
+
+var ErrWebhookInactive = errors.New("webhook is inactive")
@@ -0,0 +1,85 @@
+package model
+
+import (
+	"encoding/base64"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+type EventType string
+
+const (
+	EventShopCreated    EventType = "shop.created"
+	EventShopUpdated    EventType = "shop.updated"
+	EventReviewCreated  EventType = "review.created"
+	EventReviewUpdated  EventType = "review.updated"
+	EventImageAdded     EventType = "image.added"
+	EventStationCreated EventType = "station.created"
+	EventStationUpdated EventType = "station.updated"
+)
+
+// Event is an entry of the events outbox. Events are written in the same
+// transaction as the change they describe, so the outbox can be replayed to
+// rebuild the activity feed or notify other systems.
+//
+// Related IDs are uuid.Nil when they do not apply to the event.
+type Event struct {
+	ID        uuid.UUID
+	Type      EventType
+	Actor     UserID
+	Shop      uuid.UUID
+	Review    uuid.UUID
+	Station   uuid.UUID
+	Image     uuid.UUID
+	Payload   map[string]any
+	CreatedAt time.Time
+}
+
+func NewEvent(eventType EventType, actor UserID, payload map[string]any) (*Event, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Event{
+		ID:        id,
+		Type:      eventType,
+		Actor:     actor,
+		Payload:   payload,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// RelatedStations returns the stations an event concerns: the station itself
+// for station events, and the stations of the shop recorded in the payload
+// under "stations" for shop and review events. Only events that have not been
+// round-tripped through JSON carry typed station IDs.
+func (e *Event) RelatedStations() []uuid.UUID {
+	stations, _ := e.Payload["stations"].([]uuid.UUID)
+	if e.Station != uuid.Nil {
+		stations = append([]uuid.UUID{e.Station}, stations...)
+	}
+
+	return stations
+}
+
+// EncodeEventCursor returns an opaque pagination token for the given event.
+// Event IDs are UUIDv7 and therefore already ordered by creation time.
+func EncodeEventCursor(id uuid.UUID) string {
+	return base64.RawURLEncoding.EncodeToString(id[:])
+}
+
+func DecodeEventCursor(token string) (uuid.UUID, error) {
+	raw, err := base64.RawURLEncoding.DecodeString(token)
+	if err != nil {
+		return uuid.Nil, ErrInvalidCursor
+	}
+
+	id, err := uuid.FromBytes(raw)
+	if err != nil {
+		return uuid.Nil, ErrInvalidCursor
+	}
+
+	return id, nil
+}
@@ -0,0 +1,71 @@
+package model
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyRecord is the response to a request sent with an
+// Idempotency-Key header, kept so that retries of the request get the same
+// response instead of repeating it.
+type IdempotencyRecord struct {
+	User UserID
+	Key  string
+	// ReservationID identifies the request that reserved the key, so that a
+	// request whose lease expired cannot overwrite the response of a retry.
+	ReservationID uuid.UUID
+	// RequestHash identifies the request, so that the key cannot be reused
+	// for a different one.
+	RequestHash string
+	// StatusCode is 0 while the first request is still in progress.
+	StatusCode  int
+	ContentType string
+	Body        []byte
+	CreatedAt   time.Time
+	// ExpiresAt is when the key can be used again. While the request is in
+	// progress it is a lease, so a crashed request does not lock the key.
+	ExpiresAt time.Time
+}
+
+func NewIdempotencyRecord(user UserID, key string, requestHash string, lease time.Duration) (*IdempotencyRecord, error) {
+	if key == "" || len(key) > maxIdempotencyKeyLength {
+		return nil, ErrInvalidIdempotencyKey
+	}
+	// ヘッダーに書ける表示可能なASCII文字のみ
+	for i := range len(key) {
+		if key[i] < 0x20 || key[i] > 0x7e {
+			return nil, ErrInvalidIdempotencyKey
+		}
+	}
+
+	reservationID, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+
+	return &IdempotencyRecord{
+		User:          user,
+		Key:           key,
+		ReservationID: reservationID,
+		RequestHash:   requestHash,
+		CreatedAt:     now,
+		ExpiresAt:     now.Add(lease),
+	}, nil
+}
+
+func (r *IdempotencyRecord) Completed() bool {
+	return r.StatusCode != 0
+}
+
+// Complete records the response of the request, kept for ttl.
+func (r *IdempotencyRecord) Complete(statusCode int, contentType string, body []byte, ttl time.Duration) {
+	r.StatusCode = statusCode
+	r.ContentType = contentType
+	r.Body = body
+	r.ExpiresAt = time.Now().Add(ttl)
+}
@@ -0,0 +1,74 @@
+package model
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxMenuItemNameLength = 64
+
+type MenuItem struct {
+	ID        uuid.UUID
+	Shop      uuid.UUID
+	Name      MenuItemName
+	Price     Price
+	Category  string
+	Image     *ImageFile // 画像が登録されていない場合はnil
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewMenuItem(shop uuid.UUID, name MenuItemName, price Price, category string, image *ImageFile) (*MenuItem, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &MenuItem{
+		ID:        id,
+		Shop:      shop,
+		Name:      name,
+		Price:     price,
+		Category:  category,
+		Image:     image,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+type MenuItemName string
+
+func NewMenuItemName(name string) (MenuItemName, error) {
+	if name == "" || utf8.RuneCountInString(name) > maxMenuItemNameLength {
+		return "", ErrInvalidMenuItemName
+	}
+
+	return MenuItemName(name), nil
+}
+
+// Price is a price in yen, tax included.
+type Price int
+
+func NewPrice(yen int) (Price, error) {
+	if yen < 0 {
+		return 0, ErrInvalidPrice
+	}
+
+	return Price(yen), nil
+}
+
+// DishRating is the rating a review gives to a menu item the author ate.
+type DishRating struct {
+	MenuItem uuid.UUID
+	Rating   Rating
+}
+
+// RecommendedDish is a menu item ranked by the ratings of the reviews that
+// mention it.
+type RecommendedDish struct {
+	Item    *MenuItem
+	Average float64
+	Count   int
+}
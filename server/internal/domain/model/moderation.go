@@ -0,0 +1,138 @@
+package model
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxModerationNoteLength = 255
+
+// SystemReporter is the reporter of the reports filed automatically when
+// content is held for moderation.
+const SystemReporter UserID = "system"
+
+type ReportReason string
+
+const (
+	ReportReasonSpam         ReportReason = "spam"
+	ReportReasonOffensive    ReportReason = "offensive"
+	ReportReasonOffTopic     ReportReason = "off_topic"
+	ReportReasonPersonalInfo ReportReason = "personal_info"
+	ReportReasonOther        ReportReason = "other"
+)
+
+func ParseReportReason(reason string) (ReportReason, error) {
+	switch ReportReason(reason) {
+	case ReportReasonSpam, ReportReasonOffensive, ReportReasonOffTopic, ReportReasonPersonalInfo, ReportReasonOther:
+		return ReportReason(reason), nil
+	default:
+		return "", ErrInvalidReviewReport
+	}
+}
+
+// ReviewReport is a user's report that a review is inappropriate. It stays
+// open until a moderator acts on the review.
+type ReviewReport struct {
+	ID         uuid.UUID
+	Review     uuid.UUID
+	Reporter   UserID
+	Reason     ReportReason
+	Note       string
+	CreatedAt  time.Time
+	ResolvedAt time.Time // 未処理の場合はゼロ値
+}
+
+func NewReviewReport(review *Review, reporter UserID, reason ReportReason, note string) (*ReviewReport, error) {
+	if _, err := ParseReportReason(string(reason)); err != nil {
+		return nil, err
+	}
+	if review.Author == reporter || utf8.RuneCountInString(note) > maxModerationNoteLength {
+		return nil, ErrInvalidReviewReport
+	}
+	// その他の場合は理由の説明が必要
+	if reason == ReportReasonOther && note == "" {
+		return nil, ErrInvalidReviewReport
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ReviewReport{
+		ID:        id,
+		Review:    review.ID,
+		Reporter:  reporter,
+		Reason:    reason,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+type ModerationAction string
+
+const (
+	// ModerationHide hides the review from everyone but the author and the
+	// moderators.
+	ModerationHide ModerationAction = "hide"
+	// ModerationUnhide makes a hidden review visible again.
+	ModerationUnhide  ModerationAction = "unhide"
+	ModerationDelete  ModerationAction = "delete"
+	ModerationDismiss ModerationAction = "dismiss"
+)
+
+func ParseModerationAction(action string) (ModerationAction, error) {
+	switch ModerationAction(action) {
+	case ModerationHide, ModerationUnhide, ModerationDelete, ModerationDismiss:
+		return ModerationAction(action), nil
+	default:
+		return "", ErrInvalidModerationAction
+	}
+}
+
+// ModerationLog records an action a moderator took on a review. Every open
+// report of the review is resolved by the action.
+type ModerationLog struct {
+	ID        uuid.UUID
+	Review    uuid.UUID
+	Author    UserID // 対象レビューの投稿者。レビューが削除されても残るように記録する
+	Moderator UserID
+	Action    ModerationAction
+	Note      string
+	CreatedAt time.Time
+}
+
+func NewModerationLog(review *Review, moderator UserID, action ModerationAction, note string) (*ModerationLog, error) {
+	if _, err := ParseModerationAction(string(action)); err != nil {
+		return nil, err
+	}
+	if moderator == "" || utf8.RuneCountInString(note) > maxModerationNoteLength {
+		return nil, ErrInvalidModerationAction
+	}
+	if (action == ModerationHide && review.Hidden) || (action == ModerationUnhide && !review.Hidden) {
+		return nil, ErrInvalidModerationAction
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ModerationLog{
+		ID:        id,
+		Review:    review.ID,
+		Author:    review.Author,
+		Moderator: moderator,
+		Action:    action,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// CanView reports whether the user may see the review. Hidden reviews are
+// only visible to their author and moderators.
+func (r *Review) CanView(userID UserID, moderator bool) bool {
+	return !r.Hidden || moderator || r.Author == userID
+}
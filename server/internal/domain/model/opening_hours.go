@@ -0,0 +1,204 @@
+package model
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var osmWeekdays = map[string]time.Weekday{
+	"Su": time.Sunday,
+	"Mo": time.Monday,
+	"Tu": time.Tuesday,
+	"We": time.Wednesday,
+	"Th": time.Thursday,
+	"Fr": time.Friday,
+	"Sa": time.Saturday,
+}
+
+// OSMWeekday parses a two-letter weekday abbreviation such as "Mo".
+func OSMWeekday(name string) (time.Weekday, bool) {
+	d, ok := osmWeekdays[name]
+
+	return d, ok
+}
+
+// OSMWeekdayName returns the two-letter abbreviation of d.
+func OSMWeekdayName(d time.Weekday) string {
+	return d.String()[:2]
+}
+
+var osmMonths = map[string]time.Month{
+	"Jan": time.January, "Feb": time.February, "Mar": time.March, "Apr": time.April,
+	"May": time.May, "Jun": time.June, "Jul": time.July, "Aug": time.August,
+	"Sep": time.September, "Oct": time.October, "Nov": time.November, "Dec": time.December,
+}
+
+var (
+	osmWeekdaySelectorRegex = regexp.MustCompile(`^(Mo|Tu|We|Th|Fr|Sa|Su)(-(Mo|Tu|We|Th|Fr|Sa|Su))?(,(Mo|Tu|We|Th|Fr|Sa|Su)(-(Mo|Tu|We|Th|Fr|Sa|Su))?)*$`)
+	osmTimeRegex            = regexp.MustCompile(`^(\d{1,2}):(\d{2})-(\d{1,2}):(\d{2})$`)
+	// 例: 2026 Dec 31 off, 2026 Dec 29-31 closed "年末休業"
+	osmClosureRegex = regexp.MustCompile(`^(\d{4}) ([A-Z][a-z]{2}) (\d{1,2})(?:-(\d{1,2}))? (?:off|closed)(?: "([^"]*)")?$`)
+)
+
+// ParseOpeningHours imports the subset of the OpenStreetMap opening_hours
+// syntax that BusinessHours can represent:
+//
+//	24/7
+//	Mo-Fr 11:00-14:00,17:00-22:00; Sa 11:00-15:00; Su off
+//	Fr-Sa 18:00-02:00
+//	2026 Dec 31 off; 2026 Dec 29-31 closed "年末休業"
+//
+// Rules are separated by ";" and, as in OSM, a later rule replaces the hours
+// of the weekdays it mentions. Public holiday, month and other selectors are
+// rejected with ErrInvalidOpeningHours.
+func ParseOpeningHours(s string) (*BusinessHours, error) {
+	s = strings.TrimSpace(s)
+	if s == "" {
+		return nil, fmt.Errorf("%w: empty", ErrInvalidOpeningHours)
+	}
+
+	week := make(map[time.Weekday][]TimeRange)
+	var closures []Closure
+
+	for _, rule := range strings.Split(s, ";") {
+		rule = strings.Join(strings.Fields(rule), " ")
+		if rule == "" {
+			continue
+		}
+
+		if m := osmClosureRegex.FindStringSubmatch(rule); m != nil {
+			c, err := parseOSMClosures(m)
+			if err != nil {
+				return nil, err
+			}
+			closures = append(closures, c...)
+
+			continue
+		}
+
+		days := []time.Weekday{
+			time.Sunday, time.Monday, time.Tuesday, time.Wednesday,
+			time.Thursday, time.Friday, time.Saturday,
+		}
+		selector, rest, _ := strings.Cut(rule, " ")
+		if osmWeekdaySelectorRegex.MatchString(selector) {
+			days = parseOSMWeekdays(selector)
+		} else {
+			rest = rule
+		}
+
+		// "Mo 11:00-14:00, 17:00-22:00" のようにカンマの後に空白があってもよい
+		rest = strings.ReplaceAll(rest, " ", "")
+
+		var ranges []TimeRange
+		switch rest {
+		case "off", "closed":
+		case "24/7":
+			for _, day := range days {
+				ranges = append(ranges, TimeRange{Weekday: day, Start: 0, End: minutesPerDay})
+			}
+		default:
+			for _, span := range strings.Split(rest, ",") {
+				start, end, err := parseOSMTimeSpan(span)
+				if err != nil {
+					return nil, fmt.Errorf("%w: %q", ErrInvalidOpeningHours, rule)
+				}
+				for _, day := range days {
+					r, err := NewTimeRange(day, start, end)
+					if err != nil {
+						return nil, fmt.Errorf("%w: %q", ErrInvalidOpeningHours, rule)
+					}
+					ranges = append(ranges, r)
+				}
+			}
+		}
+
+		for _, day := range days {
+			week[day] = nil
+		}
+		for _, r := range ranges {
+			week[r.Weekday] = append(week[r.Weekday], r)
+		}
+	}
+
+	var ranges []TimeRange
+	for _, rs := range week {
+		ranges = append(ranges, rs...)
+	}
+
+	return NewBusinessHours(ranges, closures), nil
+}
+
+// parseOSMWeekdays expands a selector such as "Mo-Fr,Su". Ranges may wrap
+// around the end of the week, e.g. "Fr-Mo".
+func parseOSMWeekdays(selector string) []time.Weekday {
+	var days []time.Weekday
+	for _, part := range strings.Split(selector, ",") {
+		from, to, isRange := strings.Cut(part, "-")
+		if !isRange {
+			days = append(days, osmWeekdays[from])
+
+			continue
+		}
+
+		for d := osmWeekdays[from]; ; d = (d + 1) % 7 {
+			days = append(days, d)
+			if d == osmWeekdays[to] {
+				break
+			}
+		}
+	}
+
+	return days
+}
+
+func parseOSMTimeSpan(span string) (start, end int, err error) {
+	m := osmTimeRegex.FindStringSubmatch(span)
+	if m == nil {
+		return 0, 0, ErrInvalidOpeningHours
+	}
+
+	minutes := func(h, mm string) int {
+		hour, _ := strconv.Atoi(h)
+		minute, _ := strconv.Atoi(mm)
+
+		return hour*60 + minute
+	}
+
+	start, end = minutes(m[1], m[2]), minutes(m[3], m[4])
+	if m[2] >= "60" || m[4] >= "60" || start >= minutesPerDay || end > 2*minutesPerDay {
+		return 0, 0, ErrInvalidOpeningHours
+	}
+
+	return start, end, nil
+}
+
+func parseOSMClosures(m []string) ([]Closure, error) {
+	year, _ := strconv.Atoi(m[1])
+	month, ok := osmMonths[m[2]]
+	if !ok {
+		return nil, fmt.Errorf("%w: unknown month %q", ErrInvalidOpeningHours, m[2])
+	}
+
+	from, _ := strconv.Atoi(m[3])
+	to := from
+	if m[4] != "" {
+		to, _ = strconv.Atoi(m[4])
+	}
+
+	first := time.Date(year, month, from, 0, 0, 0, 0, Tokyo)
+	last := time.Date(year, month, to, 0, 0, 0, 0, Tokyo)
+	if first.Day() != from || last.Day() != to || last.Before(first) {
+		return nil, fmt.Errorf("%w: invalid date %q", ErrInvalidOpeningHours, m[0])
+	}
+
+	var closures []Closure
+	for d := first; !d.After(last); d = d.AddDate(0, 0, 1) {
+		closures = append(closures, NewClosure(d, m[5]))
+	}
+
+	return closures, nil
+}
@@ -0,0 +1,89 @@
+package model
+
+import (
+	"fmt"
+
+	"backend/pkg/textnorm"
+)
+
+type PaymentMethodCategory string
+
+const (
+	PaymentMethodCash       PaymentMethodCategory = "cash"
+	PaymentMethodCreditCard PaymentMethodCategory = "credit_card"
+	PaymentMethodQRCode     PaymentMethodCategory = "qr_code"
+	PaymentMethodICCard     PaymentMethodCategory = "ic_card"
+)
+
+// PaymentMethod is an entry of the payment method catalog. Shops store the
+// code, and any of the name and aliases is accepted on input.
+type PaymentMethod struct {
+	Code     string
+	Name     string // 表示名
+	Category PaymentMethodCategory
+	Aliases  []string
+}
+
+// PaymentMethodCatalog resolves free-text payment method names to catalog
+// entries.
+type PaymentMethodCatalog struct {
+	methods []*PaymentMethod
+	byKey   map[string]*PaymentMethod
+}
+
+func NewPaymentMethodCatalog(methods []*PaymentMethod) *PaymentMethodCatalog {
+	catalog := &PaymentMethodCatalog{
+		methods: methods,
+		byKey:   make(map[string]*PaymentMethod),
+	}
+	for _, method := range methods {
+		catalog.byKey[textnorm.Fold(method.Code)] = method
+		catalog.byKey[textnorm.Fold(method.Name)] = method
+		for _, alias := range method.Aliases {
+			catalog.byKey[textnorm.Fold(alias)] = method
+		}
+	}
+
+	return catalog
+}
+
+func (c *PaymentMethodCatalog) Methods() []*PaymentMethod {
+	return c.methods
+}
+
+// Resolve looks up a payment method by its code, name or one of its aliases,
+// ignoring width, case, kana type and separators.
+func (c *PaymentMethodCatalog) Resolve(name string) (*PaymentMethod, bool) {
+	method, ok := c.byKey[textnorm.Fold(name)]
+
+	return method, ok
+}
+
+// Normalize converts names to deduplicated codes in the given order.
+func (c *PaymentMethodCatalog) Normalize(names []string) ([]string, error) {
+	codes := make([]string, 0, len(names))
+	seen := make(map[string]struct{}, len(names))
+	for _, name := range names {
+		method, ok := c.Resolve(name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q", ErrInvalidPaymentMethod, name)
+		}
+		if _, ok := seen[method.Code]; ok {
+			continue
+		}
+		seen[method.Code] = struct{}{}
+		codes = append(codes, method.Code)
+	}
+
+	return codes, nil
+}
+
+// DisplayName returns the name of the payment method with the code, or the
+// code itself for values that predate the catalog.
+func (c *PaymentMethodCatalog) DisplayName(code string) string {
+	if method, ok := c.Resolve(code); ok {
+		return method.Name
+	}
+
+	return code
+}
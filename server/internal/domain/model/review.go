@@ -12,6 +12,8 @@ type Review struct {
 	Rating    Rating
 	Content   string
 	Images    []ImageFile
+	Dishes    []DishRating // 食べたメニューごとの評価
+	Hidden    bool         // モデレーターによって非表示にされたかどうか
 	CreatedAt time.Time
 	UpdatedAt time.Time
 }
@@ -36,6 +38,13 @@ func NewReview(author UserID, shop uuid.UUID, rating Rating, content string, ima
 
 type Rating int
 
+// RatingSummary aggregates the ratings of a shop's reviews.
+type RatingSummary struct {
+	Shop    uuid.UUID
+	Average float64
+	Count   int
+}
+
 func NewRating(value int) (Rating, error) {
 	if value < 0 || value > 3 {
 		return 0, ErrInvalidRating
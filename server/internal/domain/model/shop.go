@@ -18,6 +18,10 @@ type Shop struct {
 	Images         []ImageFile
 	PaymentMethods []string
 	Registerer     UserID
+	BusinessHours  *BusinessHours // 営業時間が未登録の場合はnil
+	Tags           []uuid.UUID    // カテゴリとユーザータグのID
+	Status         ShopStatus
+	MovedTo        uuid.UUID // 移転先の店舗。Statusがmovedの場合のみ
 	CreatedAt      time.Time
 	UpdatedAt      time.Time
 }
@@ -48,6 +52,7 @@ func NewShop(
 		PaymentMethods: paymentMethods,
 		Registerer:     registerer,
 		Stations:       stations,
+		Status:         ShopStatusOpen,
 		CreatedAt:      time.Now(),
 		UpdatedAt:      time.Now(),
 	}, nil
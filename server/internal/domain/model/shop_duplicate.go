@@ -0,0 +1,161 @@
+package model
+
+import (
+	"cmp"
+	"math"
+	"slices"
+
+	"backend/pkg/textnorm"
+)
+
+const (
+	// DuplicateNameThreshold is the minimum name similarity for two shops to be
+	// considered the same.
+	DuplicateNameThreshold = 0.6
+	// DuplicateDistanceMeters is how close two shops must be to be considered
+	// at the same place.
+	DuplicateDistanceMeters = 150.0
+
+	earthRadiusMeters = 6371000.0
+)
+
+// DuplicateCandidate is an existing shop that looks like the same shop as
+// another one.
+type DuplicateCandidate struct {
+	Shop           *Shop
+	NameSimilarity float64
+	Distance       float64 // 2店舗間の距離（m）。どちらかの位置が未登録の場合は負の値
+	SamePostCode   bool
+}
+
+// CompareShops scores how likely a and b are the same shop. ok is true when
+// the names are similar and the shops are near each other or share the post
+// code.
+func CompareShops(a, b *Shop) (candidate DuplicateCandidate, ok bool) {
+	candidate = DuplicateCandidate{
+		Shop:           b,
+		NameSimilarity: NameSimilarity(string(a.Name), string(b.Name)),
+		Distance:       -1,
+		SamePostCode:   a.PostCode != "" && a.PostCode == b.PostCode,
+	}
+
+	if hasLocation(a) && hasLocation(b) {
+		candidate.Distance = DistanceMeters(a.Latitude, a.Longitude, b.Latitude, b.Longitude)
+	}
+
+	near := candidate.Distance >= 0 && candidate.Distance <= DuplicateDistanceMeters
+
+	return candidate, candidate.NameSimilarity >= DuplicateNameThreshold && (near || candidate.SamePostCode)
+}
+
+// FindDuplicateCandidates returns the shops among existing that look like the
+// same shop as shop, most similar names first.
+func FindDuplicateCandidates(shop *Shop, existing []*Shop) []DuplicateCandidate {
+	var candidates []DuplicateCandidate
+	for _, other := range existing {
+		if other.ID == shop.ID {
+			continue
+		}
+		if candidate, ok := CompareShops(shop, other); ok {
+			candidates = append(candidates, candidate)
+		}
+	}
+
+	slices.SortStableFunc(candidates, func(x, y DuplicateCandidate) int {
+		return cmp.Compare(y.NameSimilarity, x.NameSimilarity)
+	})
+
+	return candidates
+}
+
+// ClusterDuplicates groups shops that are likely duplicates of each other,
+// directly or through another shop in the group. Shops without duplicates
+// are left out.
+func ClusterDuplicates(shops []*Shop) [][]*Shop {
+	parent := make([]int, len(shops))
+	for i := range parent {
+		parent[i] = i
+	}
+	var find func(int) int
+	find = func(i int) int {
+		if parent[i] != i {
+			parent[i] = find(parent[i])
+		}
+
+		return parent[i]
+	}
+
+	for i := range shops {
+		for j := i + 1; j < len(shops); j++ {
+			if _, ok := CompareShops(shops[i], shops[j]); ok {
+				parent[find(j)] = find(i)
+			}
+		}
+	}
+
+	groups := make(map[int][]*Shop)
+	var roots []int
+	for i, shop := range shops {
+		root := find(i)
+		if _, ok := groups[root]; !ok {
+			roots = append(roots, root)
+		}
+		groups[root] = append(groups[root], shop)
+	}
+
+	var clusters [][]*Shop
+	for _, root := range roots {
+		if len(groups[root]) > 1 {
+			clusters = append(clusters, groups[root])
+		}
+	}
+
+	return clusters
+}
+
+// NameSimilarity returns the Dice coefficient of the character bigrams of
+// the folded names, from 0 (nothing in common) to 1 (same name).
+func NameSimilarity(a, b string) float64 {
+	ra, rb := []rune(textnorm.Fold(a)), []rune(textnorm.Fold(b))
+	if len(ra) == 0 || len(rb) == 0 {
+		return 0
+	}
+	if string(ra) == string(rb) {
+		return 1
+	}
+	if len(ra) < 2 || len(rb) < 2 {
+		return 0
+	}
+
+	bigrams := make(map[[2]rune]int)
+	for i := 0; i+1 < len(ra); i++ {
+		bigrams[[2]rune{ra[i], ra[i+1]}]++
+	}
+
+	common := 0
+	for i := 0; i+1 < len(rb); i++ {
+		key := [2]rune{rb[i], rb[i+1]}
+		if bigrams[key] > 0 {
+			bigrams[key]--
+			common++
+		}
+	}
+
+	return 2 * float64(common) / float64(len(ra)-1+len(rb)-1)
+}
+
+// DistanceMeters returns the great-circle distance between two points.
+func DistanceMeters(lat1, lon1, lat2, lon2 float64) float64 {
+	toRad := func(deg float64) float64 { return deg * math.Pi / 180 }
+
+	dLat := toRad(lat2 - lat1)
+	dLon := toRad(lon2 - lon1)
+	h := math.Sin(dLat/2)*math.Sin(dLat/2) +
+		math.Cos(toRad(lat1))*math.Cos(toRad(lat2))*math.Sin(dLon/2)*math.Sin(dLon/2)
+
+	return 2 * earthRadiusMeters * math.Asin(math.Sqrt(h))
+}
+
+func hasLocation(shop *Shop) bool {
+	return shop.Latitude != 0 || shop.Longitude != 0
+}
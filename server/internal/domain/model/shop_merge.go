@@ -0,0 +1,81 @@
+package model
+
+import (
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShopMerge records that Source was folded into Target. Source keeps a
+// snapshot of the merged shop because the shop itself is deleted.
+type ShopMerge struct {
+	ID        uuid.UUID
+	Source    *Shop
+	Target    uuid.UUID
+	Actor     UserID
+	CreatedAt time.Time
+}
+
+func NewShopMerge(source, target *Shop, actor UserID) (*ShopMerge, error) {
+	if source.ID == target.ID {
+		return nil, ErrInvalidShopMerge
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ShopMerge{
+		ID:        id,
+		Source:    source,
+		Target:    target.ID,
+		Actor:     actor,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// Absorb adds the stations, images, payment methods and tags of source to
+// the shop without duplicates, and fills in the details the shop is missing.
+func (s *Shop) Absorb(source *Shop) {
+	s.Stations = appendMissing(s.Stations, source.Stations...)
+	s.PaymentMethods = appendMissing(s.PaymentMethods, source.PaymentMethods...)
+	s.Tags = appendMissing(s.Tags, source.Tags...)
+	for _, image := range source.Images {
+		if !slices.ContainsFunc(s.Images, func(i ImageFile) bool { return i.ID == image.ID }) {
+			s.Images = append(s.Images, image)
+		}
+	}
+
+	if s.Address == "" {
+		s.Address = source.Address
+	}
+	if s.PostCode == "" {
+		s.PostCode = source.PostCode
+	}
+	if !hasLocation(s) {
+		s.Latitude, s.Longitude = source.Latitude, source.Longitude
+	}
+	if s.BusinessHours == nil {
+		s.BusinessHours = source.BusinessHours
+	}
+
+	// 移転先が統合元の店舗だった場合、統合先そのものが移転先になるため営業中に戻す
+	if s.MovedTo == source.ID {
+		s.Status = ShopStatusOpen
+		s.MovedTo = uuid.Nil
+	}
+
+	s.UpdatedAt = time.Now()
+}
+
+func appendMissing[T comparable](values []T, others ...T) []T {
+	for _, v := range others {
+		if !slices.Contains(values, v) {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
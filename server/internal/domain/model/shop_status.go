@@ -0,0 +1,139 @@
+package model
+
+import (
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+// ClosureReportWindow is how long closure reports count towards changing the
+// status of a shop.
+const ClosureReportWindow = 60 * 24 * time.Hour
+
+const maxClosureReportNoteLength = 255
+
+type ShopStatus string
+
+const (
+	ShopStatusOpen              ShopStatus = "open"
+	ShopStatusTemporarilyClosed ShopStatus = "temporarily_closed"
+	ShopStatusPermanentlyClosed ShopStatus = "permanently_closed"
+	// ShopStatusMoved means the shop moved to another address, registered as
+	// the successor shop.
+	ShopStatusMoved ShopStatus = "moved"
+)
+
+func ParseShopStatus(status string) (ShopStatus, error) {
+	switch ShopStatus(status) {
+	case ShopStatusOpen, ShopStatusTemporarilyClosed, ShopStatusPermanentlyClosed, ShopStatusMoved:
+		return ShopStatus(status), nil
+	default:
+		return "", ErrInvalidShopStatus
+	}
+}
+
+// IsClosed reports whether the shop cannot be visited at its address.
+func (s ShopStatus) IsClosed() bool {
+	return s != ShopStatusOpen
+}
+
+// validateStatus checks that movedTo is set exactly when the status is moved.
+func validateStatus(shopID uuid.UUID, status ShopStatus, movedTo uuid.UUID) error {
+	if _, err := ParseShopStatus(string(status)); err != nil {
+		return err
+	}
+
+	if (status == ShopStatusMoved) != (movedTo != uuid.Nil) || movedTo == shopID {
+		return ErrInvalidShopStatus
+	}
+
+	return nil
+}
+
+// SetStatus changes the lifecycle status of the shop. movedTo is the
+// successor shop and must be given only for ShopStatusMoved.
+func (s *Shop) SetStatus(status ShopStatus, movedTo uuid.UUID) error {
+	if err := validateStatus(s.ID, status, movedTo); err != nil {
+		return err
+	}
+
+	s.Status = status
+	s.MovedTo = movedTo
+
+	return nil
+}
+
+// ClosureReport is a user's report that a shop changed its status.
+type ClosureReport struct {
+	ID         uuid.UUID
+	Shop       uuid.UUID
+	Reporter   UserID
+	Status     ShopStatus
+	MovedTo    uuid.UUID // 移転先の店舗。Statusがmovedの場合のみ
+	Note       string
+	CreatedAt  time.Time
+	ResolvedAt time.Time // 店舗の状態に反映されたか取り下げられた日時。未処理の場合はゼロ値
+}
+
+func NewClosureReport(shop uuid.UUID, reporter UserID, status ShopStatus, movedTo uuid.UUID, note string) (*ClosureReport, error) {
+	if err := validateStatus(shop, status, movedTo); err != nil {
+		return nil, err
+	}
+
+	if utf8.RuneCountInString(note) > maxClosureReportNoteLength {
+		return nil, ErrInvalidClosureReportNote
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &ClosureReport{
+		ID:        id,
+		Shop:      shop,
+		Reporter:  reporter,
+		Status:    status,
+		MovedTo:   movedTo,
+		Note:      note,
+		CreatedAt: time.Now(),
+	}, nil
+}
+
+// DecideShopStatus returns the status that at least threshold distinct users
+// agree on, when it differs from the current one. Reports for a move only
+// agree when they name the same successor. When several outcomes reach the
+// threshold, the one with the most reporters wins.
+func DecideShopStatus(shop *Shop, reports []*ClosureReport, threshold int) (ShopStatus, uuid.UUID, bool) {
+	type outcome struct {
+		status  ShopStatus
+		movedTo uuid.UUID
+	}
+
+	reporters := make(map[outcome]map[UserID]struct{})
+	for _, report := range reports {
+		key := outcome{report.Status, report.MovedTo}
+		if reporters[key] == nil {
+			reporters[key] = make(map[UserID]struct{})
+		}
+		reporters[key][report.Reporter] = struct{}{}
+	}
+
+	var best outcome
+	bestCount := 0
+	for key, users := range reporters {
+		if key.status == shop.Status && key.movedTo == shop.MovedTo {
+			continue
+		}
+		if len(users) >= threshold && len(users) > bestCount {
+			best, bestCount = key, len(users)
+		}
+	}
+
+	if bestCount == 0 {
+		return shop.Status, shop.MovedTo, false
+	}
+
+	return best.status, best.movedTo, true
+}
@@ -0,0 +1,147 @@
+package model
+
+import (
+	"strings"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+)
+
+const maxTagNameLength = 32
+
+type TagKind string
+
+const (
+	// TagKindCategory is a managed category such as a cuisine type. Categories
+	// form a hierarchy and only admins can create or change them.
+	TagKindCategory TagKind = "category"
+	// TagKindUser is a free-form tag anyone can attach to a shop.
+	TagKindUser TagKind = "user"
+)
+
+type Tag struct {
+	ID         uuid.UUID
+	Name       TagName
+	Kind       TagKind
+	Parent     uuid.UUID // 親カテゴリ。最上位のカテゴリとユーザータグはuuid.Nil
+	UsageCount int       // タグが付いている店舗数。読み込み時のみ設定される
+	CreatedBy  UserID
+	CreatedAt  time.Time
+	UpdatedAt  time.Time
+}
+
+func NewCategory(name TagName, parent uuid.UUID, createdBy UserID) (*Tag, error) {
+	return newTag(name, TagKindCategory, parent, createdBy)
+}
+
+func NewUserTag(name TagName, createdBy UserID) (*Tag, error) {
+	return newTag(name, TagKindUser, uuid.Nil, createdBy)
+}
+
+func newTag(name TagName, kind TagKind, parent uuid.UUID, createdBy UserID) (*Tag, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &Tag{
+		ID:        id,
+		Name:      name,
+		Kind:      kind,
+		Parent:    parent,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+type TagName string
+
+func NewTagName(name string) (TagName, error) {
+	name = strings.TrimSpace(name)
+	if name == "" || utf8.RuneCountInString(name) > maxTagNameLength {
+		return "", ErrInvalidTagName
+	}
+
+	return TagName(name), nil
+}
+
+func ParseTagKind(kind string) (TagKind, error) {
+	switch TagKind(kind) {
+	case TagKindCategory, TagKindUser:
+		return TagKind(kind), nil
+	default:
+		return "", ErrInvalidTagKind
+	}
+}
+
+// TagTree indexes the parent-child relations of a set of tags.
+type TagTree struct {
+	tags     map[uuid.UUID]*Tag
+	children map[uuid.UUID][]uuid.UUID
+}
+
+func NewTagTree(tags []*Tag) *TagTree {
+	tree := &TagTree{
+		tags:     make(map[uuid.UUID]*Tag, len(tags)),
+		children: make(map[uuid.UUID][]uuid.UUID),
+	}
+	for _, tag := range tags {
+		tree.tags[tag.ID] = tag
+		if tag.Parent != uuid.Nil {
+			tree.children[tag.Parent] = append(tree.children[tag.Parent], tag.ID)
+		}
+	}
+
+	return tree
+}
+
+func (t *TagTree) Get(id uuid.UUID) (*Tag, bool) {
+	tag, ok := t.tags[id]
+
+	return tag, ok
+}
+
+// Descendants returns id and the IDs of every tag below it, so that filtering
+// by a category also matches shops tagged with its subcategories.
+func (t *TagTree) Descendants(id uuid.UUID) []uuid.UUID {
+	ids := []uuid.UUID{id}
+	seen := map[uuid.UUID]struct{}{id: {}}
+	for i := 0; i < len(ids); i++ {
+		for _, child := range t.children[ids[i]] {
+			if _, ok := seen[child]; ok {
+				continue
+			}
+			seen[child] = struct{}{}
+			ids = append(ids, child)
+		}
+	}
+
+	return ids
+}
+
+// ValidateParent checks that parent can become the parent of tag: it must be
+// an existing category and must not be tag itself or one of its descendants.
+func (t *TagTree) ValidateParent(tag *Tag, parent uuid.UUID) error {
+	if parent == uuid.Nil {
+		return nil
+	}
+
+	if tag.Kind != TagKindCategory {
+		return ErrInvalidTagParent
+	}
+
+	p, ok := t.tags[parent]
+	if !ok || p.Kind != TagKindCategory {
+		return ErrInvalidTagParent
+	}
+
+	for _, id := range t.Descendants(tag.ID) {
+		if id == parent {
+			return ErrInvalidTagParent
+		}
+	}
+
+	return nil
+}
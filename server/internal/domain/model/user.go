@@ -0,0 +1,49 @@
+package model
+
+import (
+	"time"
+	"unicode/utf8"
+)
+
+const maxDisplayNameLength = 32
+
+type User struct {
+	ID          UserID
+	DisplayName DisplayName
+	Avatar      *ImageFile
+	CreatedAt   time.Time
+	UpdatedAt   time.Time
+}
+
+// NewUser creates a user whose display name defaults to the ID. Users are
+// created lazily the first time they access the API.
+func NewUser(id UserID) *User {
+	return &User{
+		ID:          id,
+		DisplayName: DisplayName(id),
+		CreatedAt:   time.Now(),
+		UpdatedAt:   time.Now(),
+	}
+}
+
+type DisplayName string
+
+func NewDisplayName(name string) (DisplayName, error) {
+	if name == "" || utf8.RuneCountInString(name) > maxDisplayNameLength {
+		return "", ErrInvalidDisplayName
+	}
+
+	return DisplayName(name), nil
+}
+
+// UserProfile is a user together with statistics aggregated from their
+// reviews and registered shops.
+type UserProfile struct {
+	User        *User
+	ReviewCount int
+	ShopCount   int
+	// 評価ごとのレビュー数
+	RatingDistribution map[Rating]int
+	FirstActivityAt    *time.Time
+	LastActivityAt     *time.Time
+}
@@ -0,0 +1,128 @@
+package model
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"net/url"
+	"slices"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// WebhookEvents are the event types webhooks can subscribe to.
+var WebhookEvents = []EventType{
+	EventShopCreated,
+	EventShopUpdated,
+	EventReviewCreated,
+	EventStationCreated,
+}
+
+type Webhook struct {
+	ID  uuid.UUID
+	URL string
+	// 署名（HMAC-SHA256）に使う共有シークレット
+	Secret    string
+	Events    []EventType
+	Active    bool
+	CreatedBy UserID
+	CreatedAt time.Time
+	UpdatedAt time.Time
+}
+
+func NewWebhook(rawURL string, events []EventType, createdBy UserID) (*Webhook, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	if err := ValidateWebhookURL(rawURL); err != nil {
+		return nil, err
+	}
+
+	if err := ValidateWebhookEvents(events); err != nil {
+		return nil, err
+	}
+
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return nil, err
+	}
+
+	return &Webhook{
+		ID:        id,
+		URL:       rawURL,
+		Secret:    hex.EncodeToString(secret),
+		Events:    events,
+		Active:    true,
+		CreatedBy: createdBy,
+		CreatedAt: time.Now(),
+		UpdatedAt: time.Now(),
+	}, nil
+}
+
+func ValidateWebhookURL(rawURL string) error {
+	u, err := url.Parse(rawURL)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return ErrInvalidWebhookURL
+	}
+
+	return nil
+}
+
+func ValidateWebhookEvents(events []EventType) error {
+	if len(events) == 0 {
+		return ErrInvalidWebhookEvent
+	}
+
+	for _, e := range events {
+		if !slices.Contains(WebhookEvents, e) {
+			return ErrInvalidWebhookEvent
+		}
+	}
+
+	return nil
+}
+
+type DeliveryStatus string
+
+const (
+	DeliveryPending   DeliveryStatus = "pending"
+	DeliverySucceeded DeliveryStatus = "succeeded"
+	DeliveryFailed    DeliveryStatus = "failed"
+)
+
+// WebhookDelivery is one queued POST of an event to a webhook. Payload holds
+// the exact bytes that are signed and sent, so retries are byte-identical.
+type WebhookDelivery struct {
+	ID             uuid.UUID
+	Webhook        uuid.UUID
+	Event          EventType
+	Payload        []byte
+	Status         DeliveryStatus
+	Attempts       int
+	NextAttemptAt  time.Time
+	LastError      string
+	ResponseStatus int
+	CreatedAt      time.Time
+	UpdatedAt      time.Time
+	DeliveredAt    *time.Time
+}
+
+func NewWebhookDelivery(webhook uuid.UUID, event EventType, payload []byte) (*WebhookDelivery, error) {
+	id, err := uuid.NewV7()
+	if err != nil {
+		return nil, err
+	}
+
+	return &WebhookDelivery{
+		ID:            id,
+		Webhook:       webhook,
+		Event:         event,
+		Payload:       payload,
+		Status:        DeliveryPending,
+		NextAttemptAt: time.Now(),
+		CreatedAt:     time.Now(),
+		UpdatedAt:     time.Now(),
+	}, nil
+}
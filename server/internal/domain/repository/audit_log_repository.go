@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+// AuditLogFilter narrows down the entries returned by Find. Zero values mean
+// "no condition".
+type AuditLogFilter struct {
+	Actor      model.UserID
+	Action     model.AuditAction
+	EntityType model.AuditEntityType
+	EntityID   uuid.UUID
+	Since      time.Time
+	Until      time.Time
+}
+
+// AuditLogRepository is append-only: entries can never be changed or
+// deleted through it.
+type AuditLogRepository interface {
+	Save(ctx context.Context, log *model.AuditLog) error
+	// Find returns the entries matching filter, newest first.
+	Find(ctx context.Context, filter AuditLogFilter, limit int, offset int) ([]*model.AuditLog, error)
+}
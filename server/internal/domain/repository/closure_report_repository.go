@@ -0,0 +1,17 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+type ClosureReportRepository interface {
+	// Save stores a report. A pending report by the same user for the same
+	// shop is replaced, so each user counts once.
+	Save(ctx context.Context, report *model.ClosureReport) error
+	// FindPending returns the unresolved reports of a shop filed since the
+	// given time, newest first.
+	FindPending(ctx context.Context, shopID uuid.UUID, since time.Time) ([]*model.ClosureReport, error)
+}
@@ -0,0 +1,27 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+type EditProposalRepository interface {
+	Save(ctx context.Context, proposal *model.EditProposal) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.EditProposal, error)
+	// FindByShop returns every proposal of a shop including reviewed ones,
+	// newest first.
+	FindByShop(ctx context.Context, shopID uuid.UUID) ([]*model.EditProposal, error)
+	// FindPending returns the pending proposals, oldest first. When registerer
+	// is not empty only proposals for shops registered by that user are
+	// returned.
+	FindPending(ctx context.Context, registerer model.UserID) ([]*model.EditProposal, error)
+	// Accept saves the accepted proposal together with the shop it was
+	// applied to in one transaction. It fails with ErrConflict when the shop
+	// was updated after base or the proposal was reviewed concurrently.
+	Accept(ctx context.Context, proposal *model.EditProposal, shop *model.Shop, base time.Time) error
+	// Reject saves the rejected proposal. It fails with ErrConflict when the
+	// proposal was reviewed concurrently.
+	Reject(ctx context.Context, proposal *model.EditProposal) error
+}
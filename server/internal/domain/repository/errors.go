@@ -0,0 +1,11 @@
+package repository
+
+import "errors"
+
+// ErrNotFound is wrapped by repository implementations when the requested
+// entity does not exist, so handlers can tell it apart from other failures.
+var ErrNotFound = errors.New("not found")
+
+// ErrConflict is wrapped by repository implementations when saving would
+// violate a uniqueness constraint, such as a name that is already taken.
+var ErrConflict = errors.New("conflict")
@@ -0,0 +1,29 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"github.com/google/uuid"
+)
+
+// EventFilter narrows down the activity feed. Following and FavoritesOf are
+// combined with OR, every other condition with AND.
+type EventFilter struct {
+	Types       []model.EventType
+	Station     uuid.UUID
+	Following   model.UserID
+	FavoritesOf model.UserID
+}
+
+// EventPublisher broadcasts events to live subscribers once the change they
+// describe has been committed.
+type EventPublisher interface {
+	Publish(events ...*model.Event)
+}
+
+type EventRepository interface {
+	// FindFeed returns events newest first, leaving out events of hidden
+	// reviews. When cursor is not uuid.Nil only events older than it are
+	// returned.
+	FindFeed(ctx context.Context, filter EventFilter, cursor uuid.UUID, limit int) ([]*model.Event, error)
+}
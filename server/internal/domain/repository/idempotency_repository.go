@@ -0,0 +1,19 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+)
+
+type IdempotencyRepository interface {
+	// Reserve stores record unless the user already has an unexpired record
+	// with the same key, in which case that record is returned instead.
+	// It returns nil when record was stored.
+	Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error)
+	// Complete stores the response of a reserved record. It returns
+	// ErrConflict when the reservation expired and the key was reserved again.
+	Complete(ctx context.Context, record *model.IdempotencyRecord) error
+	// Release deletes a record still in progress, so that the request can be
+	// retried with the same key. Records reserved by another request are kept.
+	Release(ctx context.Context, record *model.IdempotencyRecord) error
+}
@@ -0,0 +1,18 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"github.com/google/uuid"
+)
+
+type MenuItemRepository interface {
+	Save(ctx context.Context, item *model.MenuItem) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.MenuItem, error)
+	// FindByShop returns the menu of a shop ordered by category and name.
+	FindByShop(ctx context.Context, shopID uuid.UUID) ([]*model.MenuItem, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+	// FindRecommended ranks the menu items of a shop by the dish ratings of
+	// its reviews. Items nobody has rated are not returned.
+	FindRecommended(ctx context.Context, shopID uuid.UUID, limit int) ([]*model.RecommendedDish, error)
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"github.com/google/uuid"
+)
+
+type ModerationRepository interface {
+	// SaveReport stores a report. An open report by the same user for the
+	// same review is replaced, so each user counts once.
+	SaveReport(ctx context.Context, report *model.ReviewReport) error
+	// FindOpenReports returns the unresolved reports, oldest first.
+	FindOpenReports(ctx context.Context) ([]*model.ReviewReport, error)
+	// Moderate applies the action of log to its review, resolves the open
+	// reports of the review and records log, all in one transaction.
+	Moderate(ctx context.Context, log *model.ModerationLog) error
+	// FindLogs returns the moderation log, newest first. When reviewID is not
+	// uuid.Nil only the actions on that review are returned.
+	FindLogs(ctx context.Context, reviewID uuid.UUID, limit int, offset int) ([]*model.ModerationLog, error)
+}
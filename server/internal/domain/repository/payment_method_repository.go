@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+)
+
+type PaymentMethodRepository interface {
+	// FindCatalog returns every payment method with its aliases in display
+	// order.
+	FindCatalog(ctx context.Context) (*model.PaymentMethodCatalog, error)
+}
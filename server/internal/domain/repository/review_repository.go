@@ -7,17 +7,44 @@ import (
 	"time"
 )
 
+// ReviewFilter narrows down the reviews returned by FindRecentReviews and
+// Count. Zero values mean "no condition", except that hidden reviews are
+// excluded unless IncludeHidden is set or Viewer wrote them.
+type ReviewFilter struct {
+	After    time.Time
+	Before   time.Time
+	ShopID   uuid.UUID
+	AuthorID model.UserID
+
+	Viewer        model.UserID
+	IncludeHidden bool
+}
+
 type ReviewRepository interface {
+	// Save stores the review. Hidden reviews are stored without events, so
+	// they reach neither the feed nor the stream.
 	Save(ctx context.Context, user *model.Review) error
+	// SaveHeld stores the review hidden and files report in one transaction,
+	// so that the review waits in the moderation queue without ever being
+	// published.
+	SaveHeld(ctx context.Context, review *model.Review, report *model.ReviewReport) error
 	FindByID(ctx context.Context, id uuid.UUID) (*model.Review, error)
+	// FindRecentReviews returns reviews ordered by (created_at, id) descending.
+	// When cursor is non-nil, only reviews strictly after it are returned and
+	// offset is ignored.
 	FindRecentReviews(
 		ctx context.Context,
-		after time.Time,
-		before time.Time,
+		filter ReviewFilter,
+		cursor *model.ReviewCursor,
 		limit int,
 		offset int,
-		shopID uuid.UUID,
-		authorID model.UserID,
 	) ([]*model.Review, error)
+	Count(ctx context.Context, filter ReviewFilter) (int, error)
+	// SummarizeRatings returns the rating summary of each shop, ignoring
+	// hidden reviews. Shops without reviews are omitted from the map.
+	SummarizeRatings(ctx context.Context, shopIDs []uuid.UUID) (map[uuid.UUID]*model.RatingSummary, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	// Iterate calls fn for every review in ID order, fetching rows in batches.
+	// Hidden reviews are skipped, since the exports built on it are public.
+	Iterate(ctx context.Context, fn func(*model.Review) error) error
 }
@@ -8,8 +8,23 @@ import (
 
 type ShopRepository interface {
 	Save(ctx context.Context, user *model.Shop) error
+	// UpdateStatus saves only the status, successor and update time of shop
+	// and resolves the pending closure reports of the shop, in one
+	// transaction.
+	UpdateStatus(ctx context.Context, shop *model.Shop) error
 	FindByID(ctx context.Context, id uuid.UUID) (*model.Shop, error)
 	FindAll(ctx context.Context) ([]*model.Shop, error)
 	Delete(ctx context.Context, id uuid.UUID) error
 	FindByStation(ctx context.Context, id uuid.UUID) ([]*model.Shop, error)
+	// SearchByName returns shops whose name contains keyword, exact and
+	// prefix matches first.
+	SearchByName(ctx context.Context, keyword string, limit int) ([]*model.Shop, error)
+	// Iterate calls fn for every shop in ID order, fetching rows in batches.
+	Iterate(ctx context.Context, fn func(*model.Shop) error) error
+	// Merge saves target, which has absorbed merge.Source, moves the reviews,
+	// menu items, favorites and history of the source shop to it, deletes the
+	// source shop and leaves a redirect from its ID, all in one transaction.
+	Merge(ctx context.Context, merge *model.ShopMerge, target *model.Shop) error
+	// FindRedirect returns the shop that the merged shop id was folded into.
+	FindRedirect(ctx context.Context, id uuid.UUID) (uuid.UUID, error)
 }
@@ -10,5 +10,10 @@ type StationRepository interface {
 	Save(ctx context.Context, user *model.Station) error
 	FindByID(ctx context.Context, id uuid.UUID) (*model.Station, error)
 	FindAll(ctx context.Context) ([]*model.Station, error)
+	// FindByName returns the station with the given name, falling back to the
+	// shortest name that starts with it. It returns ErrNotFound otherwise.
+	FindByName(ctx context.Context, name string) (*model.Station, error)
 	Delete(ctx context.Context, id uuid.UUID) error
+	// Iterate calls fn for every station in ID order, fetching rows in batches.
+	Iterate(ctx context.Context, fn func(*model.Station) error) error
 }
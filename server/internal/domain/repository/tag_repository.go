@@ -0,0 +1,30 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"github.com/google/uuid"
+)
+
+type TagFilter struct {
+	Kind model.TagKind // 空の場合はすべての種類
+	// Query filters tags whose name contains it.
+	Query string
+}
+
+type TagRepository interface {
+	// Save returns an error wrapping ErrConflict when another tag already has
+	// the same name.
+	Save(ctx context.Context, tag *model.Tag) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Tag, error)
+	FindByName(ctx context.Context, name model.TagName) (*model.Tag, error)
+	// FindAll returns tags with their usage counts, categories first and the
+	// most used tags first within each kind.
+	FindAll(ctx context.Context, filter TagFilter) ([]*model.Tag, error)
+	// Delete removes a tag from every shop. Subcategories of a deleted category
+	// are moved up to its parent.
+	Delete(ctx context.Context, id uuid.UUID) error
+	// Merge moves the shops and subcategories of source to target and deletes
+	// source.
+	Merge(ctx context.Context, source, target uuid.UUID) error
+}
@@ -0,0 +1,12 @@
+package repository
+
+import (
+	"context"
+
+	"github.com/google/uuid"
+)
+
+type TraqRepository interface {
+	// PostMessage posts content to a traQ channel as the bot.
+	PostMessage(ctx context.Context, channelID uuid.UUID, content string) error
+}
@@ -0,0 +1,21 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"github.com/google/uuid"
+)
+
+type UserRepository interface {
+	Save(ctx context.Context, user *model.User) error
+	FindByID(ctx context.Context, id model.UserID) (*model.User, error)
+	// EnsureExists creates the user with default values if it does not exist yet.
+	EnsureExists(ctx context.Context, id model.UserID) error
+	// GetProfile returns the user with the statistics of their reviews and
+	// shops. Hidden reviews are counted only when includeHidden is set.
+	GetProfile(ctx context.Context, id model.UserID, includeHidden bool) (*model.UserProfile, error)
+	Follow(ctx context.Context, follower, followee model.UserID) error
+	Unfollow(ctx context.Context, follower, followee model.UserID) error
+	AddFavoriteShop(ctx context.Context, id model.UserID, shopID uuid.UUID) error
+	RemoveFavoriteShop(ctx context.Context, id model.UserID, shopID uuid.UUID) error
+}
@@ -0,0 +1,26 @@
+package repository
+
+import (
+	"backend/internal/domain/model"
+	"context"
+	"github.com/google/uuid"
+	"time"
+)
+
+type WebhookRepository interface {
+	Save(ctx context.Context, webhook *model.Webhook) error
+	FindByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error)
+	FindAll(ctx context.Context) ([]*model.Webhook, error)
+	// FindSubscribers returns the active webhooks subscribed to the event.
+	FindSubscribers(ctx context.Context, event model.EventType) ([]*model.Webhook, error)
+	Delete(ctx context.Context, id uuid.UUID) error
+
+	EnqueueDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error
+	// ClaimDueDeliveries returns pending deliveries whose next attempt is due
+	// and pushes their next attempt back by lease, so that other workers do not
+	// pick them up while they are being sent. Due deliveries of inactive
+	// webhooks are marked failed instead of being returned.
+	ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*model.WebhookDelivery, error)
+	SaveDelivery(ctx context.Context, delivery *model.WebhookDelivery) error
+	FindDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]*model.WebhookDelivery, error)
+}
@@ -0,0 +1,146 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+)
+
+// Record is a single exported row. Every record can be written as JSON and CSV.
+type Record interface {
+	CSVRow() []string
+}
+
+// Feature is implemented by records that carry a location and can therefore be
+// written as a GeoJSON feature.
+type Feature interface {
+	GeoJSONFeature() *GeoJSONFeature
+}
+
+type GeoJSONFeature struct {
+	Type       string          `json:"type"`
+	ID         string          `json:"id"`
+	Geometry   GeoJSONGeometry `json:"geometry"`
+	Properties any             `json:"properties"`
+}
+
+type GeoJSONGeometry struct {
+	Type string `json:"type"`
+	// GeoJSONでは経度, 緯度の順
+	Coordinates [2]float64 `json:"coordinates"`
+}
+
+type encoder interface {
+	Encode(r Record) error
+	Close() error
+}
+
+func newEncoder(w io.Writer, f Format, csvHeader []string) encoder {
+	switch f {
+	case FormatCSV:
+		return &csvEncoder{w: csv.NewWriter(w), header: csvHeader}
+	case FormatGeoJSON:
+		return &geoJSONEncoder{w: w}
+	default:
+		return &ndjsonEncoder{enc: json.NewEncoder(w)}
+	}
+}
+
+type ndjsonEncoder struct {
+	enc *json.Encoder
+}
+
+func (e *ndjsonEncoder) Encode(r Record) error {
+	return e.enc.Encode(r)
+}
+
+func (e *ndjsonEncoder) Close() error {
+	return nil
+}
+
+type csvEncoder struct {
+	w           *csv.Writer
+	header      []string
+	wroteHeader bool
+}
+
+func (e *csvEncoder) writeHeader() error {
+	if e.wroteHeader {
+		return nil
+	}
+	e.wroteHeader = true
+
+	return e.w.Write(e.header)
+}
+
+func (e *csvEncoder) Encode(r Record) error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	if err := e.w.Write(r.CSVRow()); err != nil {
+		return err
+	}
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+func (e *csvEncoder) Close() error {
+	if err := e.writeHeader(); err != nil {
+		return err
+	}
+	e.w.Flush()
+
+	return e.w.Error()
+}
+
+// geoJSONEncoder streams a FeatureCollection one feature at a time instead of
+// building the whole collection in memory.
+type geoJSONEncoder struct {
+	w       io.Writer
+	started bool
+}
+
+func (e *geoJSONEncoder) begin() error {
+	if e.started {
+		_, err := io.WriteString(e.w, ",\n")
+
+		return err
+	}
+	e.started = true
+	_, err := io.WriteString(e.w, `{"type":"FeatureCollection","features":[`+"\n")
+
+	return err
+}
+
+func (e *geoJSONEncoder) Encode(r Record) error {
+	f, ok := r.(Feature)
+	if !ok {
+		return fmt.Errorf("%w: %T has no location", ErrUnsupportedFormat, r)
+	}
+
+	b, err := json.Marshal(f.GeoJSONFeature())
+	if err != nil {
+		return err
+	}
+
+	if err := e.begin(); err != nil {
+		return err
+	}
+	_, err = e.w.Write(b)
+
+	return err
+}
+
+func (e *geoJSONEncoder) Close() error {
+	if !e.started {
+		e.started = true
+		if _, err := io.WriteString(e.w, `{"type":"FeatureCollection","features":[`); err != nil {
+			return err
+		}
+	}
+	_, err := io.WriteString(e.w, "\n]}\n")
+
+	return err
+}
@@ -0,0 +1,134 @@
+package export
+
+import (
+	"context"
+	"errors"
+	"io"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+var ErrUnknownTarget = errors.New("unknown export target")
+
+type Target string
+
+const (
+	TargetShops    Target = "shops"
+	TargetReviews  Target = "reviews"
+	TargetStations Target = "stations"
+)
+
+func ParseTarget(s string) (Target, error) {
+	switch t := Target(s); t {
+	case TargetShops, TargetReviews, TargetStations:
+		return t, nil
+	}
+
+	return "", ErrUnknownTarget
+}
+
+// Supports reports whether the target can be written in the given format.
+// Only shops have coordinates, so GeoJSON is limited to them.
+func (t Target) Supports(f Format) bool {
+	return f != FormatGeoJSON || t == TargetShops
+}
+
+// Exporter streams repository contents to a writer. Rows are read through the
+// repositories' Iterate methods and encoded one by one, so the whole data set
+// is never held in memory.
+type Exporter struct {
+	shopRepo    repository.ShopRepository
+	reviewRepo  repository.ReviewRepository
+	stationRepo repository.StationRepository
+}
+
+func NewExporter(
+	shopRepo repository.ShopRepository,
+	reviewRepo repository.ReviewRepository,
+	stationRepo repository.StationRepository,
+) *Exporter {
+	return &Exporter{
+		shopRepo:    shopRepo,
+		reviewRepo:  reviewRepo,
+		stationRepo: stationRepo,
+	}
+}
+
+func (e *Exporter) Export(ctx context.Context, w io.Writer, t Target, f Format) error {
+	if !t.Supports(f) {
+		return ErrUnsupportedFormat
+	}
+
+	switch t {
+	case TargetShops:
+		return e.ExportShops(ctx, w, f)
+	case TargetReviews:
+		return e.ExportReviews(ctx, w, f)
+	case TargetStations:
+		return e.ExportStations(ctx, w, f)
+	}
+
+	return ErrUnknownTarget
+}
+
+func (e *Exporter) ExportShops(ctx context.Context, w io.Writer, f Format) error {
+	// 駅の数は店舗に比べて十分小さいので名前の対応表だけは先に読み込む
+	stationNames := make(map[uuid.UUID]string)
+	err := e.stationRepo.Iterate(ctx, func(s *model.Station) error {
+		stationNames[s.ID] = s.Name
+
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	enc := newEncoder(w, f, shopCSVHeader)
+	err = e.shopRepo.Iterate(ctx, func(s *model.Shop) error {
+		return enc.Encode(NewShopRecord(s, stationNames))
+	})
+	if err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+func (e *Exporter) ExportReviews(ctx context.Context, w io.Writer, f Format) error {
+	if !TargetReviews.Supports(f) {
+		return ErrUnsupportedFormat
+	}
+
+	enc := newEncoder(w, f, reviewCSVHeader)
+	err := e.reviewRepo.Iterate(ctx, func(r *model.Review) error {
+		if r.Hidden {
+			return nil
+		}
+
+		return enc.Encode(NewReviewRecord(r))
+	})
+	if err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
+
+func (e *Exporter) ExportStations(ctx context.Context, w io.Writer, f Format) error {
+	if !TargetStations.Supports(f) {
+		return ErrUnsupportedFormat
+	}
+
+	enc := newEncoder(w, f, stationCSVHeader)
+	err := e.stationRepo.Iterate(ctx, func(s *model.Station) error {
+		return enc.Encode(NewStationRecord(s))
+	})
+	if err != nil {
+		return err
+	}
+
+	return enc.Close()
+}
@@ -0,0 +1,51 @@
+package export
+
+import (
+	"errors"
+	"strings"
+)
+
+var ErrUnsupportedFormat = errors.New("unsupported export format")
+
+type Format string
+
+const (
+	FormatNDJSON  Format = "ndjson"
+	FormatCSV     Format = "csv"
+	FormatGeoJSON Format = "geojson"
+)
+
+func ParseFormat(s string) (Format, error) {
+	switch strings.ToLower(s) {
+	case "", "ndjson", "jsonl":
+		return FormatNDJSON, nil
+	case "csv":
+		return FormatCSV, nil
+	case "geojson":
+		return FormatGeoJSON, nil
+	}
+
+	return "", ErrUnsupportedFormat
+}
+
+func (f Format) ContentType() string {
+	switch f {
+	case FormatCSV:
+		return "text/csv; charset=utf-8"
+	case FormatGeoJSON:
+		return "application/geo+json"
+	default:
+		return "application/x-ndjson"
+	}
+}
+
+func (f Format) Extension() string {
+	switch f {
+	case FormatCSV:
+		return "csv"
+	case FormatGeoJSON:
+		return "geojson"
+	default:
+		return "ndjson"
+	}
+}
@@ -0,0 +1,185 @@
+package export
+
+import (
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/domain/model"
+
+	"github.com/google/uuid"
+)
+
+// CSVでは複数値の列をこの区切り文字で連結する
+const csvListSeparator = ";"
+
+type StationRef struct {
+	ID   string `json:"id"`
+	Name string `json:"name,omitempty"`
+}
+
+type ShopRecord struct {
+	ID             string       `json:"id"`
+	Name           string       `json:"name"`
+	PostCode       string       `json:"post_code,omitempty"`
+	Address        string       `json:"address,omitempty"`
+	Latitude       float64      `json:"latitude"`
+	Longitude      float64      `json:"longitude"`
+	Stations       []StationRef `json:"stations"`
+	PaymentMethods []string     `json:"payment_methods"`
+	Images         []string     `json:"images"`
+	Registerer     string       `json:"registerer,omitempty"`
+	CreatedAt      time.Time    `json:"created_at"`
+	UpdatedAt      time.Time    `json:"updated_at"`
+}
+
+var shopCSVHeader = []string{
+	"id", "name", "post_code", "address", "latitude", "longitude",
+	"station_ids", "station_names", "payment_methods", "images",
+	"registerer", "created_at", "updated_at",
+}
+
+func NewShopRecord(s *model.Shop, stationNames map[uuid.UUID]string) *ShopRecord {
+	stations := make([]StationRef, len(s.Stations))
+	for i, id := range s.Stations {
+		stations[i] = StationRef{ID: id.String(), Name: stationNames[id]}
+	}
+
+	images := make([]string, len(s.Images))
+	for i, img := range s.Images {
+		images[i] = img.ID.String()
+	}
+
+	paymentMethods := s.PaymentMethods
+	if paymentMethods == nil {
+		paymentMethods = []string{}
+	}
+
+	return &ShopRecord{
+		ID:             s.ID.String(),
+		Name:           string(s.Name),
+		PostCode:       string(s.PostCode),
+		Address:        s.Address,
+		Latitude:       s.Latitude,
+		Longitude:      s.Longitude,
+		Stations:       stations,
+		PaymentMethods: paymentMethods,
+		Images:         images,
+		Registerer:     string(s.Registerer),
+		CreatedAt:      s.CreatedAt,
+		UpdatedAt:      s.UpdatedAt,
+	}
+}
+
+func (r *ShopRecord) CSVRow() []string {
+	stationIDs := make([]string, len(r.Stations))
+	stationNames := make([]string, len(r.Stations))
+	for i, s := range r.Stations {
+		stationIDs[i] = s.ID
+		stationNames[i] = s.Name
+	}
+
+	return []string{
+		r.ID,
+		r.Name,
+		r.PostCode,
+		r.Address,
+		formatFloat(r.Latitude),
+		formatFloat(r.Longitude),
+		strings.Join(stationIDs, csvListSeparator),
+		strings.Join(stationNames, csvListSeparator),
+		strings.Join(r.PaymentMethods, csvListSeparator),
+		strings.Join(r.Images, csvListSeparator),
+		r.Registerer,
+		formatTime(r.CreatedAt),
+		formatTime(r.UpdatedAt),
+	}
+}
+
+func (r *ShopRecord) GeoJSONFeature() *GeoJSONFeature {
+	return &GeoJSONFeature{
+		Type: "Feature",
+		ID:   r.ID,
+		Geometry: GeoJSONGeometry{
+			Type:        "Point",
+			Coordinates: [2]float64{r.Longitude, r.Latitude},
+		},
+		Properties: r,
+	}
+}
+
+type ReviewRecord struct {
+	ID        string    `json:"id"`
+	Author    string    `json:"author"`
+	Shop      string    `json:"shop"`
+	Rating    int       `json:"rating"`
+	Content   string    `json:"content"`
+	Images    []string  `json:"images"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var reviewCSVHeader = []string{
+	"id", "author", "shop", "rating", "content", "images", "created_at", "updated_at",
+}
+
+func NewReviewRecord(r *model.Review) *ReviewRecord {
+	images := make([]string, len(r.Images))
+	for i, img := range r.Images {
+		images[i] = img.ID.String()
+	}
+
+	return &ReviewRecord{
+		ID:        r.ID.String(),
+		Author:    string(r.Author),
+		Shop:      r.Shop.String(),
+		Rating:    int(r.Rating),
+		Content:   r.Content,
+		Images:    images,
+		CreatedAt: r.CreatedAt,
+		UpdatedAt: r.UpdatedAt,
+	}
+}
+
+func (r *ReviewRecord) CSVRow() []string {
+	return []string{
+		r.ID,
+		r.Author,
+		r.Shop,
+		strconv.Itoa(r.Rating),
+		r.Content,
+		strings.Join(r.Images, csvListSeparator),
+		formatTime(r.CreatedAt),
+		formatTime(r.UpdatedAt),
+	}
+}
+
+type StationRecord struct {
+	ID        string    `json:"id"`
+	Name      string    `json:"name"`
+	CreatedAt time.Time `json:"created_at"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+var stationCSVHeader = []string{"id", "name", "created_at", "updated_at"}
+
+func NewStationRecord(s *model.Station) *StationRecord {
+	return &StationRecord{
+		ID:        s.ID.String(),
+		Name:      s.Name,
+		CreatedAt: s.CreatedAt,
+		UpdatedAt: s.UpdatedAt,
+	}
+}
+
+func (r *StationRecord) CSVRow() []string {
+	return []string{r.ID, r.Name, formatTime(r.CreatedAt), formatTime(r.UpdatedAt)}
+}
+
+func formatFloat(f float64) string {
+	return strconv.FormatFloat(f, 'f', -1, 64)
+}
+
+func formatTime(t time.Time) string {
+	return t.Format(time.RFC3339)
+}
@@ -0,0 +1,186 @@
+package handler
+
+import (
+	"context"
+	"encoding/json"
+	"log/slog"
+	"net/http"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type AuditHandler struct {
+	auditRepo repository.AuditLogRepository
+}
+
+func NewAuditHandler(auditRepo repository.AuditLogRepository) *AuditHandler {
+	return &AuditHandler{
+		auditRepo: auditRepo,
+	}
+}
+
+type AuditLog struct {
+	ID string `json:"id"`
+
+	// 操作したユーザーのID
+	Actor string `json:"actor"`
+
+	// create, update, delete のいずれか
+	Action string `json:"action"`
+
+	// shop, review, station, image のいずれか
+	EntityType string `json:"entity_type"`
+
+	EntityID string `json:"entity_id"`
+
+	// 変更前後のAPIでの表現。作成時は before、削除時は after がない
+	Before json.RawMessage `json:"before,omitempty"`
+
+	After json.RawMessage `json:"after,omitempty"`
+
+	RequestID string `json:"request_id,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func FromModelToAuditLog(log *model.AuditLog) *AuditLog {
+	return &AuditLog{
+		ID:         log.ID.String(),
+		Actor:      string(log.Actor),
+		Action:     string(log.Action),
+		EntityType: string(log.EntityType),
+		EntityID:   log.EntityID.String(),
+		Before:     log.Before,
+		After:      log.After,
+		RequestID:  log.RequestID,
+		CreatedAt:  log.CreatedAt,
+	}
+}
+
+// AuditImage is the snapshot of an image in the audit log, naming what the
+// image is attached to.
+type AuditImage struct {
+	ID       string `json:"id"`
+	Shop     string `json:"shop,omitempty"`
+	Review   string `json:"review,omitempty"`
+	MenuItem string `json:"menu_item,omitempty"`
+	// アバター画像の場合のユーザーID
+	User string `json:"user,omitempty"`
+}
+
+func (h *AuditHandler) GetAuditLogs(c echo.Context) error {
+	var filter repository.AuditLogFilter
+	filter.Actor = model.UserID(c.QueryParam("actor"))
+
+	if action := c.QueryParam("action"); action != "" {
+		parsed, err := model.ParseAuditAction(action)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid action: must be create, update or delete")
+		}
+		filter.Action = parsed
+	}
+
+	if entityType := c.QueryParam("entity_type"); entityType != "" {
+		parsed, err := model.ParseAuditEntityType(entityType)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid entity_type: must be shop, review, station or image")
+		}
+		filter.EntityType = parsed
+	}
+
+	if entityID := c.QueryParam("entity_id"); entityID != "" {
+		id, err := uuid.Parse(entityID)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid entity_id")
+		}
+		filter.EntityID = id
+	}
+
+	if since := c.QueryParam("since"); since != "" {
+		t, err := time.Parse(time.RFC3339, since)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid since: must be RFC 3339")
+		}
+		filter.Since = t
+	}
+
+	if until := c.QueryParam("until"); until != "" {
+		t, err := time.Parse(time.RFC3339, until)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid until: must be RFC 3339")
+		}
+		filter.Until = t
+	}
+
+	limit, offset := parsePageParams(c)
+	logs, err := h.auditRepo.Find(c.Request().Context(), filter, limit, offset)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch audit logs")
+	}
+
+	responses := make([]*AuditLog, len(logs))
+	for i, log := range logs {
+		responses[i] = FromModelToAuditLog(log)
+	}
+
+	return c.JSON(http.StatusOK, responses)
+}
+
+// auditSnapshot marshals the API representation of an entity for the audit
+// log. Take it before changing the entity, since DTOs may share slices with
+// the model.
+func auditSnapshot(v any) []byte {
+	snapshot, err := json.Marshal(v)
+	if err != nil {
+		slog.Error("failed to marshal audit snapshot", "error", err)
+
+		return nil
+	}
+
+	return snapshot
+}
+
+// recordAudit appends an entry for a change made by the current user. Like
+// webhook notifications, it runs after the change has been committed, not in
+// the same transaction: if writing the entry fails, the failure is only
+// logged and the change is missing from the audit log.
+func recordAudit(
+	c echo.Context,
+	auditRepo repository.AuditLogRepository,
+	action model.AuditAction,
+	entityType model.AuditEntityType,
+	entityID uuid.UUID,
+	before []byte,
+	after []byte,
+) {
+	actor, _ := GetUserID(c)
+
+	entry, err := model.NewAuditLog(model.UserID(actor), action, entityType, entityID, before, after, requestID(c))
+	if err != nil {
+		logging.FromContext(c.Request().Context()).Error("failed to create audit log", "entity_type", entityType, "entity_id", entityID, "error", err)
+
+		return
+	}
+
+	// リクエストが中断されても記録は残す
+	ctx := context.WithoutCancel(c.Request().Context())
+	if err := auditRepo.Save(ctx, entry); err != nil {
+		logging.FromContext(ctx).Error("failed to save audit log", "entity_type", entityType, "entity_id", entityID, "error", err)
+	}
+}
+
+// requestID returns the ID of the current request, from the X-Request-ID
+// header given by the client or the proxy.
+func requestID(c echo.Context) string {
+	if id := c.Response().Header().Get(echo.HeaderXRequestID); id != "" {
+		return id
+	}
+
+	return c.Request().Header.Get(echo.HeaderXRequestID)
+}
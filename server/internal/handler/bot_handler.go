@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"crypto/subtle"
+	"net/http"
+
+	"backend/internal/bot"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	traqBotEventHeader = "X-TRAQ-BOT-EVENT"
+	traqBotTokenHeader = "X-TRAQ-BOT-TOKEN"
+
+	traqEventPing           = "PING"
+	traqEventMessageCreated = "MESSAGE_CREATED"
+)
+
+type BotHandler struct {
+	bot               *bot.Bot
+	traqRepo          repository.TraqRepository
+	verificationToken string
+}
+
+func NewBotHandler(b *bot.Bot, traqRepo repository.TraqRepository, verificationToken string) *BotHandler {
+	return &BotHandler{
+		bot:               b,
+		traqRepo:          traqRepo,
+		verificationToken: verificationToken,
+	}
+}
+
+// TraqMessageCreatedEvent is the payload of a traQ MESSAGE_CREATED bot event.
+type TraqMessageCreatedEvent struct {
+	Message struct {
+		ID string `json:"id"`
+
+		User struct {
+			ID string `json:"id"`
+
+			Name string `json:"name"`
+
+			Bot bool `json:"bot"`
+		} `json:"user"`
+
+		ChannelID string `json:"channelId"`
+
+		// メンションなどを展開したテキスト
+		PlainText string `json:"plainText"`
+	} `json:"message"`
+}
+
+func (h *BotHandler) HandleTraqEvent(c echo.Context) error {
+	// トークン未設定のままでは誰でもBotを動かせてしまうため受け付けない
+	token := c.Request().Header.Get(traqBotTokenHeader)
+	if h.verificationToken == "" ||
+		subtle.ConstantTimeCompare([]byte(token), []byte(h.verificationToken)) != 1 {
+		return errorResponse(c, http.StatusUnauthorized, "Invalid bot verification token")
+	}
+
+	switch c.Request().Header.Get(traqBotEventHeader) {
+	case traqEventPing:
+		return c.NoContent(http.StatusNoContent)
+	case traqEventMessageCreated:
+	default:
+		// 購読していないイベントは無視する
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	var event TraqMessageCreatedEvent
+	if err := c.Bind(&event); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	// Bot同士で応答し続けないようにする
+	if event.Message.User.Bot {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	channelID, err := uuid.Parse(event.Message.ChannelID)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid channel ID")
+	}
+
+	ctx := c.Request().Context()
+
+	reply, ok, err := h.bot.Reply(ctx, event.Message.PlainText)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to answer bot command", "command", event.Message.PlainText, "error", err)
+		reply, ok = "エラーが発生しました。時間をおいて再度お試しください", true
+	}
+	if !ok {
+		return c.NoContent(http.StatusNoContent)
+	}
+
+	if err := h.traqRepo.PostMessage(ctx, channelID, reply); err != nil {
+		logging.FromContext(ctx).Error("failed to post bot reply", "channel", channelID, "error", err)
+
+		return errorResponse(c, http.StatusBadGateway, "Failed to post reply")
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
@@ -0,0 +1,179 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/model"
+
+	"github.com/labstack/echo/v4"
+)
+
+var errInvalidOpenAt = errors.New("invalid open_at")
+
+type BusinessHours struct {
+	// 曜日ごとの営業時間。同じ曜日に複数の時間帯を指定できる
+	Weekly []BusinessHoursRange `json:"weekly"`
+
+	// 臨時休業日
+	Closures []ShopClosure `json:"closures,omitempty"`
+}
+
+type BusinessHoursRange struct {
+	// 曜日（Mo, Tu, We, Th, Fr, Sa, Su）
+	Weekday string `json:"weekday"`
+
+	// 開店時刻（HH:MM）
+	Open string `json:"open"`
+
+	// 閉店時刻（HH:MM）。開店時刻以前の場合は翌日の時刻とみなす
+	Close string `json:"close"`
+}
+
+type ShopClosure struct {
+	// 休業日（YYYY-MM-DD）
+	Date string `json:"date"`
+
+	Note string `json:"note,omitempty"`
+}
+
+func FromModelToBusinessHours(h *model.BusinessHours) *BusinessHours {
+	weekly := make([]BusinessHoursRange, len(h.Ranges))
+	for i, r := range h.Ranges {
+		weekly[i] = BusinessHoursRange{
+			Weekday: model.OSMWeekdayName(r.Weekday),
+			Open:    formatMinutes(r.Start),
+			Close:   formatMinutes(r.End),
+		}
+	}
+
+	closures := make([]ShopClosure, len(h.Closures))
+	for i, c := range h.Closures {
+		closures[i] = ShopClosure{
+			Date: c.Date.Format(time.DateOnly),
+			Note: c.Note,
+		}
+	}
+
+	return &BusinessHours{
+		Weekly:   weekly,
+		Closures: closures,
+	}
+}
+
+// ToModel converts the request body. Empty hours clear the registered ones
+// and yield nil.
+func (h *BusinessHours) ToModel() (*model.BusinessHours, error) {
+	if len(h.Weekly) == 0 && len(h.Closures) == 0 {
+		return nil, nil
+	}
+
+	ranges := make([]model.TimeRange, 0, len(h.Weekly))
+	for _, r := range h.Weekly {
+		weekday, ok := model.OSMWeekday(r.Weekday)
+		if !ok {
+			return nil, fmt.Errorf("%w: weekday %q", model.ErrInvalidBusinessHours, r.Weekday)
+		}
+
+		open, err := parseMinutes(r.Open)
+		if err != nil {
+			return nil, err
+		}
+
+		closeAt, err := parseMinutes(r.Close)
+		if err != nil {
+			return nil, err
+		}
+
+		timeRange, err := model.NewTimeRange(weekday, open, closeAt)
+		if err != nil {
+			return nil, err
+		}
+		ranges = append(ranges, timeRange)
+	}
+
+	closures := make([]model.Closure, 0, len(h.Closures))
+	for _, c := range h.Closures {
+		date, err := time.ParseInLocation(time.DateOnly, c.Date, model.Tokyo)
+		if err != nil {
+			return nil, fmt.Errorf("%w: date %q", model.ErrInvalidBusinessHours, c.Date)
+		}
+		closures = append(closures, model.NewClosure(date, c.Note))
+	}
+
+	return model.NewBusinessHours(ranges, closures), nil
+}
+
+// businessHoursFromRequest returns the hours given either as structured JSON
+// or in OpenStreetMap opening_hours syntax. set is false when the request
+// does not touch the hours.
+func businessHoursFromRequest(req *APIV1ShopsPostRequest) (hours *model.BusinessHours, set bool, err error) {
+	switch {
+	case req.BusinessHours != nil && req.OpeningHours != "":
+		return nil, false, fmt.Errorf("%w: specify either business_hours or opening_hours", model.ErrInvalidBusinessHours)
+	case req.BusinessHours != nil:
+		hours, err = req.BusinessHours.ToModel()
+
+		return hours, true, err
+	case req.OpeningHours != "":
+		hours, err = model.ParseOpeningHours(req.OpeningHours)
+
+		return hours, true, err
+	}
+
+	return nil, false, nil
+}
+
+// filterOpenAt keeps the shops open at the time given by the open_at query
+// parameter. Shops without registered hours are excluded.
+func filterOpenAt(c echo.Context, shops []*model.Shop) ([]*model.Shop, error) {
+	openAt := c.QueryParam("open_at")
+	if openAt == "" {
+		return shops, nil
+	}
+
+	t, err := time.Parse(time.RFC3339, openAt)
+	if err != nil {
+		return nil, errInvalidOpenAt
+	}
+
+	filtered := make([]*model.Shop, 0, len(shops))
+	for _, shop := range shops {
+		if shop.BusinessHours != nil && shop.BusinessHours.IsOpenAt(t) {
+			filtered = append(filtered, shop)
+		}
+	}
+
+	return filtered, nil
+}
+
+func isOpenNow(shop *model.Shop) *bool {
+	if shop.BusinessHours == nil {
+		return nil
+	}
+
+	open := shop.BusinessHours.IsOpenAt(time.Now())
+
+	return &open
+}
+
+// formatMinutes formats minutes since midnight as HH:MM, wrapping times past
+// midnight except for 24:00 itself.
+func formatMinutes(minutes int) string {
+	if minutes > 24*60 {
+		minutes -= 24 * 60
+	}
+
+	return fmt.Sprintf("%02d:%02d", minutes/60, minutes%60)
+}
+
+func parseMinutes(s string) (int, error) {
+	var hour, minute int
+	if _, err := fmt.Sscanf(s, "%d:%d", &hour, &minute); err != nil ||
+		len(s) != 5 || hour < 0 || hour > 24 || minute < 0 || minute >= 60 {
+		return 0, fmt.Errorf("%w: time %q", model.ErrInvalidBusinessHours, s)
+	}
+
+	return hour*60 + minute, nil
+}
@@ -0,0 +1,475 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type EditProposalHandler struct {
+	proposalRepo      repository.EditProposalRepository
+	shopRepo          repository.ShopRepository
+	tagRepo           repository.TagRepository
+	paymentMethodRepo repository.PaymentMethodRepository
+	webhookRepo       repository.WebhookRepository
+	auditRepo         repository.AuditLogRepository
+	admins            Admins
+}
+
+func NewEditProposalHandler(
+	proposalRepo repository.EditProposalRepository,
+	shopRepo repository.ShopRepository,
+	tagRepo repository.TagRepository,
+	paymentMethodRepo repository.PaymentMethodRepository,
+	webhookRepo repository.WebhookRepository,
+	auditRepo repository.AuditLogRepository,
+	admins Admins,
+) *EditProposalHandler {
+	return &EditProposalHandler{
+		proposalRepo:      proposalRepo,
+		shopRepo:          shopRepo,
+		tagRepo:           tagRepo,
+		paymentMethodRepo: paymentMethodRepo,
+		webhookRepo:       webhookRepo,
+		auditRepo:         auditRepo,
+		admins:            admins,
+	}
+}
+
+type ShopLocation struct {
+	Latitude float64 `json:"latitude"`
+
+	Longitude float64 `json:"longitude"`
+}
+
+type FieldChange struct {
+	// name, address, post_code, location, payment_methods, stations, tags のいずれか
+	Field string `json:"field"`
+
+	// 提案時点の値
+	Old any `json:"old"`
+
+	// 提案された値
+	New any `json:"new"`
+}
+
+type EditProposal struct {
+	ID string `json:"id"`
+
+	Shop string `json:"shop"`
+
+	// 提案したユーザーのID
+	Proposer string `json:"proposer"`
+
+	Changes []*FieldChange `json:"changes"`
+
+	Note string `json:"note,omitempty"`
+
+	// pending, accepted, rejected のいずれか
+	Status string `json:"status"`
+
+	// 承認・却下したユーザーのID
+	Reviewer string `json:"reviewer,omitempty"`
+
+	ReviewNote string `json:"review_note,omitempty"`
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	ReviewedAt *time.Time `json:"reviewed_at,omitempty"`
+}
+
+func FromModelToEditProposal(m *model.EditProposal) *EditProposal {
+	changes := make([]*FieldChange, len(m.Changes))
+	for i, change := range m.Changes {
+		changes[i] = &FieldChange{
+			Field: string(change.Field),
+			Old:   fromModelFieldValue(change.Old),
+			New:   fromModelFieldValue(change.New),
+		}
+	}
+
+	var reviewedAt *time.Time
+	if !m.ReviewedAt.IsZero() {
+		reviewedAt = &m.ReviewedAt
+	}
+
+	return &EditProposal{
+		ID:         m.ID.String(),
+		Shop:       m.Shop.String(),
+		Proposer:   string(m.Proposer),
+		Changes:    changes,
+		Note:       m.Note,
+		Status:     string(m.Status),
+		Reviewer:   string(m.Reviewer),
+		ReviewNote: m.ReviewNote,
+		CreatedAt:  m.CreatedAt,
+		ReviewedAt: reviewedAt,
+	}
+}
+
+func fromModelFieldValue(value any) any {
+	if location, ok := value.([2]float64); ok {
+		return &ShopLocation{Latitude: location[0], Longitude: location[1]}
+	}
+
+	return value
+}
+
+// ShopChanges は変更したい項目だけを指定する。配列を空にする場合は [] を指定する
+type ShopChanges struct {
+	Name *string `json:"name,omitempty"`
+
+	Address *string `json:"address,omitempty"`
+
+	PostCode *string `json:"post_code,omitempty"`
+
+	Location *ShopLocation `json:"location,omitempty"`
+
+	PaymentMethods []string `json:"payment_methods,omitempty"`
+
+	Stations []string `json:"stations,omitempty"`
+
+	Tags []string `json:"tags,omitempty"`
+}
+
+type APIV1ShopsIDEditProposalsPostRequest struct {
+	Changes ShopChanges `json:"changes"`
+
+	Note string `json:"note,omitempty"`
+}
+
+type APIV1EditProposalsIDReviewRequest struct {
+	Note string `json:"note,omitempty"`
+}
+
+type APIV1EditProposalsIDAcceptPost200Response struct {
+	Proposal *EditProposal `json:"proposal"`
+
+	Shop *Shop `json:"shop"`
+}
+
+type APIV1EditProposalsIDAcceptPost409Response struct {
+	Error string `json:"error"`
+
+	// 提案後に変更された項目
+	Conflicts []string `json:"conflicts"`
+}
+
+// CreateEditProposal submits changes to a shop for the registerer or a
+// moderator to review.
+func (h *EditProposalHandler) CreateEditProposal(c echo.Context) error {
+	shopID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid shop ID format")
+	}
+
+	var req APIV1ShopsIDEditProposalsPostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	shop, err := h.shopRepo.FindByID(c.Request().Context(), shopID)
+	if err != nil {
+		return shopLookupError(c, err)
+	}
+
+	changes, err := h.parseChanges(c, shop, &req.Changes)
+	if err != nil {
+		return editProposalRequestError(c, err)
+	}
+
+	proposal, err := model.NewEditProposal(shop, model.UserID(userID), changes, req.Note)
+	if err != nil {
+		return editProposalRequestError(c, err)
+	}
+
+	if err := h.proposalRepo.Save(c.Request().Context(), proposal); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save edit proposal")
+	}
+
+	return c.JSON(http.StatusCreated, FromModelToEditProposal(proposal))
+}
+
+// GetShopEditProposals returns the proposals of a shop including the
+// accept/reject history.
+func (h *EditProposalHandler) GetShopEditProposals(c echo.Context) error {
+	shopID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid shop ID format")
+	}
+
+	proposals, err := h.proposalRepo.FindByShop(c.Request().Context(), shopID)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch edit proposals")
+	}
+
+	return c.JSON(http.StatusOK, fromModelToEditProposals(proposals))
+}
+
+// GetEditProposalQueue returns the pending proposals the caller can review:
+// all of them for moderators, and those for their own shops otherwise.
+func (h *EditProposalHandler) GetEditProposalQueue(c echo.Context) error {
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	registerer := model.UserID(userID)
+	if h.admins.Contains(userID) {
+		registerer = ""
+	}
+
+	proposals, err := h.proposalRepo.FindPending(c.Request().Context(), registerer)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch edit proposals")
+	}
+
+	return c.JSON(http.StatusOK, fromModelToEditProposals(proposals))
+}
+
+func (h *EditProposalHandler) GetEditProposal(c echo.Context) error {
+	proposal, err := h.findProposal(c)
+	if err != nil {
+		return editProposalLookupError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, FromModelToEditProposal(proposal))
+}
+
+// AcceptEditProposal applies the proposal to the shop. It answers 409 when
+// the proposed fields were changed after the proposal was made.
+func (h *EditProposalHandler) AcceptEditProposal(c echo.Context) error {
+	proposal, shop, reviewer, err := h.prepareReview(c)
+	if err != nil {
+		return editProposalLookupError(c, err)
+	}
+
+	var req APIV1EditProposalsIDReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	base := shop.UpdatedAt
+	before := auditSnapshot(FromModelToShop(shop))
+	if err := proposal.Accept(shop, reviewer, req.Note); err != nil {
+		if errors.Is(err, model.ErrEditConflict) {
+			conflicts := proposal.Conflicts(shop)
+			res := &APIV1EditProposalsIDAcceptPost409Response{
+				Error:     "The shop was changed after the proposal was made",
+				Conflicts: make([]string, len(conflicts)),
+			}
+			for i, field := range conflicts {
+				res.Conflicts[i] = string(field)
+			}
+
+			return c.JSON(http.StatusConflict, res)
+		}
+
+		return editProposalRequestError(c, err)
+	}
+
+	if err := h.proposalRepo.Accept(c.Request().Context(), proposal, shop, base); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return errorResponse(c, http.StatusConflict, "The shop or the proposal was updated concurrently: retry")
+		}
+
+		return errorResponse(c, http.StatusInternalServerError, "Failed to apply edit proposal")
+	}
+	notifyWebhooks(c, h.webhookRepo, model.EventShopUpdated, FromModelToShop(shop))
+	recordAudit(c, h.auditRepo, model.AuditActionUpdate, model.AuditEntityShop, shop.ID, before, auditSnapshot(FromModelToShop(shop)))
+
+	return c.JSON(http.StatusOK, &APIV1EditProposalsIDAcceptPost200Response{
+		Proposal: FromModelToEditProposal(proposal),
+		Shop:     FromModelToShop(shop),
+	})
+}
+
+func (h *EditProposalHandler) RejectEditProposal(c echo.Context) error {
+	proposal, _, reviewer, err := h.prepareReview(c)
+	if err != nil {
+		return editProposalLookupError(c, err)
+	}
+
+	var req APIV1EditProposalsIDReviewRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	if err := proposal.Reject(reviewer, req.Note); err != nil {
+		return editProposalRequestError(c, err)
+	}
+
+	if err := h.proposalRepo.Reject(c.Request().Context(), proposal); err != nil {
+		if errors.Is(err, repository.ErrConflict) {
+			return errorResponse(c, http.StatusConflict, "The proposal was reviewed concurrently")
+		}
+
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save edit proposal")
+	}
+
+	return c.JSON(http.StatusOK, FromModelToEditProposal(proposal))
+}
+
+// prepareReview loads the proposal and its shop and checks that the caller
+// may review it.
+func (h *EditProposalHandler) prepareReview(c echo.Context) (*model.EditProposal, *model.Shop, model.UserID, error) {
+	userID, err := GetUserID(c)
+	if err != nil {
+		return nil, nil, "", errNotReviewer
+	}
+
+	proposal, err := h.findProposal(c)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	shop, err := h.shopRepo.FindByID(c.Request().Context(), proposal.Shop)
+	if err != nil {
+		return nil, nil, "", err
+	}
+
+	if !canEditShop(h.admins, userID, shop) {
+		return nil, nil, "", errNotReviewer
+	}
+
+	return proposal, shop, model.UserID(userID), nil
+}
+
+// parseChanges converts the requested changes to field changes against the
+// current shop, normalizing payment methods and checking tags.
+func (h *EditProposalHandler) parseChanges(c echo.Context, shop *model.Shop, req *ShopChanges) ([]model.FieldChange, error) {
+	values := make(map[model.ShopField]any)
+	if req.Name != nil {
+		values[model.ShopFieldName] = *req.Name
+	}
+	if req.Address != nil {
+		values[model.ShopFieldAddress] = *req.Address
+	}
+	if req.PostCode != nil {
+		values[model.ShopFieldPostCode] = *req.PostCode
+	}
+	if req.Location != nil {
+		values[model.ShopFieldLocation] = [2]float64{req.Location.Latitude, req.Location.Longitude}
+	}
+	if req.PaymentMethods != nil {
+		methods, err := normalizePaymentMethods(c, h.paymentMethodRepo, req.PaymentMethods)
+		if err != nil {
+			return nil, err
+		}
+		values[model.ShopFieldPaymentMethods] = methods
+	}
+	if req.Stations != nil {
+		stations := make([]uuid.UUID, len(req.Stations))
+		for i, s := range req.Stations {
+			id, err := uuid.Parse(s)
+			if err != nil {
+				return nil, model.ErrInvalidEditProposal
+			}
+			stations[i] = id
+		}
+		values[model.ShopFieldStations] = stations
+	}
+	if req.Tags != nil {
+		tags, err := parseShopTags(c.Request().Context(), h.tagRepo, req.Tags)
+		if err != nil {
+			return nil, err
+		}
+		values[model.ShopFieldTags] = tags
+	}
+
+	fields := []model.ShopField{
+		model.ShopFieldName,
+		model.ShopFieldAddress,
+		model.ShopFieldPostCode,
+		model.ShopFieldLocation,
+		model.ShopFieldPaymentMethods,
+		model.ShopFieldStations,
+		model.ShopFieldTags,
+	}
+
+	var changes []model.FieldChange
+	for _, field := range fields {
+		value, ok := values[field]
+		if !ok {
+			continue
+		}
+		change, err := model.NewFieldChange(shop, field, value)
+		if err != nil {
+			return nil, err
+		}
+		changes = append(changes, change)
+	}
+
+	return changes, nil
+}
+
+func (h *EditProposalHandler) findProposal(c echo.Context) (*model.EditProposal, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errInvalidEditProposalID
+	}
+
+	return h.proposalRepo.FindByID(c.Request().Context(), id)
+}
+
+func fromModelToEditProposals(proposals []*model.EditProposal) []*EditProposal {
+	responses := make([]*EditProposal, len(proposals))
+	for i, proposal := range proposals {
+		responses[i] = FromModelToEditProposal(proposal)
+	}
+
+	return responses
+}
+
+// canEditShop reports whether the user may change the shop directly.
+func canEditShop(admins Admins, userID string, shop *model.Shop) bool {
+	return admins.Contains(userID) || model.UserID(userID) == shop.Registerer
+}
+
+var (
+	errInvalidEditProposalID = errors.New("invalid edit proposal ID")
+	errNotReviewer           = errors.New("user cannot review the edit proposal")
+)
+
+func editProposalLookupError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errNotReviewer):
+		return errorResponse(c, http.StatusForbidden, "Only the registerer or moderators can review edit proposals")
+	case errors.Is(err, errInvalidEditProposalID):
+		return errorResponse(c, http.StatusBadRequest, "Invalid edit proposal ID format")
+	case errors.Is(err, repository.ErrNotFound):
+		return errorResponse(c, http.StatusNotFound, "Edit proposal not found")
+	default:
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch edit proposal")
+	}
+}
+
+func editProposalRequestError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, model.ErrEditProposalClosed):
+		return errorResponse(c, http.StatusConflict, "Edit proposal has already been reviewed")
+	case errors.Is(err, model.ErrInvalidPaymentMethod):
+		return paymentMethodError(c, err)
+	case errors.Is(err, model.ErrInvalidShopName):
+		return errorResponse(c, http.StatusBadRequest, "Invalid shop name")
+	case errors.Is(err, model.ErrInvalidPostCode):
+		return errorResponse(c, http.StatusBadRequest, "Invalid post code")
+	case errors.Is(err, model.ErrInvalidEditProposal):
+		return errorResponse(c, http.StatusBadRequest, "Invalid edit proposal: changes must differ from the current values and the note must be at most 255 characters")
+	case errors.Is(err, errUnknownTag):
+		return shopTagsError(c, err)
+	default:
+		return errorResponse(c, http.StatusInternalServerError, "Failed to validate edit proposal")
+	}
+}
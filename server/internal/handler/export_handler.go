@@ -0,0 +1,58 @@
+package handler
+
+import (
+	"fmt"
+	"net/http"
+	"time"
+
+	"backend/internal/export"
+	"backend/internal/logging"
+
+	"github.com/labstack/echo/v4"
+)
+
+type ExportHandler struct {
+	exporter *export.Exporter
+}
+
+func NewExportHandler(exporter *export.Exporter) *ExportHandler {
+	return &ExportHandler{
+		exporter: exporter,
+	}
+}
+
+func (h *ExportHandler) ExportShops(c echo.Context) error {
+	return h.export(c, export.TargetShops)
+}
+
+func (h *ExportHandler) ExportReviews(c echo.Context) error {
+	return h.export(c, export.TargetReviews)
+}
+
+func (h *ExportHandler) ExportStations(c echo.Context) error {
+	return h.export(c, export.TargetStations)
+}
+
+func (h *ExportHandler) export(c echo.Context, target export.Target) error {
+	format, err := export.ParseFormat(c.QueryParam("format"))
+	if err != nil || !target.Supports(format) {
+		return errorResponse(c, http.StatusBadRequest, "Unsupported export format")
+	}
+
+	filename := fmt.Sprintf("%s-%s.%s", target, time.Now().Format("20060102"), format.Extension())
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, format.ContentType())
+	res.Header().Set(echo.HeaderContentDisposition, fmt.Sprintf("attachment; filename=%q", filename))
+	res.WriteHeader(http.StatusOK)
+
+	// ヘッダー送信後はステータスを変更できないので、途中のエラーはログに残して接続を切る。
+	// 正常に終わったレスポンスと区別できるよう、チャンクの終端は送らない
+	if err := h.exporter.Export(c.Request().Context(), res, target, format); err != nil {
+		logging.FromContext(c.Request().Context()).Error("failed to export", "target", target, "error", err)
+
+		panic(http.ErrAbortHandler)
+	}
+
+	return nil
+}
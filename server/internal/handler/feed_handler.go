@@ -0,0 +1,141 @@
+package handler
+
+import (
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+// フィードに既定で表示するイベント。更新系のイベントはtypesで明示したときのみ返す
+var defaultFeedEventTypes = []model.EventType{
+	model.EventShopCreated,
+	model.EventReviewCreated,
+	model.EventImageAdded,
+	model.EventStationCreated,
+}
+
+type FeedHandler struct {
+	eventRepo repository.EventRepository
+}
+
+func NewFeedHandler(eventRepo repository.EventRepository) *FeedHandler {
+	return &FeedHandler{
+		eventRepo: eventRepo,
+	}
+}
+
+type Event struct {
+	ID string `json:"id"`
+
+	Type string `json:"type"`
+
+	Actor string `json:"actor,omitempty"`
+
+	Shop string `json:"shop,omitempty"`
+
+	Review string `json:"review,omitempty"`
+
+	Station string `json:"station,omitempty"`
+
+	Image string `json:"image,omitempty"`
+
+	Payload map[string]any `json:"payload,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+}
+
+func FromModelToEvent(e *model.Event) *Event {
+	idString := func(id uuid.UUID) string {
+		if id == uuid.Nil {
+			return ""
+		}
+
+		return id.String()
+	}
+
+	return &Event{
+		ID:        e.ID.String(),
+		Type:      string(e.Type),
+		Actor:     string(e.Actor),
+		Shop:      idString(e.Shop),
+		Review:    idString(e.Review),
+		Station:   idString(e.Station),
+		Image:     idString(e.Image),
+		Payload:   e.Payload,
+		CreatedAt: e.CreatedAt,
+	}
+}
+
+func (h *FeedHandler) GetFeed(c echo.Context) error {
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	limit, _ := parsePageParams(c)
+
+	filter := repository.EventFilter{
+		Types: defaultFeedEventTypes,
+	}
+
+	if types := c.QueryParam("types"); types != "" {
+		filter.Types = nil
+		for _, t := range strings.Split(types, ",") {
+			filter.Types = append(filter.Types, model.EventType(strings.TrimSpace(t)))
+		}
+	}
+
+	if stationID := c.QueryParam("station_id"); stationID != "" {
+		id, err := uuid.Parse(stationID)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid station ID")
+		}
+		filter.Station = id
+	}
+
+	if following, _ := strconv.ParseBool(c.QueryParam("following")); following {
+		filter.Following = model.UserID(userID)
+	}
+
+	if favorites, _ := strconv.ParseBool(c.QueryParam("favorites")); favorites {
+		filter.FavoritesOf = model.UserID(userID)
+	}
+
+	cursor := uuid.Nil
+	if token := c.QueryParam("cursor"); token != "" {
+		cursor, err = model.DecodeEventCursor(token)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid cursor")
+		}
+	}
+
+	// 1件多く取得して次のページがあるかを判定する
+	events, err := h.eventRepo.FindFeed(c.Request().Context(), filter, cursor, limit+1)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch feed")
+	}
+
+	res := &ListResponse[*Event]{}
+	if len(events) > limit {
+		events = events[:limit]
+		res.NextCursor = model.EncodeEventCursor(events[len(events)-1].ID)
+		res.Next = pageURL(c, map[string]string{
+			"limit":  strconv.Itoa(limit),
+			"cursor": res.NextCursor,
+		})
+	}
+
+	res.Items = make([]*Event, len(events))
+	for i, e := range events {
+		res.Items[i] = FromModelToEvent(e)
+	}
+
+	return listJSON(c, res)
+}
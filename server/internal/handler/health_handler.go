@@ -0,0 +1,89 @@
+package handler
+
+import (
+	"net/http"
+	"time"
+
+	"backend/internal/health"
+	"backend/internal/logging"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	healthStatusOK          = "ok"
+	healthStatusError       = "error"
+	healthStatusUnavailable = "unavailable"
+)
+
+type HealthHandler struct {
+	liveness  *health.Registry
+	readiness *health.Registry
+}
+
+func NewHealthHandler(liveness *health.Registry, readiness *health.Registry) *HealthHandler {
+	return &HealthHandler{
+		liveness:  liveness,
+		readiness: readiness,
+	}
+}
+
+type HealthCheck struct {
+	// ok または error
+	Status string `json:"status"`
+
+	// チェックにかかった時間（ミリ秒）
+	LatencyMs float64 `json:"latency_ms"`
+
+	Error string `json:"error,omitempty"`
+}
+
+type HealthResponse struct {
+	// ok または unavailable
+	Status string `json:"status"`
+
+	// コンポーネントごとの結果
+	Checks map[string]HealthCheck `json:"checks"`
+}
+
+// Livez reports whether the process is working. It does not depend on MySQL
+// or S3, so that an outage of either does not get the server restarted.
+func (h *HealthHandler) Livez(c echo.Context) error {
+	return h.respond(c, h.liveness)
+}
+
+// Readyz reports whether the server can serve requests: the database is
+// reachable and migrated, and the bucket is accessible.
+func (h *HealthHandler) Readyz(c echo.Context) error {
+	return h.respond(c, h.readiness)
+}
+
+func (h *HealthHandler) respond(c echo.Context, registry *health.Registry) error {
+	ctx := c.Request().Context()
+	report := registry.Run(ctx)
+
+	response := HealthResponse{
+		Status: healthStatusOK,
+		Checks: make(map[string]HealthCheck, len(report.Results)),
+	}
+	for _, result := range report.Results {
+		check := HealthCheck{
+			Status:    healthStatusOK,
+			LatencyMs: float64(result.Latency) / float64(time.Millisecond),
+		}
+		if result.Err != nil {
+			check.Status = healthStatusError
+			check.Error = result.Err.Error()
+			logging.FromContext(ctx).Warn("health check failed", "check", result.Name, "error", result.Err)
+		}
+		response.Checks[result.Name] = check
+	}
+
+	if !report.Healthy() {
+		response.Status = healthStatusUnavailable
+
+		return c.JSON(http.StatusServiceUnavailable, response)
+	}
+
+	return c.JSON(http.StatusOK, response)
+}
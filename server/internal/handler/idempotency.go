@@ -0,0 +1,190 @@
+package handler
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/binary"
+	"encoding/hex"
+	"errors"
+	"hash"
+	"io"
+	"mime"
+	"mime/multipart"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	idempotencyKeyHeader = "Idempotency-Key"
+	// idempotencyTimeout bounds requests with an Idempotency-Key.
+	idempotencyTimeout = 5 * time.Minute
+	// idempotencyLease is how long a key stays locked by a request in
+	// progress, in case the request never finishes. It outlives
+	// idempotencyTimeout, so that a retry cannot take over the key while the
+	// first request is still running.
+	idempotencyLease = 2 * idempotencyTimeout
+)
+
+// NewIdempotencyMiddleware makes POST requests safe to retry. The first
+// response to a request with an Idempotency-Key header is kept for ttl and
+// replayed to retries with the same key. It must run after the user ID
+// middleware.
+func NewIdempotencyMiddleware(repo repository.IdempotencyRepository, ttl time.Duration) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			key := c.Request().Header.Get(idempotencyKeyHeader)
+			if key == "" {
+				return next(c)
+			}
+
+			userID, err := GetUserID(c)
+			if err != nil {
+				return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+			}
+
+			body, err := io.ReadAll(c.Request().Body)
+			if err != nil {
+				return errorResponse(c, http.StatusBadRequest, "Failed to read request body")
+			}
+			c.Request().Body = io.NopCloser(bytes.NewReader(body))
+
+			requestHash, err := hashRequest(c.Request(), body)
+			if err != nil {
+				return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+			}
+
+			record, err := model.NewIdempotencyRecord(model.UserID(userID), key, requestHash, idempotencyLease)
+			if errors.Is(err, model.ErrInvalidIdempotencyKey) {
+				return errorResponse(c, http.StatusBadRequest, "Invalid Idempotency-Key")
+			}
+			if err != nil {
+				return errorResponse(c, http.StatusInternalServerError, "Failed to reserve Idempotency-Key")
+			}
+
+			existing, err := repo.Reserve(c.Request().Context(), record)
+			if errors.Is(err, repository.ErrConflict) {
+				return errorResponse(c, http.StatusConflict, "A request with the same Idempotency-Key is in progress")
+			}
+			if err != nil {
+				return errorResponse(c, http.StatusInternalServerError, "Failed to check Idempotency-Key")
+			}
+			if existing != nil {
+				return replayIdempotent(c, record, existing)
+			}
+
+			recorder := &responseRecorder{ResponseWriter: c.Response().Writer}
+			c.Response().Writer = recorder
+
+			reqCtx, cancel := context.WithTimeout(c.Request().Context(), idempotencyTimeout)
+			defer cancel()
+			c.SetRequest(c.Request().WithContext(reqCtx))
+
+			err = next(c)
+
+			// レスポンスの保存に失敗しても、リクエスト自体の結果は返す
+			ctx := context.WithoutCancel(c.Request().Context())
+			status := c.Response().Status
+			if err != nil || !c.Response().Committed || !idempotentStatus(status) {
+				if err := repo.Release(ctx, record); err != nil {
+					logging.FromContext(ctx).Error("failed to release Idempotency-Key", "error", err)
+				}
+
+				return err
+			}
+
+			record.Complete(status, c.Response().Header().Get(echo.HeaderContentType), recorder.body.Bytes(), ttl)
+			if err := repo.Complete(ctx, record); err != nil {
+				logging.FromContext(ctx).Error("failed to store response for Idempotency-Key", "error", err)
+			}
+
+			return nil
+		}
+	}
+}
+
+func replayIdempotent(c echo.Context, record, existing *model.IdempotencyRecord) error {
+	if existing.RequestHash != record.RequestHash {
+		return errorResponse(c, http.StatusUnprocessableEntity, "Idempotency-Key was used for a different request")
+	}
+	if !existing.Completed() {
+		return errorResponse(c, http.StatusConflict, "A request with the same Idempotency-Key is in progress")
+	}
+
+	c.Response().Header().Set("Idempotent-Replayed", "true")
+
+	return c.Blob(existing.StatusCode, existing.ContentType, existing.Body)
+}
+
+// idempotentStatus reports whether a response with status is final, so that
+// retries get it replayed. Server errors, conflicts and rate limits may
+// succeed when retried, so the key is released instead.
+func idempotentStatus(status int) bool {
+	return status < http.StatusInternalServerError &&
+		status != http.StatusConflict &&
+		status != http.StatusTooManyRequests
+}
+
+// hashRequest identifies a request by its method, URI and body. Multipart
+// bodies are hashed by their parts, because clients choose a new boundary
+// every time they encode the same form.
+func hashRequest(req *http.Request, body []byte) (string, error) {
+	h := sha256.New()
+	writeHashField(h, req.Method)
+	writeHashField(h, req.URL.RequestURI())
+
+	mediaType, params, err := mime.ParseMediaType(req.Header.Get(echo.HeaderContentType))
+	if err != nil || !strings.HasPrefix(mediaType, "multipart/") {
+		h.Write(body)
+
+		return hex.EncodeToString(h.Sum(nil)), nil
+	}
+
+	reader := multipart.NewReader(bytes.NewReader(body), params["boundary"])
+	for {
+		part, err := reader.NextPart()
+		if errors.Is(err, io.EOF) {
+			break
+		}
+		if err != nil {
+			return "", err
+		}
+		writeHashField(h, part.FormName())
+		writeHashField(h, part.FileName())
+		content, err := io.ReadAll(part)
+		if err != nil {
+			return "", err
+		}
+		writeHashField(h, string(content))
+	}
+
+	return hex.EncodeToString(h.Sum(nil)), nil
+}
+
+// writeHashField writes s prefixed with its length, so that adjacent fields
+// cannot be confused with each other.
+func writeHashField(h hash.Hash, s string) {
+	var length [8]byte
+	binary.LittleEndian.PutUint64(length[:], uint64(len(s)))
+	h.Write(length[:])
+	h.Write([]byte(s))
+}
+
+// responseRecorder copies the response body while writing it.
+type responseRecorder struct {
+	http.ResponseWriter
+	body bytes.Buffer
+}
+
+func (r *responseRecorder) Write(b []byte) (int, error) {
+	r.body.Write(b)
+
+	return r.ResponseWriter.Write(b)
+}
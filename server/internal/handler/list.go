@@ -0,0 +1,147 @@
+package handler
+
+import (
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"github.com/labstack/echo/v4"
+)
+
+// ListProfile is the media type profile (RFC 6906) a client puts in the Accept
+// header to receive list endpoints wrapped in a ListResponse, e.g.
+//
+//	Accept: application/json; profile="urn:h25s01:list"
+//
+// Without it list endpoints keep returning bare JSON arrays.
+const ListProfile = "urn:h25s01:list"
+
+const defaultPageLimit = 30
+
+type ListResponse[T any] struct {
+	Items []T `json:"items"`
+
+	// 条件に一致する総件数
+	Total *int `json:"total,omitempty"`
+
+	// 次/前のページのURL。Linkヘッダーと同じ値
+	Next string `json:"next,omitempty"`
+
+	Prev string `json:"prev,omitempty"`
+
+	// カーソルページネーションに対応した一覧でのみ返される
+	NextCursor string `json:"next_cursor,omitempty"`
+}
+
+func acceptsListProfile(c echo.Context) bool {
+	for _, part := range strings.Split(c.Request().Header.Get(echo.HeaderAccept), ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil || mediaType != echo.MIMEApplicationJSON {
+			continue
+		}
+
+		for _, profile := range strings.Fields(params["profile"]) {
+			if profile == ListProfile {
+				return true
+			}
+		}
+	}
+
+	return false
+}
+
+func parsePageParams(c echo.Context) (limit int, offset int) {
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultPageLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	offset, err = strconv.Atoi(c.QueryParam("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	return limit, offset
+}
+
+// paginate slices an in-memory result set for endpoints whose repositories
+// return every row at once.
+func paginate[T any](items []T, limit, offset int) []T {
+	if offset >= len(items) {
+		return []T{}
+	}
+
+	end := offset + limit
+	if end > len(items) {
+		end = len(items)
+	}
+
+	return items[offset:end]
+}
+
+func newOffsetListResponse[T any](c echo.Context, items []T, total, limit, offset int) *ListResponse[T] {
+	res := &ListResponse[T]{
+		Items: items,
+		Total: &total,
+	}
+
+	if offset+limit < total {
+		res.Next = pageURL(c, map[string]string{
+			"limit":  strconv.Itoa(limit),
+			"offset": strconv.Itoa(offset + limit),
+		})
+	}
+
+	if offset > 0 {
+		prev := offset - limit
+		if prev < 0 {
+			prev = 0
+		}
+		res.Prev = pageURL(c, map[string]string{
+			"limit":  strconv.Itoa(limit),
+			"offset": strconv.Itoa(prev),
+		})
+	}
+
+	return res
+}
+
+// pageURL returns the current request URI with the given query parameters
+// replaced.
+func pageURL(c echo.Context, params map[string]string) string {
+	u := *c.Request().URL
+	q := u.Query()
+	for k, v := range params {
+		q.Set(k, v)
+	}
+	u.RawQuery = q.Encode()
+
+	return u.RequestURI()
+}
+
+// listJSON writes a ListResponse along with an RFC 8288 Link header that
+// mirrors its next/prev URLs.
+func listJSON[T any](c echo.Context, res *ListResponse[T]) error {
+	var links []string
+	if res.Next != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="next"`, res.Next))
+	}
+	if res.Prev != "" {
+		links = append(links, fmt.Sprintf(`<%s>; rel="prev"`, res.Prev))
+	}
+	if len(links) > 0 {
+		c.Response().Header().Set("Link", strings.Join(links, ", "))
+	}
+
+	c.Response().Header().Set(
+		echo.HeaderContentType,
+		fmt.Sprintf("%s; profile=%q", echo.MIMEApplicationJSON, ListProfile),
+	)
+
+	return c.JSON(http.StatusOK, res)
+}
@@ -0,0 +1,378 @@
+package handler
+
+import (
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+	"backend/internal/metrics"
+	"context"
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"time"
+	"unicode/utf8"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const maxMenuCategoryLength = 32
+
+// shop detail に載せるおすすめメニューの件数
+const recommendedDishLimit = 3
+
+type MenuHandler struct {
+	menuRepo  repository.MenuItemRepository
+	shopRepo  repository.ShopRepository
+	fileRepo  repository.FileRepository
+	auditRepo repository.AuditLogRepository
+}
+
+func NewMenuHandler(
+	menuRepo repository.MenuItemRepository,
+	shopRepo repository.ShopRepository,
+	fileRepo repository.FileRepository,
+	auditRepo repository.AuditLogRepository,
+) *MenuHandler {
+	return &MenuHandler{
+		menuRepo:  menuRepo,
+		shopRepo:  shopRepo,
+		fileRepo:  fileRepo,
+		auditRepo: auditRepo,
+	}
+}
+
+type MenuItem struct {
+	ID string `json:"id"`
+
+	// メニューを提供している店舗ID
+	Shop string `json:"shop"`
+
+	Name string `json:"name"`
+
+	// 税込価格（円）
+	Price int `json:"price"`
+
+	Category string `json:"category,omitempty"`
+
+	// 画像ID
+	Image string `json:"image,omitempty"`
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+func FromModelToMenuItem(m *model.MenuItem) *MenuItem {
+	image := ""
+	if m.Image != nil {
+		image = m.Image.ID.String()
+	}
+
+	return &MenuItem{
+		ID:        m.ID.String(),
+		Shop:      m.Shop.String(),
+		Name:      string(m.Name),
+		Price:     int(m.Price),
+		Category:  m.Category,
+		Image:     image,
+		CreatedAt: m.CreatedAt,
+		UpdatedAt: m.UpdatedAt,
+	}
+}
+
+type RecommendedDish struct {
+	MenuItem
+
+	// 料理ごとの評価の平均（0から3まで）
+	AverageRating float64 `json:"average_rating"`
+
+	// 評価したレビューの数
+	RatingCount int `json:"rating_count"`
+}
+
+func FromModelToRecommendedDish(m *model.RecommendedDish) *RecommendedDish {
+	return &RecommendedDish{
+		MenuItem:      *FromModelToMenuItem(m.Item),
+		AverageRating: m.Average,
+		RatingCount:   m.Count,
+	}
+}
+
+type APIV1ShopsIDMenuPostRequest struct {
+	Name string `json:"name"`
+
+	// 税込価格（円）
+	Price *int `json:"price"`
+
+	Category string `json:"category,omitempty"`
+
+	// アップロード済みの画像ID
+	Image string `json:"image,omitempty"`
+}
+
+func (req *APIV1ShopsIDMenuPostRequest) parse() (model.MenuItemName, model.Price, *model.ImageFile, error) {
+	name, err := model.NewMenuItemName(req.Name)
+	if err != nil {
+		return "", 0, nil, errors.New("Invalid menu item name")
+	}
+
+	if req.Price == nil {
+		return "", 0, nil, errors.New("Price is required")
+	}
+	price, err := model.NewPrice(*req.Price)
+	if err != nil {
+		return "", 0, nil, errors.New("Invalid price")
+	}
+
+	if utf8.RuneCountInString(req.Category) > maxMenuCategoryLength {
+		return "", 0, nil, errors.New("Invalid category")
+	}
+
+	var image *model.ImageFile
+	if req.Image != "" {
+		imageID, err := uuid.Parse(req.Image)
+		if err != nil {
+			return "", 0, nil, errors.New("Invalid image ID")
+		}
+		image = model.NewImageFile(imageID)
+	}
+
+	return name, price, image, nil
+}
+
+func (h *MenuHandler) GetMenu(c echo.Context) error {
+	shopID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid shop ID format")
+	}
+
+	if _, err := h.shopRepo.FindByID(c.Request().Context(), shopID); err != nil {
+		return shopLookupError(c, err)
+	}
+
+	items, err := h.menuRepo.FindByShop(c.Request().Context(), shopID)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch menu")
+	}
+
+	responses := make([]*MenuItem, len(items))
+	for i, item := range items {
+		responses[i] = FromModelToMenuItem(item)
+	}
+
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (h *MenuHandler) CreateMenuItem(c echo.Context) error {
+	shopID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid shop ID format")
+	}
+
+	var req APIV1ShopsIDMenuPostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	name, price, image, err := req.parse()
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if _, err := h.shopRepo.FindByID(c.Request().Context(), shopID); err != nil {
+		return shopLookupError(c, err)
+	}
+
+	item, err := model.NewMenuItem(shopID, name, price, req.Category, image)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to create menu item")
+	}
+
+	if err := h.menuRepo.Save(c.Request().Context(), item); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save menu item")
+	}
+
+	return c.JSON(http.StatusCreated, FromModelToMenuItem(item))
+}
+
+func (h *MenuHandler) UpdateMenuItem(c echo.Context) error {
+	item, err := h.findMenuItem(c)
+	if err != nil {
+		return menuItemLookupError(c, err)
+	}
+
+	var req APIV1ShopsIDMenuPostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request body")
+	}
+
+	name, price, image, err := req.parse()
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	item.Name = name
+	item.Price = price
+	item.Category = req.Category
+	item.Image = image
+	item.UpdatedAt = time.Now()
+
+	if err := h.menuRepo.Save(c.Request().Context(), item); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save menu item")
+	}
+
+	return c.JSON(http.StatusOK, FromModelToMenuItem(item))
+}
+
+func (h *MenuHandler) DeleteMenuItem(c echo.Context) error {
+	item, err := h.findMenuItem(c)
+	if err != nil {
+		return menuItemLookupError(c, err)
+	}
+
+	if err := h.menuRepo.Delete(c.Request().Context(), item.ID); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to delete menu item")
+	}
+
+	if item.Image != nil {
+		_ = h.fileRepo.DeleteImage(c.Request().Context(), item.Image.ID)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Menu item deleted successfully",
+	})
+}
+
+func (h *MenuHandler) UploadMenuItemImage(c echo.Context) error {
+	item, err := h.findMenuItem(c)
+	if err != nil {
+		return menuItemLookupError(c, err)
+	}
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid image file")
+	}
+
+	contentType := fileHeader.Header.Get("Content-Type")
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to open image file")
+	}
+	defer func(file multipart.File) {
+		err := file.Close()
+		if err != nil {
+			logging.FromContext(c.Request().Context()).Error("failed to close image file", "error", err)
+		}
+	}(file)
+
+	imageID, err := h.fileRepo.UploadImage(c.Request().Context(), contentType, file)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save image file")
+	}
+
+	// メニューの画像は1枚なので、古い画像は置き換える
+	previous := item.Image
+	item.Image = model.NewImageFile(imageID)
+	item.UpdatedAt = time.Now()
+
+	if err := h.menuRepo.Save(c.Request().Context(), item); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save menu item with new image")
+	}
+
+	metrics.ImagesUploaded.WithLabelValues("menu_item").Inc()
+
+	image := AuditImage{ID: imageID.String(), MenuItem: item.ID.String()}
+	recordAudit(c, h.auditRepo, model.AuditActionCreate, model.AuditEntityImage, imageID, nil, auditSnapshot(image))
+
+	if previous != nil {
+		_ = h.fileRepo.DeleteImage(c.Request().Context(), previous.ID)
+		image := AuditImage{ID: previous.ID.String(), MenuItem: item.ID.String()}
+		recordAudit(c, h.auditRepo, model.AuditActionDelete, model.AuditEntityImage, previous.ID, auditSnapshot(image), nil)
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{
+		"id": imageID.String(),
+	})
+}
+
+var (
+	errInvalidMenuItemID = errors.New("invalid menu item ID")
+	errDishNotOnMenu     = errors.New("dish is not on the menu of the shop")
+)
+
+// findMenuItem loads the menu item in the path and checks that it belongs to
+// the shop in the path.
+func (h *MenuHandler) findMenuItem(c echo.Context) (*model.MenuItem, error) {
+	shopID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errInvalidMenuItemID
+	}
+
+	itemID, err := uuid.Parse(c.Param("itemId"))
+	if err != nil {
+		return nil, errInvalidMenuItemID
+	}
+
+	item, err := h.menuRepo.FindByID(c.Request().Context(), itemID)
+	if err != nil {
+		return nil, err
+	}
+
+	if item.Shop != shopID {
+		return nil, repository.ErrNotFound
+	}
+
+	return item, nil
+}
+
+func menuItemLookupError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errInvalidMenuItemID):
+		return errorResponse(c, http.StatusBadRequest, "Invalid menu item ID")
+	case errors.Is(err, repository.ErrNotFound):
+		return errorResponse(c, http.StatusNotFound, "Menu item not found")
+	default:
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch menu item")
+	}
+}
+
+func shopLookupError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrNotFound) {
+		return errorResponse(c, http.StatusNotFound, "Shop not found")
+	}
+
+	return errorResponse(c, http.StatusInternalServerError, "Failed to fetch shop")
+}
+
+// validateDishes checks that every rated dish is on the menu of the shop.
+func validateDishes(
+	ctx context.Context,
+	menuRepo repository.MenuItemRepository,
+	shopID uuid.UUID,
+	dishes []model.DishRating,
+) error {
+	if len(dishes) == 0 {
+		return nil
+	}
+
+	items, err := menuRepo.FindByShop(ctx, shopID)
+	if err != nil {
+		return err
+	}
+
+	menu := make(map[uuid.UUID]struct{}, len(items))
+	for _, item := range items {
+		menu[item.ID] = struct{}{}
+	}
+
+	for _, dish := range dishes {
+		if _, ok := menu[dish.MenuItem]; !ok {
+			return errDishNotOnMenu
+		}
+	}
+
+	return nil
+}
@@ -1,30 +1,91 @@
 package handler
 
 import (
-	"github.com/labstack/echo/v4"
 	"net/http"
 	"os"
 	"strconv"
+	"sync"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/labstack/echo/v4"
 )
 
 const UserIDKey = "userId"
 
-func UserIDMiddleware(next echo.HandlerFunc) echo.HandlerFunc {
-	return func(c echo.Context) error {
-		username := c.Request().Header.Get("X-Forwarded-User")
-		if username != "" {
+// NewUserIDMiddleware authenticates the caller and lazily creates a users row
+// the first time a user is seen.
+func NewUserIDMiddleware(userRepo repository.UserRepository) echo.MiddlewareFunc {
+	// 作成済みのユーザーは毎回DBに問い合わせないようにキャッシュする
+	var knownUsers sync.Map
+
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			username := c.Request().Header.Get("X-Forwarded-User")
+			if username == "" {
+				isDebug, err := strconv.ParseBool(os.Getenv("DEBUG"))
+				if err != nil || !isDebug {
+					return echo.NewHTTPError(http.StatusUnauthorized)
+				}
+
+				username = "traP"
+			}
+
 			c.Set(UserIDKey, username)
+			ctx := model.WithUserID(c.Request().Context(), model.UserID(username))
+			ctx = logging.WithLogger(ctx, logging.FromContext(ctx).With("user", username))
+			c.SetRequest(c.Request().WithContext(ctx))
+
+			if _, ok := knownUsers.Load(username); !ok {
+				// ユーザー作成に失敗してもリクエスト自体は続行する
+				if err := userRepo.EnsureExists(ctx, model.UserID(username)); err != nil {
+					logging.FromContext(ctx).Error("failed to ensure user", "error", err)
+				} else {
+					knownUsers.Store(username, struct{}{})
+				}
+			}
 
 			return next(c)
 		}
+	}
+}
 
-		isDebug, err := strconv.ParseBool(os.Getenv("DEBUG"))
-		if err != nil || !isDebug {
-			return echo.NewHTTPError(http.StatusUnauthorized)
-		}
+// Admins is the set of users allowed to use the admin APIs and to moderate
+// content.
+type Admins map[string]struct{}
 
-		c.Set(UserIDKey, "traP")
+func NewAdmins(admins []string) Admins {
+	allowed := make(Admins, len(admins))
+	for _, admin := range admins {
+		allowed[admin] = struct{}{}
+	}
+
+	return allowed
+}
+
+func (a Admins) Contains(userID string) bool {
+	_, ok := a[userID]
+
+	return ok
+}
+
+// NewAdminMiddleware rejects requests from users that are not in admins. It
+// must run after the user ID middleware.
+func NewAdminMiddleware(admins Admins) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, err := GetUserID(c)
+			if err != nil {
+				return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+			}
+
+			if !admins.Contains(userID) {
+				return errorResponse(c, http.StatusForbidden, "Admin privileges required")
+			}
 
-		return next(c)
+			return next(c)
+		}
 	}
 }
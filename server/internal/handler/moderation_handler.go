@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const defaultModerationLogLimit = 50
+
+type ModerationHandler struct {
+	moderationRepo repository.ModerationRepository
+	reviewRepo     repository.ReviewRepository
+	fileRepo       repository.FileRepository
+}
+
+func NewModerationHandler(
+	moderationRepo repository.ModerationRepository,
+	reviewRepo repository.ReviewRepository,
+	fileRepo repository.FileRepository,
+) *ModerationHandler {
+	return &ModerationHandler{
+		moderationRepo: moderationRepo,
+		reviewRepo:     reviewRepo,
+		fileRepo:       fileRepo,
+	}
+}
+
+type ReviewReport struct {
+	ID string `json:"id"`
+
+	Review string `json:"review"`
+
+	// 報告したユーザーのID
+	Reporter string `json:"reporter"`
+
+	// spam, offensive, off_topic, personal_info, other のいずれか
+	Reason string `json:"reason"`
+
+	Note string `json:"note,omitempty"`
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+func FromModelToReviewReport(r *model.ReviewReport) *ReviewReport {
+	return &ReviewReport{
+		ID:        r.ID.String(),
+		Review:    r.Review.String(),
+		Reporter:  string(r.Reporter),
+		Reason:    string(r.Reason),
+		Note:      r.Note,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+type ModerationLog struct {
+	ID string `json:"id"`
+
+	Review string `json:"review"`
+
+	// 対象レビューの投稿者のユーザーID
+	Author string `json:"author"`
+
+	// 操作したモデレーターのユーザーID
+	Moderator string `json:"moderator"`
+
+	// hide, unhide, delete, dismiss のいずれか
+	Action string `json:"action"`
+
+	Note string `json:"note,omitempty"`
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+func FromModelToModerationLog(l *model.ModerationLog) *ModerationLog {
+	return &ModerationLog{
+		ID:        l.ID.String(),
+		Review:    l.Review.String(),
+		Author:    string(l.Author),
+		Moderator: string(l.Moderator),
+		Action:    string(l.Action),
+		Note:      l.Note,
+		CreatedAt: l.CreatedAt,
+	}
+}
+
+type ModerationQueueItem struct {
+	Review *Review `json:"review"`
+
+	// 未処理の報告。古い順
+	Reports []*ReviewReport `json:"reports"`
+}
+
+type APIV1ReviewsIDReportsPostRequest struct {
+	Reason string `json:"reason"`
+
+	Note string `json:"note,omitempty"`
+}
+
+type APIV1ModerationReviewsIDActionsPostRequest struct {
+	Action string `json:"action"`
+
+	Note string `json:"note,omitempty"`
+}
+
+// CreateReviewReport flags a review as inappropriate for the moderators.
+func (h *ModerationHandler) CreateReviewReport(c echo.Context) error {
+	review, err := h.findReview(c)
+	if err != nil {
+		return reviewLookupError(c, err)
+	}
+
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+	// 非表示のレビューは投稿者しか見られないため報告できない
+	if !review.CanView(model.UserID(userID), false) {
+		return errorResponse(c, http.StatusNotFound, "Review not found")
+	}
+
+	var req APIV1ReviewsIDReportsPostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	reason, err := model.ParseReportReason(req.Reason)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid reason: must be one of spam, offensive, off_topic, personal_info, other")
+	}
+
+	report, err := model.NewReviewReport(review, model.UserID(userID), reason, req.Note)
+	if errors.Is(err, model.ErrInvalidReviewReport) {
+		return errorResponse(c, http.StatusBadRequest, "Invalid report: cannot report your own review, note is required for other and must be at most 255 characters")
+	}
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	if err := h.moderationRepo.SaveReport(c.Request().Context(), report); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save review report")
+	}
+
+	return c.JSON(http.StatusCreated, FromModelToReviewReport(report))
+}
+
+// GetModerationQueue returns the reviews with open reports, the review
+// reported first coming first.
+func (h *ModerationHandler) GetModerationQueue(c echo.Context) error {
+	reports, err := h.moderationRepo.FindOpenReports(c.Request().Context())
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch review reports")
+	}
+
+	items := []*ModerationQueueItem{}
+	byReview := make(map[uuid.UUID]*ModerationQueueItem)
+	for _, report := range reports {
+		item, ok := byReview[report.Review]
+		if !ok {
+			review, err := h.reviewRepo.FindByID(c.Request().Context(), report.Review)
+			if err != nil {
+				return errorResponse(c, http.StatusInternalServerError, "Failed to fetch review")
+			}
+			item = &ModerationQueueItem{Review: &Review{}}
+			item.Review.FromModel(review)
+			byReview[report.Review] = item
+			items = append(items, item)
+		}
+		item.Reports = append(item.Reports, FromModelToReviewReport(report))
+	}
+
+	return c.JSON(http.StatusOK, items)
+}
+
+// ModerateReview hides, unhides, deletes the review or dismisses its reports.
+// The open reports of the review are resolved either way.
+func (h *ModerationHandler) ModerateReview(c echo.Context) error {
+	review, err := h.findReview(c)
+	if err != nil {
+		return reviewLookupError(c, err)
+	}
+
+	var req APIV1ModerationReviewsIDActionsPostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	action, err := model.ParseModerationAction(req.Action)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid action: must be one of hide, unhide, delete, dismiss")
+	}
+
+	log, err := model.NewModerationLog(review, model.UserID(userID), action, req.Note)
+	if errors.Is(err, model.ErrInvalidModerationAction) {
+		return errorResponse(c, http.StatusBadRequest, "Invalid action: the review is already in that state or the note is longer than 255 characters")
+	}
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	if err := h.moderationRepo.Moderate(c.Request().Context(), log); err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "Review not found")
+		}
+
+		return errorResponse(c, http.StatusInternalServerError, "Failed to moderate review")
+	}
+
+	if action == model.ModerationDelete {
+		for _, image := range review.Images {
+			_ = h.fileRepo.DeleteImage(c.Request().Context(), image.ID)
+		}
+	}
+
+	return c.JSON(http.StatusOK, FromModelToModerationLog(log))
+}
+
+// GetModerationLogs returns the actions taken by moderators, newest first.
+func (h *ModerationHandler) GetModerationLogs(c echo.Context) error {
+	reviewID := uuid.Nil
+	if review := c.QueryParam("review"); review != "" {
+		id, err := uuid.Parse(review)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid review ID")
+		}
+		reviewID = id
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 {
+		limit = defaultModerationLogLimit
+	}
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	offset, err := strconv.Atoi(c.QueryParam("offset"))
+	if err != nil || offset < 0 {
+		offset = 0
+	}
+
+	logs, err := h.moderationRepo.FindLogs(c.Request().Context(), reviewID, limit, offset)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch moderation logs")
+	}
+
+	responses := make([]*ModerationLog, len(logs))
+	for i, log := range logs {
+		responses[i] = FromModelToModerationLog(log)
+	}
+
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (h *ModerationHandler) findReview(c echo.Context) (*model.Review, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errInvalidReviewID
+	}
+
+	return h.reviewRepo.FindByID(c.Request().Context(), id)
+}
+
+var errInvalidReviewID = errors.New("invalid review ID")
+
+func reviewLookupError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errInvalidReviewID):
+		return errorResponse(c, http.StatusBadRequest, "Invalid review ID")
+	case errors.Is(err, repository.ErrNotFound):
+		return errorResponse(c, http.StatusNotFound, "Review not found")
+	default:
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch review")
+	}
+}
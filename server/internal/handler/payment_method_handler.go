@@ -0,0 +1,127 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/labstack/echo/v4"
+)
+
+type PaymentMethodHandler struct {
+	paymentMethodRepo repository.PaymentMethodRepository
+}
+
+func NewPaymentMethodHandler(paymentMethodRepo repository.PaymentMethodRepository) *PaymentMethodHandler {
+	return &PaymentMethodHandler{
+		paymentMethodRepo: paymentMethodRepo,
+	}
+}
+
+type PaymentMethod struct {
+	// 店舗の payment_methods に入る識別子
+	Code string `json:"code"`
+
+	// 表示名
+	Name string `json:"name"`
+
+	// cash, credit_card, qr_code, ic_card のいずれか
+	Category string `json:"category"`
+
+	// 入力時に受け付ける表記ゆれ
+	Aliases []string `json:"aliases"`
+}
+
+func FromModelToPaymentMethod(m *model.PaymentMethod) *PaymentMethod {
+	aliases := m.Aliases
+	if aliases == nil {
+		aliases = []string{}
+	}
+
+	return &PaymentMethod{
+		Code:     m.Code,
+		Name:     m.Name,
+		Category: string(m.Category),
+		Aliases:  aliases,
+	}
+}
+
+func (h *PaymentMethodHandler) GetPaymentMethods(c echo.Context) error {
+	catalog, err := h.paymentMethodRepo.FindCatalog(c.Request().Context())
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch payment methods")
+	}
+
+	responses := make([]*PaymentMethod, len(catalog.Methods()))
+	for i, method := range catalog.Methods() {
+		responses[i] = FromModelToPaymentMethod(method)
+	}
+
+	return c.JSON(http.StatusOK, responses)
+}
+
+// normalizePaymentMethods converts the payment methods of a shop request to
+// catalog codes.
+func normalizePaymentMethods(
+	c echo.Context,
+	paymentMethodRepo repository.PaymentMethodRepository,
+	names []string,
+) ([]string, error) {
+	catalog, err := paymentMethodRepo.FindCatalog(c.Request().Context())
+	if err != nil {
+		return nil, err
+	}
+
+	return catalog.Normalize(names)
+}
+
+func paymentMethodError(c echo.Context, err error) error {
+	if errors.Is(err, model.ErrInvalidPaymentMethod) {
+		return errorResponse(c, http.StatusBadRequest, "Invalid payment method: must be one of GET /api/v1/payment-methods")
+	}
+
+	return errorResponse(c, http.StatusInternalServerError, "Failed to fetch payment methods")
+}
+
+// filterPaymentMethods keeps the shops accepting every payment method given
+// by the comma separated payment_methods query parameter.
+func filterPaymentMethods(
+	c echo.Context,
+	paymentMethodRepo repository.PaymentMethodRepository,
+	shops []*model.Shop,
+) ([]*model.Shop, error) {
+	param := c.QueryParam("payment_methods")
+	if param == "" {
+		return shops, nil
+	}
+
+	codes, err := normalizePaymentMethods(c, paymentMethodRepo, strings.Split(param, ","))
+	if err != nil {
+		return nil, err
+	}
+
+	filtered := make([]*model.Shop, 0, len(shops))
+	for _, shop := range shops {
+		accepted := make(map[string]struct{}, len(shop.PaymentMethods))
+		for _, code := range shop.PaymentMethods {
+			accepted[code] = struct{}{}
+		}
+
+		matched := true
+		for _, code := range codes {
+			if _, ok := accepted[code]; !ok {
+				matched = false
+
+				break
+			}
+		}
+		if matched {
+			filtered = append(filtered, shop)
+		}
+	}
+
+	return filtered, nil
+}
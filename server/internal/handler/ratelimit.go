@@ -0,0 +1,93 @@
+package handler
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/logging"
+	"backend/internal/ratelimit"
+
+	"github.com/labstack/echo/v4"
+)
+
+// NewRateLimitMiddleware limits the request rate of each user per route. It
+// must run after the user ID middleware.
+func NewRateLimitMiddleware(limiter *ratelimit.Limiter) echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			userID, err := GetUserID(c)
+			if err != nil {
+				return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+			}
+
+			ok, retryAfter, err := limiter.Allow(
+				c.Request().Context(),
+				userID,
+				c.Request().Method,
+				c.Path(),
+				time.Now(),
+			)
+			// 制限の確認に失敗してもリクエスト自体は続行する
+			if err != nil {
+				logging.FromContext(c.Request().Context()).Error("failed to check rate limit", "error", err)
+
+				return next(c)
+			}
+			if !ok {
+				return tooManyRequests(c, retryAfter, "Too many requests")
+			}
+
+			return next(c)
+		}
+	}
+}
+
+// NewQuotaMiddleware returns a middleware counting successful requests toward
+// the daily quota of the user. Failed requests do not use up the quota. It must
+// run after the user ID middleware.
+func NewQuotaMiddleware(limiter *ratelimit.Limiter) func(quota ratelimit.Quota) echo.MiddlewareFunc {
+	return func(quota ratelimit.Quota) echo.MiddlewareFunc {
+		return func(next echo.HandlerFunc) echo.HandlerFunc {
+			return func(c echo.Context) error {
+				userID, err := GetUserID(c)
+				if err != nil {
+					return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+				}
+
+				now := time.Now()
+				ok, retryAfter, err := limiter.Reserve(c.Request().Context(), userID, quota, now)
+				if err != nil {
+					logging.FromContext(c.Request().Context()).Error("failed to check quota", "quota", quota, "error", err)
+
+					return next(c)
+				}
+				if !ok {
+					return tooManyRequests(c, retryAfter, "Daily quota exceeded")
+				}
+
+				err = next(c)
+				if err != nil || c.Response().Status >= http.StatusBadRequest {
+					// リクエストの中断でキャンセルされていても返却はする
+					ctx := context.WithoutCancel(c.Request().Context())
+					if err := limiter.Release(ctx, userID, quota, now); err != nil {
+						logging.FromContext(ctx).Error("failed to release quota", "quota", quota, "error", err)
+					}
+				}
+
+				return err
+			}
+		}
+	}
+}
+
+// tooManyRequests responds 429 telling the client when to retry in whole
+// seconds.
+func tooManyRequests(c echo.Context, retryAfter time.Duration, msg string) error {
+	seconds := max(1, int(math.Ceil(retryAfter.Seconds())))
+	c.Response().Header().Set("Retry-After", strconv.Itoa(seconds))
+
+	return errorResponse(c, http.StatusTooManyRequests, msg)
+}
@@ -1,9 +1,13 @@
 package handler
 
 import (
+	"backend/internal/contentpolicy"
 	"backend/internal/domain/model"
 	"backend/internal/domain/repository"
-	"log"
+	"backend/internal/logging"
+	"backend/internal/metrics"
+	"context"
+	"errors"
 	"mime/multipart"
 	"net/http"
 	"strconv"
@@ -13,18 +17,37 @@ import (
 	"github.com/labstack/echo/v4"
 )
 
-const maxContentLength = 1024
 const maxImages = 4
+const maxDishes = 10
+const maxPageLimit = 100
 
 type ReviewHandler struct {
-	reviewRepo repository.ReviewRepository
-	fileRepo   repository.FileRepository
+	reviewRepo  repository.ReviewRepository
+	fileRepo    repository.FileRepository
+	webhookRepo repository.WebhookRepository
+	menuRepo    repository.MenuItemRepository
+	policy      *contentpolicy.Policy
+	auditRepo   repository.AuditLogRepository
+	admins      Admins
 }
 
-func NewReviewHandler(reviewRepo repository.ReviewRepository, fileRepo repository.FileRepository) *ReviewHandler {
+func NewReviewHandler(
+	reviewRepo repository.ReviewRepository,
+	fileRepo repository.FileRepository,
+	webhookRepo repository.WebhookRepository,
+	menuRepo repository.MenuItemRepository,
+	policy *contentpolicy.Policy,
+	auditRepo repository.AuditLogRepository,
+	admins Admins,
+) *ReviewHandler {
 	return &ReviewHandler{
-		reviewRepo: reviewRepo,
-		fileRepo:   fileRepo,
+		reviewRepo:  reviewRepo,
+		fileRepo:    fileRepo,
+		webhookRepo: webhookRepo,
+		menuRepo:    menuRepo,
+		policy:      policy,
+		auditRepo:   auditRepo,
+		admins:      admins,
 	}
 }
 
@@ -47,6 +70,20 @@ type Review struct {
 	Content string `json:"content,omitempty"`
 
 	Images []string `json:"images,omitempty"`
+
+	// 食べたメニューごとの評価
+	Dishes []ReviewDish `json:"dishes,omitempty"`
+
+	// モデレーターによって非表示にされたかどうか。投稿者とモデレーターにのみ返される
+	Hidden bool `json:"hidden,omitempty"`
+}
+
+type ReviewDish struct {
+	// メニューID
+	MenuItem string `json:"menu_item"`
+
+	// 評価（0から3まで）
+	Rating int32 `json:"rating"`
 }
 
 func (d *Review) FromModel(r *model.Review) {
@@ -55,6 +92,14 @@ func (d *Review) FromModel(r *model.Review) {
 		images[i] = img.ID.String()
 	}
 
+	dishes := make([]ReviewDish, len(r.Dishes))
+	for i, dish := range r.Dishes {
+		dishes[i] = ReviewDish{
+			MenuItem: dish.MenuItem.String(),
+			Rating:   int32(dish.Rating),
+		}
+	}
+
 	d.ID = r.ID.String()
 	d.Author = string(r.Author)
 	d.Shop = r.Shop.String()
@@ -63,6 +108,8 @@ func (d *Review) FromModel(r *model.Review) {
 	d.UpdatedAt = r.UpdatedAt
 	d.Content = r.Content
 	d.Images = images
+	d.Dishes = dishes
+	d.Hidden = r.Hidden
 }
 
 type APIV1ReviewsPostRequest struct {
@@ -73,6 +120,8 @@ type APIV1ReviewsPostRequest struct {
 	Content string `json:"content,omitempty"`
 
 	Images []string `json:"images,omitempty"`
+
+	Dishes []ReviewDish `json:"dishes,omitempty"`
 }
 
 func (h *ReviewHandler) GetReviews(c echo.Context) error {
@@ -103,19 +152,99 @@ func (h *ReviewHandler) GetReviews(c echo.Context) error {
 
 	userID := c.QueryParam("author_id")
 
+	viewer, _ := GetUserID(c)
+
+	filter := repository.ReviewFilter{
+		After:    after,
+		Before:   before,
+		ShopID:   shopID,
+		AuthorID: model.UserID(userID),
+
+		Viewer:        model.UserID(viewer),
+		IncludeHidden: h.admins.Contains(viewer),
+	}
+
+	// cursorパラメータが指定された場合はカーソルでページングする
+	if c.QueryParams().Has("cursor") {
+		return h.getReviewPage(c, filter, limit)
+	}
+
+	if acceptsListProfile(c) {
+		limit, offset = parsePageParams(c)
+	}
+
 	reviews, err := h.reviewRepo.FindRecentReviews(
 		c.Request().Context(),
-		after,
-		before,
+		filter,
+		nil,
 		limit,
 		offset,
-		shopID,
-		model.UserID(userID),
 	)
 	if err != nil {
 		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch reviews")
 	}
 
+	if !acceptsListProfile(c) {
+		return c.JSON(http.StatusOK, fromModelToReviews(reviews))
+	}
+
+	total, err := h.reviewRepo.Count(c.Request().Context(), filter)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to count reviews")
+	}
+
+	return listJSON(c, newOffsetListResponse(c, fromModelToReviews(reviews), total, limit, offset))
+}
+
+func (h *ReviewHandler) getReviewPage(c echo.Context, filter repository.ReviewFilter, limit int) error {
+	if limit > maxPageLimit {
+		limit = maxPageLimit
+	}
+
+	var cursor *model.ReviewCursor
+	if token := c.QueryParam("cursor"); token != "" {
+		decoded, err := model.DecodeReviewCursor(token)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid cursor")
+		}
+		cursor = decoded
+	}
+
+	// 1件多く取得して次のページがあるかを判定する
+	reviews, err := h.reviewRepo.FindRecentReviews(
+		c.Request().Context(),
+		filter,
+		cursor,
+		limit+1,
+		0,
+	)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch reviews")
+	}
+
+	res := &ListResponse[*Review]{}
+	if len(reviews) > limit {
+		reviews = reviews[:limit]
+		res.NextCursor = model.NewReviewCursor(reviews[len(reviews)-1]).Encode()
+		res.Next = pageURL(c, map[string]string{
+			"limit":  strconv.Itoa(limit),
+			"cursor": res.NextCursor,
+		})
+	}
+	res.Items = fromModelToReviews(reviews)
+
+	if withTotal, _ := strconv.ParseBool(c.QueryParam("with_total")); withTotal {
+		total, err := h.reviewRepo.Count(c.Request().Context(), filter)
+		if err != nil {
+			return errorResponse(c, http.StatusInternalServerError, "Failed to count reviews")
+		}
+		res.Total = &total
+	}
+
+	return listJSON(c, res)
+}
+
+func fromModelToReviews(reviews []*model.Review) []*Review {
 	responses := make([]*Review, len(reviews))
 
 	for i, review := range reviews {
@@ -124,7 +253,7 @@ func (h *ReviewHandler) GetReviews(c echo.Context) error {
 		responses[i] = reviewDto
 	}
 
-	return c.JSON(http.StatusOK, responses)
+	return responses
 }
 
 func (h *ReviewHandler) CreateReview(c echo.Context) error {
@@ -148,8 +277,9 @@ func (h *ReviewHandler) CreateReview(c echo.Context) error {
 		return errorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
-	if err := validateContent(req.Content); err != nil {
-		return errorResponse(c, http.StatusBadRequest, err.Error())
+	decision := h.policy.Evaluate(req.Content)
+	if decision.Action == contentpolicy.ActionReject {
+		return errorResponse(c, http.StatusBadRequest, decision.Message())
 	}
 
 	images, err := parseImages(req.Images)
@@ -157,6 +287,15 @@ func (h *ReviewHandler) CreateReview(c echo.Context) error {
 		return errorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
+	dishes, err := parseDishes(req.Dishes)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	if err := h.validateDishes(c, shopID, dishes); err != nil {
+		return err
+	}
+
 	review, err := model.NewReview(
 		model.UserID(userID),
 		shopID,
@@ -167,14 +306,20 @@ func (h *ReviewHandler) CreateReview(c echo.Context) error {
 	if err != nil {
 		return errorResponse(c, http.StatusInternalServerError, "Failed to create review")
 	}
+	review.Dishes = dishes
 
-	err = h.reviewRepo.Save(c.Request().Context(), review)
+	err = h.saveReview(c.Request().Context(), review, decision)
 	if err != nil {
 		return errorResponse(c, http.StatusInternalServerError, "Failed to save review")
 	}
+	metrics.ReviewsCreated.Inc()
 
 	reviewDto := &Review{}
 	reviewDto.FromModel(review)
+	if !review.Hidden {
+		notifyWebhooks(c, h.webhookRepo, model.EventReviewCreated, reviewDto)
+	}
+	recordAudit(c, h.auditRepo, model.AuditActionCreate, model.AuditEntityReview, review.ID, nil, auditSnapshot(reviewDto))
 
 	return c.JSON(http.StatusCreated, reviewDto)
 }
@@ -186,11 +331,19 @@ func (h *ReviewHandler) GetReview(c echo.Context) error {
 	}
 
 	review, err := h.reviewRepo.FindByID(c.Request().Context(), id)
-
+	if errors.Is(err, repository.ErrNotFound) {
+		return errorResponse(c, http.StatusNotFound, "Review not found")
+	}
 	if err != nil {
 		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch review")
 	}
 
+	// 非表示のレビューは存在しないものとして扱う
+	viewer, _ := GetUserID(c)
+	if !review.CanView(model.UserID(viewer), h.admins.Contains(viewer)) {
+		return errorResponse(c, http.StatusNotFound, "Review not found")
+	}
+
 	reviewDto := &Review{}
 	reviewDto.FromModel(review)
 
@@ -223,8 +376,9 @@ func (h *ReviewHandler) UpdateReview(c echo.Context) error {
 		return errorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
-	if err := validateContent(req.Content); err != nil {
-		return errorResponse(c, http.StatusBadRequest, err.Error())
+	decision := h.policy.Evaluate(req.Content)
+	if decision.Action == contentpolicy.ActionReject {
+		return errorResponse(c, http.StatusBadRequest, decision.Message())
 	}
 
 	images, err := parseImages(req.Images)
@@ -232,10 +386,27 @@ func (h *ReviewHandler) UpdateReview(c echo.Context) error {
 		return errorResponse(c, http.StatusBadRequest, err.Error())
 	}
 
+	dishes, err := parseDishes(req.Dishes)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
 	review, err := h.reviewRepo.FindByID(c.Request().Context(), id)
 	if err != nil {
 		return errorResponse(c, http.StatusNotFound, "Review not found")
 	}
+	beforeDto := &Review{}
+	beforeDto.FromModel(review)
+	before := auditSnapshot(beforeDto)
+
+	// dishes を知らないクライアントからの更新では、料理の評価をそのまま残す
+	if req.Dishes != nil {
+		review.Dishes = dishes
+	}
+	// 残した評価も含め、料理は更新後の店舗のメニューにある必要がある
+	if err := h.validateDishes(c, shopID, review.Dishes); err != nil {
+		return err
+	}
 
 	review.Author = model.UserID(userID)
 	review.Shop = shopID
@@ -244,13 +415,14 @@ func (h *ReviewHandler) UpdateReview(c echo.Context) error {
 	review.Images = images
 	review.UpdatedAt = time.Now()
 
-	err = h.reviewRepo.Save(c.Request().Context(), review)
+	err = h.saveReview(c.Request().Context(), review, decision)
 	if err != nil {
 		return errorResponse(c, http.StatusInternalServerError, "Failed to save review")
 	}
 
 	reviewDto := &Review{}
 	reviewDto.FromModel(review)
+	recordAudit(c, h.auditRepo, model.AuditActionUpdate, model.AuditEntityReview, review.ID, before, auditSnapshot(reviewDto))
 
 	return c.JSON(http.StatusCreated, reviewDto)
 }
@@ -283,6 +455,9 @@ func (h *ReviewHandler) DeleteReview(c echo.Context) error {
 	if err != nil {
 		return errorResponse(c, http.StatusInternalServerError, "Failed to delete review")
 	}
+	reviewDto := &Review{}
+	reviewDto.FromModel(review)
+	recordAudit(c, h.auditRepo, model.AuditActionDelete, model.AuditEntityReview, review.ID, auditSnapshot(reviewDto), nil)
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Review deleted successfully",
@@ -314,7 +489,7 @@ func (h *ReviewHandler) UploadImage(c echo.Context) error {
 	defer func(file multipart.File) {
 		err := file.Close()
 		if err != nil {
-			log.Println(err)
+			logging.FromContext(c.Request().Context()).Error("failed to close image file", "error", err)
 		}
 	}(file)
 
@@ -337,12 +512,29 @@ func (h *ReviewHandler) UploadImage(c echo.Context) error {
 	if err := h.reviewRepo.Save(c.Request().Context(), review); err != nil {
 		return errorResponse(c, http.StatusInternalServerError, "Failed to save review with new image")
 	}
+	metrics.ImagesUploaded.WithLabelValues("review").Inc()
+	image := AuditImage{ID: FileID.String(), Review: review.ID.String()}
+	recordAudit(c, h.auditRepo, model.AuditActionCreate, model.AuditEntityImage, FileID, nil, auditSnapshot(image))
 
 	return c.JSON(http.StatusCreated, map[string]string{
 		"id": FileID.String(),
 	})
 }
 
+// validateDishes writes an error response and returns it when a rated dish is
+// not on the menu of the reviewed shop.
+func (h *ReviewHandler) validateDishes(c echo.Context, shopID uuid.UUID, dishes []model.DishRating) error {
+	err := validateDishes(c.Request().Context(), h.menuRepo, shopID, dishes)
+	if errors.Is(err, errDishNotOnMenu) {
+		return errorResponse(c, http.StatusBadRequest, "Menu item is not on the menu of the shop")
+	}
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch menu")
+	}
+
+	return nil
+}
+
 func validateAuthor(userID, author string) error {
 	if userID != author {
 		return echo.NewHTTPError(http.StatusForbidden, "You are not allowed to post this review")
@@ -369,11 +561,29 @@ func parseRating(rating int32) (model.Rating, error) {
 	return r, nil
 }
 
-func validateContent(content string) error {
-	if len(content) > maxContentLength {
-		return echo.NewHTTPError(http.StatusBadRequest, "Invalid content length")
+// saveReview stores the review, holding it for moderation when the content
+// policy queued it. A held review is hidden and reported in the same
+// transaction as the save, so it is never published.
+func (h *ReviewHandler) saveReview(ctx context.Context, review *model.Review, decision *contentpolicy.Decision) error {
+	if decision.Action != contentpolicy.ActionQueue {
+		return h.reviewRepo.Save(ctx, review)
 	}
 
+	report, err := model.NewReviewReport(
+		review,
+		model.SystemReporter,
+		decision.Violations[0].Reason,
+		decision.Message(),
+	)
+	if err != nil {
+		return err
+	}
+
+	if err := h.reviewRepo.SaveHeld(ctx, review, report); err != nil {
+		return err
+	}
+	metrics.ReviewsHeld.Inc()
+
 	return nil
 }
 
@@ -395,3 +605,32 @@ func parseImages(imagesReq []string) ([]model.ImageFile, error) {
 
 	return images, nil
 }
+
+func parseDishes(dishesReq []ReviewDish) ([]model.DishRating, error) {
+	if len(dishesReq) > maxDishes {
+		return nil, echo.NewHTTPError(http.StatusBadRequest, "Too many dishes")
+	}
+
+	dishes := make([]model.DishRating, 0, len(dishesReq))
+	seen := make(map[uuid.UUID]struct{}, len(dishesReq))
+
+	for _, dish := range dishesReq {
+		itemID, err := uuid.Parse(dish.MenuItem)
+		if err != nil {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Invalid menu item ID")
+		}
+		if _, ok := seen[itemID]; ok {
+			return nil, echo.NewHTTPError(http.StatusBadRequest, "Duplicate menu item")
+		}
+		seen[itemID] = struct{}{}
+
+		rating, err := parseRating(dish.Rating)
+		if err != nil {
+			return nil, err
+		}
+
+		dishes = append(dishes, model.DishRating{MenuItem: itemID, Rating: rating})
+	}
+
+	return dishes, nil
+}
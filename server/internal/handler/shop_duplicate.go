@@ -0,0 +1,171 @@
+package handler
+
+import (
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type DuplicateCandidate struct {
+	Shop *Shop `json:"shop"`
+
+	// 正規化した店舗名の類似度（0〜1）
+	NameSimilarity float64 `json:"name_similarity"`
+
+	// 登録しようとした店舗からの距離（m）。位置が未登録の場合は含まれない
+	DistanceMeters *float64 `json:"distance_meters,omitempty"`
+
+	SamePostCode bool `json:"same_post_code"`
+}
+
+func FromModelToDuplicateCandidate(m model.DuplicateCandidate) *DuplicateCandidate {
+	var distance *float64
+	if m.Distance >= 0 {
+		d := math.Round(m.Distance)
+		distance = &d
+	}
+
+	return &DuplicateCandidate{
+		Shop:           FromModelToShop(m.Shop),
+		NameSimilarity: math.Round(m.NameSimilarity*100) / 100,
+		DistanceMeters: distance,
+		SamePostCode:   m.SamePostCode,
+	}
+}
+
+type APIV1ShopsPost409Response struct {
+	Error string `json:"error"`
+
+	// 重複の可能性がある既存の店舗
+	Candidates []*DuplicateCandidate `json:"candidates"`
+}
+
+type APIV1ShopsIDMergePostRequest struct {
+	// 統合先の店舗ID
+	Into string `json:"into"`
+}
+
+type DuplicateCluster struct {
+	// 同じ店舗の可能性がある店舗の一覧
+	Shops []*Shop `json:"shops"`
+}
+
+// checkDuplicates responds with 409 and the candidates when shop looks like
+// an existing shop, unless the client confirmed the registration with
+// force=true. It returns responded=false when the shop may be saved.
+func checkDuplicates(c echo.Context, shops []*model.Shop, shop *model.Shop) (responded bool, err error) {
+	if force, _ := strconv.ParseBool(c.QueryParam("force")); force {
+		return false, nil
+	}
+
+	candidates := model.FindDuplicateCandidates(shop, shops)
+	if len(candidates) == 0 {
+		return false, nil
+	}
+
+	res := &APIV1ShopsPost409Response{
+		Error:      "Possible duplicate shops found: retry with force=true to register anyway",
+		Candidates: make([]*DuplicateCandidate, len(candidates)),
+	}
+	for i, candidate := range candidates {
+		res.Candidates[i] = FromModelToDuplicateCandidate(candidate)
+	}
+
+	return true, c.JSON(http.StatusConflict, res)
+}
+
+// GetDuplicateShops lists groups of shops that are likely to be the same shop.
+func (h *ShopHandler) GetDuplicateShops(c echo.Context) error {
+	shops, err := h.shopRepo.FindAll(c.Request().Context())
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	clusters := model.ClusterDuplicates(shops)
+
+	responses := make([]*DuplicateCluster, len(clusters))
+	for i, cluster := range clusters {
+		responses[i] = &DuplicateCluster{Shops: make([]*Shop, len(cluster))}
+		for j, shop := range cluster {
+			responses[i].Shops[j] = FromModelToShop(shop)
+		}
+	}
+
+	return c.JSON(http.StatusOK, responses)
+}
+
+// MergeShop folds the shop into another one and deletes it. The old ID keeps
+// redirecting to the shop it was merged into.
+func (h *ShopHandler) MergeShop(c echo.Context) error {
+	sourceID, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid shop ID format")
+	}
+
+	var req APIV1ShopsIDMergePostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+	targetID, err := uuid.Parse(req.Into)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid into: must be a shop ID")
+	}
+
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	source, err := h.shopRepo.FindByID(c.Request().Context(), sourceID)
+	if err != nil {
+		return shopLookupError(c, err)
+	}
+	target, err := h.shopRepo.FindByID(c.Request().Context(), targetID)
+	if err != nil {
+		return shopLookupError(c, err)
+	}
+
+	merge, err := model.NewShopMerge(source, target, model.UserID(userID))
+	if errors.Is(err, model.ErrInvalidShopMerge) {
+		return errorResponse(c, http.StatusBadRequest, "Cannot merge a shop into itself")
+	}
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
+	sourceBefore := auditSnapshot(FromModelToShop(source))
+	targetBefore := auditSnapshot(FromModelToShop(target))
+	target.Absorb(source)
+	if err := h.shopRepo.Merge(c.Request().Context(), merge, target); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to merge shops")
+	}
+	notifyWebhooks(c, h.webhookRepo, model.EventShopUpdated, FromModelToShop(target))
+	recordAudit(c, h.auditRepo, model.AuditActionDelete, model.AuditEntityShop, source.ID, sourceBefore, nil)
+	recordAudit(c, h.auditRepo, model.AuditActionUpdate, model.AuditEntityShop, target.ID, targetBefore, auditSnapshot(FromModelToShop(target)))
+
+	return c.JSON(http.StatusOK, FromModelToShop(target))
+}
+
+// redirectMergedShop answers 301 with the shop that id was merged into. It
+// returns redirected=false when id was never merged.
+func (h *ShopHandler) redirectMergedShop(c echo.Context, id uuid.UUID) (redirected bool, err error) {
+	to, err := h.shopRepo.FindRedirect(c.Request().Context(), id)
+	if errors.Is(err, repository.ErrNotFound) {
+		return false, nil
+	}
+	if err != nil {
+		return true, errorResponse(c, http.StatusInternalServerError, "Failed to fetch shop")
+	}
+
+	path := strings.TrimSuffix(c.Request().URL.Path, c.Param("id")) + to.String()
+
+	return true, c.Redirect(http.StatusMovedPermanently, path)
+}
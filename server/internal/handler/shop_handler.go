@@ -3,7 +3,9 @@ package handler
 import (
 	"backend/internal/domain/model"
 	"backend/internal/domain/repository"
-	"fmt"
+	"backend/internal/logging"
+	"backend/internal/metrics"
+	"errors"
 	"mime/multipart"
 	"net/http"
 	"time"
@@ -14,14 +16,37 @@ import (
 )
 
 type ShopHandler struct {
-	shopRepo repository.ShopRepository
-	fileRepo repository.FileRepository
+	shopRepo    repository.ShopRepository
+	fileRepo    repository.FileRepository
+	webhookRepo repository.WebhookRepository
+	menuRepo    repository.MenuItemRepository
+	tagRepo     repository.TagRepository
+	auditRepo   repository.AuditLogRepository
+
+	paymentMethodRepo repository.PaymentMethodRepository
+	admins            Admins
 }
 
-func NewShopHandler(shopRepo repository.ShopRepository, fileRepo repository.FileRepository) *ShopHandler {
+func NewShopHandler(
+	shopRepo repository.ShopRepository,
+	fileRepo repository.FileRepository,
+	webhookRepo repository.WebhookRepository,
+	menuRepo repository.MenuItemRepository,
+	tagRepo repository.TagRepository,
+	paymentMethodRepo repository.PaymentMethodRepository,
+	auditRepo repository.AuditLogRepository,
+	admins Admins,
+) *ShopHandler {
 	return &ShopHandler{
-		shopRepo: shopRepo,
-		fileRepo: fileRepo,
+		shopRepo:    shopRepo,
+		fileRepo:    fileRepo,
+		webhookRepo: webhookRepo,
+		menuRepo:    menuRepo,
+		tagRepo:     tagRepo,
+		auditRepo:   auditRepo,
+
+		paymentMethodRepo: paymentMethodRepo,
+		admins:            admins,
 	}
 }
 
@@ -47,6 +72,23 @@ type Shop struct {
 
 	Registerer string `json:"registerer,omitempty"`
 
+	BusinessHours *BusinessHours `json:"business_hours,omitempty"`
+
+	// カテゴリとユーザータグのID配列
+	Tags []string `json:"tags,omitempty"`
+
+	// open, temporarily_closed, permanently_closed, moved のいずれか
+	Status string `json:"status"`
+
+	// 移転先の店舗ID。status が moved の場合のみ含まれる
+	MovedTo string `json:"moved_to,omitempty"`
+
+	// 現在（Asia/Tokyo）営業中かどうか。営業時間が未登録の場合は含まれない
+	IsOpenNow *bool `json:"is_open_now,omitempty"`
+
+	// 料理ごとの評価が高いメニュー。店舗詳細でのみ含まれる
+	RecommendedDishes []*RecommendedDish `json:"recommended_dishes,omitempty"`
+
 	CreatedAt time.Time `json:"created_at,omitempty"`
 
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
@@ -63,6 +105,21 @@ func FromModelToShop(m *model.Shop) *Shop {
 		images[i] = img.ID.String()
 	}
 
+	tags := make([]string, len(m.Tags))
+	for i, tag := range m.Tags {
+		tags[i] = tag.String()
+	}
+
+	movedTo := ""
+	if m.MovedTo != uuid.Nil {
+		movedTo = m.MovedTo.String()
+	}
+
+	var businessHours *BusinessHours
+	if m.BusinessHours != nil {
+		businessHours = FromModelToBusinessHours(m.BusinessHours)
+	}
+
 	return &Shop{
 		ID:             m.ID.String(),
 		Name:           string(m.Name),
@@ -74,6 +131,11 @@ func FromModelToShop(m *model.Shop) *Shop {
 		PaymentMethods: m.PaymentMethods,
 		Stations:       stations,
 		Registerer:     string(m.Registerer),
+		BusinessHours:  businessHours,
+		Tags:           tags,
+		Status:         string(m.Status),
+		MovedTo:        movedTo,
+		IsOpenNow:      isOpenNow(m),
 		CreatedAt:      m.CreatedAt,
 		UpdatedAt:      m.UpdatedAt,
 	}
@@ -84,7 +146,7 @@ type APIV1ShopsPostRequest struct {
 
 	PostCode string `json:"post_code,omitempty"`
 
-	Address string `json:"address",omitempty`
+	Address string `json:"address,omitempty"`
 
 	Latitude float64 `json:"latitude,omitempty"`
 
@@ -98,6 +160,14 @@ type APIV1ShopsPostRequest struct {
 	Stations []string `json:"stations,omitempty"`
 
 	Registerer string `json:"registerer,omitempty"`
+
+	BusinessHours *BusinessHours `json:"business_hours,omitempty"`
+
+	// OpenStreetMapの opening_hours 形式の営業時間。business_hours と同時には指定できない
+	OpeningHours string `json:"opening_hours,omitempty"`
+
+	// カテゴリとユーザータグのID配列
+	Tags []string `json:"tags,omitempty"`
 }
 
 type APIV1ShopsIDImagesPost200Response struct {
@@ -115,14 +185,29 @@ func (h *ShopHandler) GetShopDetail(c echo.Context) error {
 		return errorResponse(c, http.StatusBadRequest, "Invalid shop ID format")
 	}
 	shop, err := h.shopRepo.FindByID(c.Request().Context(), uuidShopID)
+	if errors.Is(err, repository.ErrNotFound) {
+		if redirected, err := h.redirectMergedShop(c, uuidShopID); redirected {
+			return err
+		}
+
+		return errorResponse(c, http.StatusNotFound, "Shop not found")
+	}
 	if err != nil {
 		return errorResponse(c, http.StatusInternalServerError, err.Error())
 	}
-	if shop == nil {
-		return errorResponse(c, http.StatusNotFound, "Shop not found")
+
+	dishes, err := h.menuRepo.FindRecommended(c.Request().Context(), uuidShopID, recommendedDishLimit)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err.Error())
 	}
 
-	return c.JSON(http.StatusOK, FromModelToShop(shop))
+	res := FromModelToShop(shop)
+	res.RecommendedDishes = make([]*RecommendedDish, len(dishes))
+	for i, dish := range dishes {
+		res.RecommendedDishes[i] = FromModelToRecommendedDish(dish)
+	}
+
+	return c.JSON(http.StatusOK, res)
 }
 
 func (h *ShopHandler) UpdateShop(c echo.Context) error {
@@ -139,11 +224,14 @@ func (h *ShopHandler) UpdateShop(c echo.Context) error {
 
 	shop, err := h.shopRepo.FindByID(c.Request().Context(), uuidShopID)
 	if err != nil {
-		return errorResponse(c, http.StatusInternalServerError, err.Error())
+		return shopLookupError(c, err)
 	}
-	if shop == nil {
-		return errorResponse(c, http.StatusNotFound, "Shop not found")
+
+	userID, err := GetUserID(c)
+	if err != nil || !canEditShop(h.admins, userID, shop) {
+		return errorResponse(c, http.StatusForbidden, "Only the registerer or moderators can edit the shop directly: submit an edit proposal instead")
 	}
+	before := auditSnapshot(FromModelToShop(shop))
 
 	if req.Name != "" {
 		name, err := model.NewShopName(req.Name)
@@ -189,7 +277,11 @@ func (h *ShopHandler) UpdateShop(c echo.Context) error {
 		shop.Images = images
 	}
 	if req.PaymentMethods != nil {
-		shop.PaymentMethods = req.PaymentMethods
+		paymentMethods, err := normalizePaymentMethods(c, h.paymentMethodRepo, req.PaymentMethods)
+		if err != nil {
+			return paymentMethodError(c, err)
+		}
+		shop.PaymentMethods = paymentMethods
 	}
 	if req.Stations != nil {
 		stationUUIDs := make([]uuid.UUID, len(req.Stations))
@@ -210,11 +302,29 @@ func (h *ShopHandler) UpdateShop(c echo.Context) error {
 		shop.Registerer = userID
 	}
 
+	businessHours, set, err := businessHoursFromRequest(&req)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+	if set {
+		shop.BusinessHours = businessHours
+	}
+
+	if req.Tags != nil {
+		tags, err := parseShopTags(c.Request().Context(), h.tagRepo, req.Tags)
+		if err != nil {
+			return shopTagsError(c, err)
+		}
+		shop.Tags = tags
+	}
+
 	shop.UpdatedAt = time.Now()
 
 	if err := h.shopRepo.Save(c.Request().Context(), shop); err != nil {
 		return errorResponse(c, http.StatusInternalServerError, err.Error())
 	}
+	notifyWebhooks(c, h.webhookRepo, model.EventShopUpdated, FromModelToShop(shop))
+	recordAudit(c, h.auditRepo, model.AuditActionUpdate, model.AuditEntityShop, shop.ID, before, auditSnapshot(FromModelToShop(shop)))
 
 	return c.JSON(http.StatusOK, FromModelToShop(shop))
 }
@@ -226,9 +336,15 @@ func (h *ShopHandler) Delete(c echo.Context) error {
 		return errorResponse(c, http.StatusBadRequest, "Invalid shop ID format")
 	}
 
+	shop, err := h.shopRepo.FindByID(c.Request().Context(), uuidShopID)
+	if err != nil {
+		return shopLookupError(c, err)
+	}
+
 	if err := h.shopRepo.Delete(c.Request().Context(), uuidShopID); err != nil {
 		return errorResponse(c, http.StatusInternalServerError, err.Error())
 	}
+	recordAudit(c, h.auditRepo, model.AuditActionDelete, model.AuditEntityShop, shop.ID, auditSnapshot(FromModelToShop(shop)), nil)
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "Shop deleted successfully",
@@ -240,11 +356,37 @@ func (h *ShopHandler) GetShops(c echo.Context) error {
 		return errorResponse(c, http.StatusInternalServerError, err.Error())
 	}
 
+	shops = filterClosed(c, shops)
+
+	shops, err = filterOpenAt(c, shops)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid open_at: must be RFC3339")
+	}
+
+	shops, err = filterPaymentMethods(c, h.paymentMethodRepo, shops)
+	if err != nil {
+		return paymentMethodError(c, err)
+	}
+
+	shops, err = filterTags(c, h.tagRepo, shops)
+	if errors.Is(err, errInvalidTagFilter) {
+		return errorResponse(c, http.StatusBadRequest, "Invalid tags: must be comma separated tag IDs, and tag_mode must be and or or")
+	}
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+
 	responses := make([]*Shop, len(shops))
 	for i, v := range shops {
 		responses[i] = FromModelToShop(v)
 	}
 
+	if acceptsListProfile(c) {
+		limit, offset := parsePageParams(c)
+
+		return listJSON(c, newOffsetListResponse(c, paginate(responses, limit, offset), len(responses), limit, offset))
+	}
+
 	return c.JSON(http.StatusOK, responses)
 }
 
@@ -301,13 +443,42 @@ func (h *ShopHandler) CreateShop(c echo.Context) error {
 		stationUUIDs[i] = u
 	}
 
-	shop, err := model.NewShop(shopName, req.Address, postCode, req.Latitude, req.Longitude, images, req.PaymentMethods, registerer, stationUUIDs)
+	businessHours, _, err := businessHoursFromRequest(&req)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, err.Error())
+	}
+
+	tags, err := parseShopTags(c.Request().Context(), h.tagRepo, req.Tags)
+	if err != nil {
+		return shopTagsError(c, err)
+	}
+
+	paymentMethods, err := normalizePaymentMethods(c, h.paymentMethodRepo, req.PaymentMethods)
+	if err != nil {
+		return paymentMethodError(c, err)
+	}
+
+	shop, err := model.NewShop(shopName, req.Address, postCode, req.Latitude, req.Longitude, images, paymentMethods, registerer, stationUUIDs)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, err.Error())
+	}
+	shop.BusinessHours = businessHours
+	shop.Tags = tags
+
+	existing, err := h.shopRepo.FindAll(c.Request().Context())
 	if err != nil {
 		return errorResponse(c, http.StatusInternalServerError, err.Error())
 	}
+	if responded, err := checkDuplicates(c, existing, shop); responded {
+		return err
+	}
+
 	if err := h.shopRepo.Save(c.Request().Context(), shop); err != nil {
 		return errorResponse(c, http.StatusInternalServerError, err.Error())
 	}
+	metrics.ShopsCreated.Inc()
+	notifyWebhooks(c, h.webhookRepo, model.EventShopCreated, FromModelToShop(shop))
+	recordAudit(c, h.auditRepo, model.AuditActionCreate, model.AuditEntityShop, shop.ID, nil, auditSnapshot(FromModelToShop(shop)))
 
 	return c.JSON(http.StatusCreated, FromModelToShop(shop))
 }
@@ -331,6 +502,8 @@ func (h *ShopHandler) DeletePicture(c echo.Context) error {
 		if err != nil {
 			return errorResponse(c, http.StatusInternalServerError, err.Error())
 		}
+		image := AuditImage{ID: img.ID.String(), Shop: shop.ID.String()}
+		recordAudit(c, h.auditRepo, model.AuditActionDelete, model.AuditEntityImage, img.ID, auditSnapshot(image), nil)
 	}
 
 	shop.Images = []model.ImageFile{}
@@ -362,7 +535,7 @@ func (h *ShopHandler) ShopImgUpload(c echo.Context) error {
 	defer func(src multipart.File) {
 		err := src.Close()
 		if err != nil {
-			fmt.Println(err)
+			logging.FromContext(c.Request().Context()).Error("failed to close uploaded file", "error", err)
 		}
 	}(src)
 
@@ -384,6 +557,9 @@ func (h *ShopHandler) ShopImgUpload(c echo.Context) error {
 	if err := h.shopRepo.Save(c.Request().Context(), shop); err != nil {
 		return errorResponse(c, http.StatusInternalServerError, "Failed to save shop with new image: "+err.Error())
 	}
+	metrics.ImagesUploaded.WithLabelValues("shop").Inc()
+	image := AuditImage{ID: imageID.String(), Shop: shop.ID.String()}
+	recordAudit(c, h.auditRepo, model.AuditActionCreate, model.AuditEntityImage, imageID, nil, auditSnapshot(image))
 
 	return c.JSON(http.StatusOK, APIV1ShopsIDImagesPost200Response{
 		ImageURL: imageID.String(),
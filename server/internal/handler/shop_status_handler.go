@@ -0,0 +1,298 @@
+package handler
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type ShopStatusHandler struct {
+	shopRepo    repository.ShopRepository
+	reportRepo  repository.ClosureReportRepository
+	webhookRepo repository.WebhookRepository
+	auditRepo   repository.AuditLogRepository
+	// 店舗の状態を自動で変更するのに必要な報告者数
+	threshold int
+}
+
+func NewShopStatusHandler(
+	shopRepo repository.ShopRepository,
+	reportRepo repository.ClosureReportRepository,
+	webhookRepo repository.WebhookRepository,
+	auditRepo repository.AuditLogRepository,
+	threshold int,
+) *ShopStatusHandler {
+	return &ShopStatusHandler{
+		shopRepo:    shopRepo,
+		reportRepo:  reportRepo,
+		webhookRepo: webhookRepo,
+		auditRepo:   auditRepo,
+		threshold:   threshold,
+	}
+}
+
+type ClosureReport struct {
+	ID string `json:"id"`
+
+	Shop string `json:"shop"`
+
+	// 報告したユーザーのID
+	Reporter string `json:"reporter"`
+
+	// 報告された店舗の状態
+	Status string `json:"status"`
+
+	// 移転先の店舗ID
+	MovedTo string `json:"moved_to,omitempty"`
+
+	Note string `json:"note,omitempty"`
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+func FromModelToClosureReport(r *model.ClosureReport) *ClosureReport {
+	movedTo := ""
+	if r.MovedTo != uuid.Nil {
+		movedTo = r.MovedTo.String()
+	}
+
+	return &ClosureReport{
+		ID:        r.ID.String(),
+		Shop:      r.Shop.String(),
+		Reporter:  string(r.Reporter),
+		Status:    string(r.Status),
+		MovedTo:   movedTo,
+		Note:      r.Note,
+		CreatedAt: r.CreatedAt,
+	}
+}
+
+type APIV1ShopsIDStatusPutRequest struct {
+	// open, temporarily_closed, permanently_closed, moved のいずれか
+	Status string `json:"status"`
+
+	// 移転先の店舗ID。status が moved の場合のみ指定する
+	MovedTo string `json:"moved_to,omitempty"`
+}
+
+type APIV1ShopsIDClosureReportsPostRequest struct {
+	APIV1ShopsIDStatusPutRequest
+
+	Note string `json:"note,omitempty"`
+}
+
+type APIV1ShopsIDClosureReportsPost201Response struct {
+	Report *ClosureReport `json:"report"`
+
+	// 報告を反映した後の店舗の状態
+	ShopStatus string `json:"shop_status"`
+
+	// この報告で店舗の状態が変わったかどうか
+	StatusChanged bool `json:"status_changed"`
+}
+
+// UpdateShopStatus sets the status of a shop directly. Pending closure
+// reports are resolved by the change.
+func (h *ShopStatusHandler) UpdateShopStatus(c echo.Context) error {
+	shop, err := h.findShop(c)
+	if err != nil {
+		return shopStatusLookupError(c, err)
+	}
+
+	var req APIV1ShopsIDStatusPutRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	status, movedTo, err := h.parseStatus(c, shop, &req)
+	if err != nil {
+		return shopStatusRequestError(c, err)
+	}
+
+	if err := h.changeStatus(c, shop, status, movedTo); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save shop status")
+	}
+
+	return c.JSON(http.StatusOK, FromModelToShop(shop))
+}
+
+// CreateClosureReport files a report that a shop closed, moved or reopened.
+// When enough distinct users agree, the status of the shop changes.
+func (h *ShopStatusHandler) CreateClosureReport(c echo.Context) error {
+	shop, err := h.findShop(c)
+	if err != nil {
+		return shopStatusLookupError(c, err)
+	}
+
+	var req APIV1ShopsIDClosureReportsPostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	status, movedTo, err := h.parseStatus(c, shop, &req.APIV1ShopsIDStatusPutRequest)
+	if err != nil {
+		return shopStatusRequestError(c, err)
+	}
+
+	if status == shop.Status && movedTo == shop.MovedTo {
+		return errorResponse(c, http.StatusConflict, "Shop already has the reported status")
+	}
+
+	report, err := model.NewClosureReport(shop.ID, model.UserID(userID), status, movedTo, req.Note)
+	if err != nil {
+		return shopStatusRequestError(c, err)
+	}
+
+	if err := h.reportRepo.Save(c.Request().Context(), report); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save closure report")
+	}
+
+	reports, err := h.reportRepo.FindPending(c.Request().Context(), shop.ID, time.Now().Add(-model.ClosureReportWindow))
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch closure reports")
+	}
+
+	res := &APIV1ShopsIDClosureReportsPost201Response{
+		Report: FromModelToClosureReport(report),
+	}
+
+	if status, movedTo, changed := model.DecideShopStatus(shop, reports, h.threshold); changed {
+		if err := h.changeStatus(c, shop, status, movedTo); err != nil {
+			return errorResponse(c, http.StatusInternalServerError, "Failed to save shop status")
+		}
+		res.StatusChanged = true
+	}
+	res.ShopStatus = string(shop.Status)
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (h *ShopStatusHandler) GetClosureReports(c echo.Context) error {
+	shop, err := h.findShop(c)
+	if err != nil {
+		return shopStatusLookupError(c, err)
+	}
+
+	reports, err := h.reportRepo.FindPending(c.Request().Context(), shop.ID, time.Now().Add(-model.ClosureReportWindow))
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch closure reports")
+	}
+
+	responses := make([]*ClosureReport, len(reports))
+	for i, report := range reports {
+		responses[i] = FromModelToClosureReport(report)
+	}
+
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (h *ShopStatusHandler) findShop(c echo.Context) (*model.Shop, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errInvalidShopID
+	}
+
+	return h.shopRepo.FindByID(c.Request().Context(), id)
+}
+
+// parseStatus validates the requested status. A successor shop must exist.
+func (h *ShopStatusHandler) parseStatus(
+	c echo.Context,
+	shop *model.Shop,
+	req *APIV1ShopsIDStatusPutRequest,
+) (model.ShopStatus, uuid.UUID, error) {
+	status, err := model.ParseShopStatus(req.Status)
+	if err != nil {
+		return "", uuid.Nil, err
+	}
+
+	movedTo := uuid.Nil
+	if req.MovedTo != "" {
+		movedTo, err = uuid.Parse(req.MovedTo)
+		if err != nil || movedTo == shop.ID {
+			return "", uuid.Nil, model.ErrInvalidShopStatus
+		}
+		if _, err := h.shopRepo.FindByID(c.Request().Context(), movedTo); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return "", uuid.Nil, model.ErrInvalidShopStatus
+			}
+
+			return "", uuid.Nil, err
+		}
+	}
+
+	if (status == model.ShopStatusMoved) != (movedTo != uuid.Nil) {
+		return "", uuid.Nil, model.ErrInvalidShopStatus
+	}
+
+	return status, movedTo, nil
+}
+
+// changeStatus saves a status change, whether made by an admin or by enough
+// closure reports. Automatic changes are recorded in the audit log as made by
+// the user whose report tipped the balance.
+func (h *ShopStatusHandler) changeStatus(c echo.Context, shop *model.Shop, status model.ShopStatus, movedTo uuid.UUID) error {
+	before := auditSnapshot(FromModelToShop(shop))
+	if err := shop.SetStatus(status, movedTo); err != nil {
+		return err
+	}
+	shop.UpdatedAt = time.Now()
+
+	if err := h.shopRepo.UpdateStatus(c.Request().Context(), shop); err != nil {
+		return err
+	}
+	notifyWebhooks(c, h.webhookRepo, model.EventShopUpdated, FromModelToShop(shop))
+	recordAudit(c, h.auditRepo, model.AuditActionUpdate, model.AuditEntityShop, shop.ID, before, auditSnapshot(FromModelToShop(shop)))
+
+	return nil
+}
+
+var errInvalidShopID = errors.New("invalid shop ID")
+
+func shopStatusLookupError(c echo.Context, err error) error {
+	if errors.Is(err, errInvalidShopID) {
+		return errorResponse(c, http.StatusBadRequest, "Invalid shop ID format")
+	}
+
+	return shopLookupError(c, err)
+}
+
+func shopStatusRequestError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidShopStatus):
+		return errorResponse(c, http.StatusBadRequest, "Invalid status: moved requires moved_to of another existing shop, and other statuses must not have it")
+	case errors.Is(err, model.ErrInvalidClosureReportNote):
+		return errorResponse(c, http.StatusBadRequest, "Invalid note length")
+	default:
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch shop")
+	}
+}
+
+// filterClosed hides shops that are not open unless the include_closed query
+// parameter is true.
+func filterClosed(c echo.Context, shops []*model.Shop) []*model.Shop {
+	if includeClosed, _ := strconv.ParseBool(c.QueryParam("include_closed")); includeClosed {
+		return shops
+	}
+
+	filtered := make([]*model.Shop, 0, len(shops))
+	for _, shop := range shops {
+		if !shop.Status.IsClosed() {
+			filtered = append(filtered, shop)
+		}
+	}
+
+	return filtered
+}
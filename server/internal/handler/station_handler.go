@@ -15,6 +15,8 @@ import (
 type StationHandler struct {
 	stationRepo repository.StationRepository
 	shopRepo    repository.ShopRepository
+	webhookRepo repository.WebhookRepository
+	auditRepo   repository.AuditLogRepository
 }
 
 type StationDto struct {
@@ -24,10 +26,17 @@ type StationDto struct {
 	UpdatedAt time.Time `json:"updated_at,omitempty"`
 }
 
-func NewStationHandler(stationRepo repository.StationRepository, shopRepo repository.ShopRepository) *StationHandler {
+func NewStationHandler(
+	stationRepo repository.StationRepository,
+	shopRepo repository.ShopRepository,
+	webhookRepo repository.WebhookRepository,
+	auditRepo repository.AuditLogRepository,
+) *StationHandler {
 	return &StationHandler{
 		stationRepo: stationRepo,
 		shopRepo:    shopRepo,
+		webhookRepo: webhookRepo,
+		auditRepo:   auditRepo,
 	}
 }
 
@@ -83,6 +92,8 @@ func (h *StationHandler) CreateStation(c echo.Context) error {
 	if err := h.stationRepo.Save(c.Request().Context(), station); err != nil {
 		return errorResponse(c, http.StatusInternalServerError, err.Error())
 	}
+	notifyWebhooks(c, h.webhookRepo, model.EventStationCreated, FromModel(station))
+	recordAudit(c, h.auditRepo, model.AuditActionCreate, model.AuditEntityStation, station.ID, nil, auditSnapshot(FromModel(station)))
 
 	return c.JSON(http.StatusCreated, FromModel(station))
 
@@ -99,6 +110,12 @@ func (h *StationHandler) GetStations(c echo.Context) error {
 		responses[i] = FromModel(v)
 	}
 
+	if acceptsListProfile(c) {
+		limit, offset := parsePageParams(c)
+
+		return listJSON(c, newOffsetListResponse(c, paginate(responses, limit, offset), len(responses), limit, offset))
+	}
+
 	return c.JSON(http.StatusCreated, responses)
 }
 
@@ -126,11 +143,13 @@ func (h *StationHandler) UpdateStation(c echo.Context) error {
 	if err != nil {
 		return errorResponse(c, http.StatusNotFound, "Invalid name")
 	}
+	before := auditSnapshot(FromModel(station))
 	station.Name = req.Name
 	station.UpdatedAt = time.Now()
 	if err := h.stationRepo.Save(c.Request().Context(), station); err != nil {
 		return errorResponse(c, http.StatusInternalServerError, err.Error())
 	}
+	recordAudit(c, h.auditRepo, model.AuditActionUpdate, model.AuditEntityStation, station.ID, before, auditSnapshot(FromModel(station)))
 
 	return c.JSON(http.StatusCreated, FromModel(station))
 }
@@ -143,9 +162,15 @@ func (h *StationHandler) DeleteStation(c echo.Context) error {
 		return errorResponse(c, http.StatusBadRequest, "Invalid station ID")
 	}
 
+	station, err := h.stationRepo.FindByID(c.Request().Context(), id)
+	if err != nil {
+		return errorResponse(c, http.StatusNotFound, "Station not found")
+	}
+
 	if err := h.stationRepo.Delete(c.Request().Context(), id); err != nil {
 		return errorResponse(c, http.StatusInternalServerError, fmt.Sprintf("failed to delete station: %v", err))
 	}
+	recordAudit(c, h.auditRepo, model.AuditActionDelete, model.AuditEntityStation, station.ID, auditSnapshot(FromModel(station)), nil)
 
 	return c.JSON(http.StatusOK, map[string]string{
 		"message": "station deleted successfully",
@@ -178,7 +203,23 @@ func (h *StationHandler) GetShopAroundStation(c echo.Context) error {
 		return errorResponse(c, http.StatusInternalServerError, err.Error())
 	}
 
-	responses := shops
+	shops = filterClosed(c, shops)
+
+	shops, err = filterOpenAt(c, shops)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid open_at: must be RFC3339")
+	}
+
+	responses := make([]*Shop, len(shops))
+	for i, v := range shops {
+		responses[i] = FromModelToShop(v)
+	}
+
+	if acceptsListProfile(c) {
+		limit, offset := parsePageParams(c)
+
+		return listJSON(c, newOffsetListResponse(c, paginate(responses, limit, offset), len(responses), limit, offset))
+	}
 
 	return c.JSON(http.StatusCreated, responses)
 }
@@ -0,0 +1,164 @@
+package handler
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/infrastructure/stream"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const (
+	// プロキシにアイドル接続を切られないよう定期的にコメントを送る
+	streamHeartbeatInterval = 30 * time.Second
+	// 再接続までの待ち時間(ミリ秒)
+	streamRetryMillis = 3000
+
+	// Last-Event-ID の時点からイベントを再送できなかったことを通知する
+	streamEventReset = "reset"
+)
+
+type StreamHandler struct {
+	broker *stream.Broker
+}
+
+func NewStreamHandler(broker *stream.Broker) *StreamHandler {
+	return &StreamHandler{
+		broker: broker,
+	}
+}
+
+// streamFilter selects the events a client is interested in. Zero values mean
+// "no condition".
+type streamFilter struct {
+	types   []model.EventType
+	shop    uuid.UUID
+	station uuid.UUID
+}
+
+func (f *streamFilter) match(e *model.Event) bool {
+	if len(f.types) > 0 && !slices.Contains(f.types, e.Type) {
+		return false
+	}
+	if f.shop != uuid.Nil && e.Shop != f.shop {
+		return false
+	}
+	if f.station != uuid.Nil && !slices.Contains(e.RelatedStations(), f.station) {
+		return false
+	}
+
+	return true
+}
+
+func (h *StreamHandler) Stream(c echo.Context) error {
+	var filter streamFilter
+
+	if types := c.QueryParam("types"); types != "" {
+		for _, t := range strings.Split(types, ",") {
+			filter.types = append(filter.types, model.EventType(strings.TrimSpace(t)))
+		}
+	}
+
+	if shopID := c.QueryParam("shop_id"); shopID != "" {
+		id, err := uuid.Parse(shopID)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid shop ID")
+		}
+		filter.shop = id
+	}
+
+	if stationID := c.QueryParam("station_id"); stationID != "" {
+		id, err := uuid.Parse(stationID)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid station ID")
+		}
+		filter.station = id
+	}
+
+	// EventSourceは再接続時に最後に受け取ったIDをヘッダーで送る
+	lastEventID := uuid.Nil
+	if v := c.Request().Header.Get("Last-Event-ID"); v != "" {
+		id, err := uuid.Parse(v)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid Last-Event-ID")
+		}
+		lastEventID = id
+	}
+
+	sub, replay, complete := h.broker.Subscribe(lastEventID)
+	defer sub.Close()
+
+	res := c.Response()
+	res.Header().Set(echo.HeaderContentType, "text/event-stream")
+	res.Header().Set(echo.HeaderCacheControl, "no-cache")
+	res.Header().Set(echo.HeaderConnection, "keep-alive")
+	// nginxなどのバッファリングを無効にする
+	res.Header().Set("X-Accel-Buffering", "no")
+	res.WriteHeader(http.StatusOK)
+
+	if _, err := fmt.Fprintf(res, "retry: %d\n\n", streamRetryMillis); err != nil {
+		return nil
+	}
+
+	if !complete {
+		if _, err := fmt.Fprintf(res, "event: %s\ndata: {}\n\n", streamEventReset); err != nil {
+			return nil
+		}
+	}
+
+	for _, event := range replay {
+		if !filter.match(event) {
+			continue
+		}
+		if err := writeStreamEvent(res, event); err != nil {
+			return nil
+		}
+	}
+	res.Flush()
+
+	heartbeat := time.NewTicker(streamHeartbeatInterval)
+	defer heartbeat.Stop()
+
+	ctx := c.Request().Context()
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case <-heartbeat.C:
+			if _, err := fmt.Fprint(res, ": heartbeat\n\n"); err != nil {
+				return nil
+			}
+		case event, ok := <-sub.C:
+			if !ok {
+				// 送信が追いつかず切断されたか、サーバーが停止する。
+				// クライアントは Last-Event-ID で再開する
+				return nil
+			}
+			if !filter.match(event) {
+				continue
+			}
+			if err := writeStreamEvent(res, event); err != nil {
+				return nil
+			}
+		}
+		res.Flush()
+	}
+}
+
+func writeStreamEvent(res *echo.Response, e *model.Event) error {
+	data, err := json.Marshal(FromModelToEvent(e))
+	if err != nil {
+		return fmt.Errorf("failed to marshal event: %w", err)
+	}
+
+	_, err = fmt.Fprintf(res, "id: %s\nevent: %s\ndata: %s\n\n", e.ID, e.Type, data)
+
+	return err
+}
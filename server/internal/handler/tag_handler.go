@@ -0,0 +1,485 @@
+package handler
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const maxShopTags = 20
+
+var (
+	errInvalidTagID     = errors.New("invalid tag ID")
+	errInvalidTagFilter = errors.New("invalid tags filter")
+	errUnknownTag       = errors.New("unknown tag")
+)
+
+type TagHandler struct {
+	tagRepo repository.TagRepository
+}
+
+func NewTagHandler(tagRepo repository.TagRepository) *TagHandler {
+	return &TagHandler{
+		tagRepo: tagRepo,
+	}
+}
+
+type Tag struct {
+	ID string `json:"id"`
+
+	Name string `json:"name"`
+
+	// category: 管理者が管理するカテゴリ, user: ユーザーが自由に付けるタグ
+	Kind string `json:"kind"`
+
+	// 親カテゴリのID
+	Parent string `json:"parent,omitempty"`
+
+	// タグが付いている店舗数
+	UsageCount int `json:"usage_count"`
+
+	CreatedBy string `json:"created_by,omitempty"`
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+func FromModelToTag(t *model.Tag) *Tag {
+	parent := ""
+	if t.Parent != uuid.Nil {
+		parent = t.Parent.String()
+	}
+
+	return &Tag{
+		ID:         t.ID.String(),
+		Name:       string(t.Name),
+		Kind:       string(t.Kind),
+		Parent:     parent,
+		UsageCount: t.UsageCount,
+		CreatedBy:  string(t.CreatedBy),
+		CreatedAt:  t.CreatedAt,
+		UpdatedAt:  t.UpdatedAt,
+	}
+}
+
+type APIV1TagsPostRequest struct {
+	Name string `json:"name"`
+
+	// 親カテゴリのID。カテゴリの作成・更新時のみ指定できる。更新時に空文字列を指定すると最上位に移動する
+	Parent *string `json:"parent,omitempty"`
+
+	// 更新時のみ指定できる。ユーザータグをカテゴリに昇格する場合などに使う
+	Kind string `json:"kind,omitempty"`
+}
+
+type APIV1TagsIDMergePostRequest struct {
+	// 統合先のタグID
+	Into string `json:"into"`
+}
+
+func (h *TagHandler) GetTags(c echo.Context) error {
+	filter := repository.TagFilter{
+		Query: c.QueryParam("q"),
+	}
+
+	if kind := c.QueryParam("kind"); kind != "" {
+		parsed, err := model.ParseTagKind(kind)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid kind")
+		}
+		filter.Kind = parsed
+	}
+
+	tags, err := h.tagRepo.FindAll(c.Request().Context(), filter)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch tags")
+	}
+
+	responses := make([]*Tag, len(tags))
+	for i, tag := range tags {
+		responses[i] = FromModelToTag(tag)
+	}
+
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (h *TagHandler) GetTag(c echo.Context) error {
+	tag, err := h.findTag(c)
+	if err != nil {
+		return tagLookupError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, FromModelToTag(tag))
+}
+
+// CreateTag creates a free-form user tag. When a tag with the same name
+// already exists it is returned instead, so clients can tag shops by name
+// without looking the tag up first.
+func (h *TagHandler) CreateTag(c echo.Context) error {
+	var req APIV1TagsPostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	if req.Parent != nil {
+		return errorResponse(c, http.StatusBadRequest, "User tags cannot have a parent")
+	}
+
+	name, err := model.NewTagName(req.Name)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid tag name")
+	}
+
+	existing, err := h.tagRepo.FindByName(c.Request().Context(), name)
+	if err == nil {
+		return c.JSON(http.StatusOK, FromModelToTag(existing))
+	}
+	if !errors.Is(err, repository.ErrNotFound) {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch tag")
+	}
+
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	tag, err := model.NewUserTag(name, model.UserID(userID))
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to create tag")
+	}
+
+	if err := h.tagRepo.Save(c.Request().Context(), tag); err != nil {
+		return tagSaveError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, FromModelToTag(tag))
+}
+
+func (h *TagHandler) CreateCategory(c echo.Context) error {
+	var req APIV1TagsPostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	name, err := model.NewTagName(req.Name)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid tag name")
+	}
+
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	tag, err := model.NewCategory(name, uuid.Nil, model.UserID(userID))
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to create tag")
+	}
+
+	if req.Parent != nil && *req.Parent != "" {
+		if err := h.setParent(c.Request().Context(), tag, *req.Parent); err != nil {
+			return tagParentError(c, err)
+		}
+	}
+
+	if err := h.tagRepo.Save(c.Request().Context(), tag); err != nil {
+		return tagSaveError(c, err)
+	}
+
+	return c.JSON(http.StatusCreated, FromModelToTag(tag))
+}
+
+// UpdateTag renames a tag, changes its kind or moves it in the category
+// hierarchy.
+func (h *TagHandler) UpdateTag(c echo.Context) error {
+	tag, err := h.findTag(c)
+	if err != nil {
+		return tagLookupError(c, err)
+	}
+
+	var req APIV1TagsPostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	if req.Name != "" {
+		name, err := model.NewTagName(req.Name)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid tag name")
+		}
+		tag.Name = name
+	}
+
+	if req.Kind != "" {
+		kind, err := model.ParseTagKind(req.Kind)
+		if err != nil {
+			return errorResponse(c, http.StatusBadRequest, "Invalid kind")
+		}
+		if kind == model.TagKindUser && tag.Kind == model.TagKindCategory {
+			// 子カテゴリを持つカテゴリはユーザータグに戻せない
+			tags, err := h.tagRepo.FindAll(c.Request().Context(), repository.TagFilter{Kind: model.TagKindCategory})
+			if err != nil {
+				return errorResponse(c, http.StatusInternalServerError, "Failed to fetch tags")
+			}
+			if len(model.NewTagTree(tags).Descendants(tag.ID)) > 1 {
+				return errorResponse(c, http.StatusBadRequest, "Category with subcategories cannot become a user tag")
+			}
+			tag.Parent = uuid.Nil
+		}
+		tag.Kind = kind
+	}
+
+	if req.Parent != nil {
+		tag.Parent = uuid.Nil
+		if *req.Parent != "" {
+			if err := h.setParent(c.Request().Context(), tag, *req.Parent); err != nil {
+				return tagParentError(c, err)
+			}
+		}
+	}
+
+	tag.UpdatedAt = time.Now()
+
+	if err := h.tagRepo.Save(c.Request().Context(), tag); err != nil {
+		return tagSaveError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, FromModelToTag(tag))
+}
+
+// MergeTag moves every shop tagged with the tag in the path to the target
+// tag and deletes the merged tag.
+func (h *TagHandler) MergeTag(c echo.Context) error {
+	source, err := h.findTag(c)
+	if err != nil {
+		return tagLookupError(c, err)
+	}
+
+	var req APIV1TagsIDMergePostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	targetID, err := uuid.Parse(req.Into)
+	if err != nil || targetID == source.ID {
+		return errorResponse(c, http.StatusBadRequest, "Invalid merge target")
+	}
+
+	tags, err := h.tagRepo.FindAll(c.Request().Context(), repository.TagFilter{})
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch tags")
+	}
+	tree := model.NewTagTree(tags)
+
+	target, ok := tree.Get(targetID)
+	if !ok {
+		return errorResponse(c, http.StatusNotFound, "Merge target not found")
+	}
+
+	// 子カテゴリは統合先に付け替えるので、統合先は自身の子孫でないカテゴリでなければならない
+	descendants := tree.Descendants(source.ID)
+	if len(descendants) > 1 {
+		if target.Kind != model.TagKindCategory {
+			return errorResponse(c, http.StatusBadRequest, "Category with subcategories can only be merged into a category")
+		}
+		for _, id := range descendants {
+			if id == target.ID {
+				return errorResponse(c, http.StatusBadRequest, "Cannot merge a category into its subcategory")
+			}
+		}
+	}
+
+	if err := h.tagRepo.Merge(c.Request().Context(), source.ID, target.ID); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to merge tags")
+	}
+
+	merged, err := h.tagRepo.FindByID(c.Request().Context(), target.ID)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch tag")
+	}
+
+	return c.JSON(http.StatusOK, FromModelToTag(merged))
+}
+
+func (h *TagHandler) DeleteTag(c echo.Context) error {
+	tag, err := h.findTag(c)
+	if err != nil {
+		return tagLookupError(c, err)
+	}
+
+	if err := h.tagRepo.Delete(c.Request().Context(), tag.ID); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to delete tag")
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Tag deleted successfully",
+	})
+}
+
+func (h *TagHandler) findTag(c echo.Context) (*model.Tag, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errInvalidTagID
+	}
+
+	return h.tagRepo.FindByID(c.Request().Context(), id)
+}
+
+func (h *TagHandler) setParent(ctx context.Context, tag *model.Tag, parent string) error {
+	parentID, err := uuid.Parse(parent)
+	if err != nil {
+		return model.ErrInvalidTagParent
+	}
+
+	tags, err := h.tagRepo.FindAll(ctx, repository.TagFilter{Kind: model.TagKindCategory})
+	if err != nil {
+		return err
+	}
+
+	if err := model.NewTagTree(tags).ValidateParent(tag, parentID); err != nil {
+		return err
+	}
+	tag.Parent = parentID
+
+	return nil
+}
+
+func tagLookupError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errInvalidTagID):
+		return errorResponse(c, http.StatusBadRequest, "Invalid tag ID")
+	case errors.Is(err, repository.ErrNotFound):
+		return errorResponse(c, http.StatusNotFound, "Tag not found")
+	default:
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch tag")
+	}
+}
+
+func tagParentError(c echo.Context, err error) error {
+	if errors.Is(err, model.ErrInvalidTagParent) {
+		return errorResponse(c, http.StatusBadRequest, "Invalid parent: must be a category outside the subtree of the tag")
+	}
+
+	return errorResponse(c, http.StatusInternalServerError, "Failed to fetch tags")
+}
+
+func tagSaveError(c echo.Context, err error) error {
+	if errors.Is(err, repository.ErrConflict) {
+		return errorResponse(c, http.StatusConflict, "Tag name is already taken")
+	}
+
+	return errorResponse(c, http.StatusInternalServerError, "Failed to save tag")
+}
+
+// parseShopTags converts the tag IDs of a shop request, checking that every
+// tag exists.
+func parseShopTags(ctx context.Context, tagRepo repository.TagRepository, ids []string) ([]uuid.UUID, error) {
+	if len(ids) > maxShopTags {
+		return nil, errUnknownTag
+	}
+
+	tags := make([]uuid.UUID, 0, len(ids))
+	seen := make(map[uuid.UUID]struct{}, len(ids))
+	for _, idStr := range ids {
+		id, err := uuid.Parse(idStr)
+		if err != nil {
+			return nil, errUnknownTag
+		}
+		if _, ok := seen[id]; ok {
+			continue
+		}
+		seen[id] = struct{}{}
+
+		if _, err := tagRepo.FindByID(ctx, id); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return nil, errUnknownTag
+			}
+
+			return nil, err
+		}
+		tags = append(tags, id)
+	}
+
+	return tags, nil
+}
+
+// filterTags keeps the shops matching the tags query parameter, a comma
+// separated list of tag IDs. tag_mode=and (default) requires every tag and
+// tag_mode=or any of them. A category also matches its subcategories.
+func filterTags(c echo.Context, tagRepo repository.TagRepository, shops []*model.Shop) ([]*model.Shop, error) {
+	param := c.QueryParam("tags")
+	if param == "" {
+		return shops, nil
+	}
+
+	mode := c.QueryParam("tag_mode")
+	if mode == "" {
+		mode = "and"
+	}
+	if mode != "and" && mode != "or" {
+		return nil, errInvalidTagFilter
+	}
+
+	tags, err := tagRepo.FindAll(c.Request().Context(), repository.TagFilter{})
+	if err != nil {
+		return nil, err
+	}
+	tree := model.NewTagTree(tags)
+
+	// 指定されたタグごとに、子孫を含めて一致するタグの集合を作る
+	var groups []map[uuid.UUID]struct{}
+	for _, idStr := range strings.Split(param, ",") {
+		id, err := uuid.Parse(strings.TrimSpace(idStr))
+		if err != nil {
+			return nil, errInvalidTagFilter
+		}
+		group := make(map[uuid.UUID]struct{})
+		for _, tagID := range tree.Descendants(id) {
+			group[tagID] = struct{}{}
+		}
+		groups = append(groups, group)
+	}
+
+	filtered := make([]*model.Shop, 0, len(shops))
+	for _, shop := range shops {
+		matched := 0
+		for _, group := range groups {
+			if hasAnyTag(shop.Tags, group) {
+				matched++
+			}
+		}
+
+		if (mode == "and" && matched == len(groups)) || (mode == "or" && matched > 0) {
+			filtered = append(filtered, shop)
+		}
+	}
+
+	return filtered, nil
+}
+
+func hasAnyTag(tags []uuid.UUID, group map[uuid.UUID]struct{}) bool {
+	for _, tag := range tags {
+		if _, ok := group[tag]; ok {
+			return true
+		}
+	}
+
+	return false
+}
+
+func shopTagsError(c echo.Context, err error) error {
+	if errors.Is(err, errUnknownTag) {
+		return errorResponse(c, http.StatusBadRequest, "Invalid tags: must be at most 20 existing tag IDs")
+	}
+
+	return errorResponse(c, http.StatusInternalServerError, "Failed to fetch tags")
+}
@@ -0,0 +1,300 @@
+package handler
+
+import (
+	"errors"
+	"mime/multipart"
+	"net/http"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+	"backend/internal/metrics"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+type UserHandler struct {
+	userRepo  repository.UserRepository
+	shopRepo  repository.ShopRepository
+	fileRepo  repository.FileRepository
+	auditRepo repository.AuditLogRepository
+	admins    Admins
+}
+
+func NewUserHandler(
+	userRepo repository.UserRepository,
+	shopRepo repository.ShopRepository,
+	fileRepo repository.FileRepository,
+	auditRepo repository.AuditLogRepository,
+	admins Admins,
+) *UserHandler {
+	return &UserHandler{
+		userRepo:  userRepo,
+		shopRepo:  shopRepo,
+		fileRepo:  fileRepo,
+		auditRepo: auditRepo,
+		admins:    admins,
+	}
+}
+
+type UserProfile struct {
+	ID string `json:"id"`
+
+	DisplayName string `json:"display_name"`
+
+	AvatarImage string `json:"avatar_image,omitempty"`
+
+	ReviewCount int `json:"review_count"`
+
+	ShopCount int `json:"shop_count"`
+
+	// 評価（0から3まで）ごとのレビュー数
+	RatingDistribution map[int]int `json:"rating_distribution"`
+
+	FirstActivityAt *time.Time `json:"first_activity_at,omitempty"`
+
+	LastActivityAt *time.Time `json:"last_activity_at,omitempty"`
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
+}
+
+func FromModelToUserProfile(p *model.UserProfile) *UserProfile {
+	distribution := make(map[int]int)
+	for rating := 0; rating <= 3; rating++ {
+		distribution[rating] = p.RatingDistribution[model.Rating(rating)]
+	}
+
+	var avatar string
+	if p.User.Avatar != nil {
+		avatar = p.User.Avatar.ID.String()
+	}
+
+	return &UserProfile{
+		ID:                 string(p.User.ID),
+		DisplayName:        string(p.User.DisplayName),
+		AvatarImage:        avatar,
+		ReviewCount:        p.ReviewCount,
+		ShopCount:          p.ShopCount,
+		RatingDistribution: distribution,
+		FirstActivityAt:    p.FirstActivityAt,
+		LastActivityAt:     p.LastActivityAt,
+		CreatedAt:          p.User.CreatedAt,
+	}
+}
+
+type APIV1UsersMePutRequest struct {
+	DisplayName string `json:"display_name"`
+}
+
+func (h *UserHandler) GetUser(c echo.Context) error {
+	userID, err := model.NewUserID(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	return h.getProfile(c, userID)
+}
+
+func (h *UserHandler) GetMe(c echo.Context) error {
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	return h.getProfile(c, model.UserID(userID))
+}
+
+func (h *UserHandler) getProfile(c echo.Context, userID model.UserID) error {
+	// 非表示のレビューは本人と管理者にだけ集計に含める
+	viewer, _ := GetUserID(c)
+	includeHidden := viewer == string(userID) || h.admins.Contains(viewer)
+
+	profile, err := h.userRepo.GetProfile(c.Request().Context(), userID, includeHidden)
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "User not found")
+		}
+
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch user profile")
+	}
+
+	return c.JSON(http.StatusOK, FromModelToUserProfile(profile))
+}
+
+func (h *UserHandler) UpdateMe(c echo.Context) error {
+	var req APIV1UsersMePutRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	displayName, err := model.NewDisplayName(req.DisplayName)
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid display name")
+	}
+
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	user, err := h.userRepo.FindByID(c.Request().Context(), model.UserID(userID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "User not found")
+		}
+
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch user")
+	}
+
+	user.DisplayName = displayName
+	user.UpdatedAt = time.Now()
+
+	if err := h.userRepo.Save(c.Request().Context(), user); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save user")
+	}
+
+	return h.getProfile(c, user.ID)
+}
+
+func (h *UserHandler) UploadAvatar(c echo.Context) error {
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	user, err := h.userRepo.FindByID(c.Request().Context(), model.UserID(userID))
+	if err != nil {
+		if errors.Is(err, repository.ErrNotFound) {
+			return errorResponse(c, http.StatusNotFound, "User not found")
+		}
+
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch user")
+	}
+
+	fileHeader, err := c.FormFile("image")
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid image file")
+	}
+
+	file, err := fileHeader.Open()
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to open image file")
+	}
+	defer func(file multipart.File) {
+		err := file.Close()
+		if err != nil {
+			logging.FromContext(c.Request().Context()).Error("failed to close image file", "error", err)
+		}
+	}(file)
+
+	imageID, err := h.fileRepo.UploadImage(c.Request().Context(), fileHeader.Header.Get("Content-Type"), file)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save image file")
+	}
+
+	oldAvatar := user.Avatar
+	user.Avatar = model.NewImageFile(imageID)
+	user.UpdatedAt = time.Now()
+
+	if err := h.userRepo.Save(c.Request().Context(), user); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save user with new avatar")
+	}
+
+	metrics.ImagesUploaded.WithLabelValues("avatar").Inc()
+
+	image := AuditImage{ID: imageID.String(), User: string(user.ID)}
+	recordAudit(c, h.auditRepo, model.AuditActionCreate, model.AuditEntityImage, imageID, nil, auditSnapshot(image))
+
+	if oldAvatar != nil {
+		_ = h.fileRepo.DeleteImage(c.Request().Context(), oldAvatar.ID)
+		image := AuditImage{ID: oldAvatar.ID.String(), User: string(user.ID)}
+		recordAudit(c, h.auditRepo, model.AuditActionDelete, model.AuditEntityImage, oldAvatar.ID, auditSnapshot(image), nil)
+	}
+
+	return c.JSON(http.StatusCreated, map[string]string{
+		"id": imageID.String(),
+	})
+}
+
+func (h *UserHandler) Follow(c echo.Context) error {
+	return h.updateFollow(c, true)
+}
+
+func (h *UserHandler) Unfollow(c echo.Context) error {
+	return h.updateFollow(c, false)
+}
+
+func (h *UserHandler) updateFollow(c echo.Context, follow bool) error {
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	followee, err := model.NewUserID(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid user ID")
+	}
+
+	if follow {
+		if followee == model.UserID(userID) {
+			return errorResponse(c, http.StatusBadRequest, "You cannot follow yourself")
+		}
+
+		if _, err := h.userRepo.FindByID(c.Request().Context(), followee); err != nil {
+			if errors.Is(err, repository.ErrNotFound) {
+				return errorResponse(c, http.StatusNotFound, "User not found")
+			}
+
+			return errorResponse(c, http.StatusInternalServerError, "Failed to fetch user")
+		}
+
+		if err := h.userRepo.Follow(c.Request().Context(), model.UserID(userID), followee); err != nil {
+			return errorResponse(c, http.StatusInternalServerError, "Failed to follow user")
+		}
+	} else {
+		if err := h.userRepo.Unfollow(c.Request().Context(), model.UserID(userID), followee); err != nil {
+			return errorResponse(c, http.StatusInternalServerError, "Failed to unfollow user")
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
+
+func (h *UserHandler) AddFavoriteShop(c echo.Context) error {
+	return h.updateFavoriteShop(c, true)
+}
+
+func (h *UserHandler) RemoveFavoriteShop(c echo.Context) error {
+	return h.updateFavoriteShop(c, false)
+}
+
+func (h *UserHandler) updateFavoriteShop(c echo.Context, favorite bool) error {
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	shopID, err := uuid.Parse(c.Param("shopId"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid shop ID format")
+	}
+
+	if favorite {
+		shop, err := h.shopRepo.FindByID(c.Request().Context(), shopID)
+		if err != nil || shop == nil {
+			return errorResponse(c, http.StatusNotFound, "Shop not found")
+		}
+
+		if err := h.userRepo.AddFavoriteShop(c.Request().Context(), model.UserID(userID), shopID); err != nil {
+			return errorResponse(c, http.StatusInternalServerError, "Failed to add favorite shop")
+		}
+	} else {
+		if err := h.userRepo.RemoveFavoriteShop(c.Request().Context(), model.UserID(userID), shopID); err != nil {
+			return errorResponse(c, http.StatusInternalServerError, "Failed to remove favorite shop")
+		}
+	}
+
+	return c.NoContent(http.StatusNoContent)
+}
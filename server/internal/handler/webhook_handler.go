@@ -0,0 +1,341 @@
+package handler
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+)
+
+const defaultDeliveryLimit = 50
+
+type WebhookHandler struct {
+	webhookRepo repository.WebhookRepository
+}
+
+func NewWebhookHandler(webhookRepo repository.WebhookRepository) *WebhookHandler {
+	return &WebhookHandler{
+		webhookRepo: webhookRepo,
+	}
+}
+
+type Webhook struct {
+	ID string `json:"id"`
+
+	URL string `json:"url"`
+
+	// 作成時のレスポンスにのみ含まれる
+	Secret string `json:"secret,omitempty"`
+
+	Events []string `json:"events"`
+
+	Active bool `json:"active"`
+
+	CreatedBy string `json:"created_by,omitempty"`
+
+	CreatedAt time.Time `json:"created_at,omitempty"`
+
+	UpdatedAt time.Time `json:"updated_at,omitempty"`
+}
+
+func FromModelToWebhook(w *model.Webhook) *Webhook {
+	events := make([]string, len(w.Events))
+	for i, e := range w.Events {
+		events[i] = string(e)
+	}
+
+	return &Webhook{
+		ID:        w.ID.String(),
+		URL:       w.URL,
+		Events:    events,
+		Active:    w.Active,
+		CreatedBy: string(w.CreatedBy),
+		CreatedAt: w.CreatedAt,
+		UpdatedAt: w.UpdatedAt,
+	}
+}
+
+type WebhookDelivery struct {
+	ID string `json:"id"`
+
+	Event string `json:"event"`
+
+	Status string `json:"status"`
+
+	Attempts int `json:"attempts"`
+
+	NextAttemptAt *time.Time `json:"next_attempt_at,omitempty"`
+
+	LastError string `json:"last_error,omitempty"`
+
+	ResponseStatus int `json:"response_status,omitempty"`
+
+	CreatedAt time.Time `json:"created_at"`
+
+	DeliveredAt *time.Time `json:"delivered_at,omitempty"`
+}
+
+func FromModelToWebhookDelivery(d *model.WebhookDelivery) *WebhookDelivery {
+	var nextAttemptAt *time.Time
+	if d.Status == model.DeliveryPending {
+		nextAttemptAt = &d.NextAttemptAt
+	}
+
+	return &WebhookDelivery{
+		ID:             d.ID.String(),
+		Event:          string(d.Event),
+		Status:         string(d.Status),
+		Attempts:       d.Attempts,
+		NextAttemptAt:  nextAttemptAt,
+		LastError:      d.LastError,
+		ResponseStatus: d.ResponseStatus,
+		CreatedAt:      d.CreatedAt,
+		DeliveredAt:    d.DeliveredAt,
+	}
+}
+
+type APIV1WebhooksPostRequest struct {
+	URL string `json:"url"`
+
+	Events []string `json:"events"`
+
+	// 更新時のみ有効。省略した場合は変更しない
+	Active *bool `json:"active,omitempty"`
+}
+
+func (h *WebhookHandler) GetWebhooks(c echo.Context) error {
+	webhooks, err := h.webhookRepo.FindAll(c.Request().Context())
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch webhooks")
+	}
+
+	responses := make([]*Webhook, len(webhooks))
+	for i, w := range webhooks {
+		responses[i] = FromModelToWebhook(w)
+	}
+
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (h *WebhookHandler) CreateWebhook(c echo.Context) error {
+	var req APIV1WebhooksPostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	userID, err := GetUserID(c)
+	if err != nil {
+		return errorResponse(c, http.StatusUnauthorized, "Unauthorized: Failed to get user ID")
+	}
+
+	webhook, err := model.NewWebhook(req.URL, toEventTypes(req.Events), model.UserID(userID))
+	if err != nil {
+		return webhookValidationError(c, err)
+	}
+
+	if err := h.webhookRepo.Save(c.Request().Context(), webhook); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save webhook")
+	}
+
+	// シークレットは作成時にのみ返す
+	res := FromModelToWebhook(webhook)
+	res.Secret = webhook.Secret
+
+	return c.JSON(http.StatusCreated, res)
+}
+
+func (h *WebhookHandler) GetWebhook(c echo.Context) error {
+	webhook, err := h.findWebhook(c)
+	if err != nil {
+		return webhookLookupError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, FromModelToWebhook(webhook))
+}
+
+func (h *WebhookHandler) UpdateWebhook(c echo.Context) error {
+	var req APIV1WebhooksPostRequest
+	if err := c.Bind(&req); err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid request payload")
+	}
+
+	webhook, err := h.findWebhook(c)
+	if err != nil {
+		return webhookLookupError(c, err)
+	}
+
+	if req.URL != "" {
+		if err := model.ValidateWebhookURL(req.URL); err != nil {
+			return webhookValidationError(c, err)
+		}
+		webhook.URL = req.URL
+	}
+
+	if req.Events != nil {
+		events := toEventTypes(req.Events)
+		if err := model.ValidateWebhookEvents(events); err != nil {
+			return webhookValidationError(c, err)
+		}
+		webhook.Events = events
+	}
+
+	if req.Active != nil {
+		webhook.Active = *req.Active
+	}
+
+	webhook.UpdatedAt = time.Now()
+
+	if err := h.webhookRepo.Save(c.Request().Context(), webhook); err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to save webhook")
+	}
+
+	return c.JSON(http.StatusOK, FromModelToWebhook(webhook))
+}
+
+func (h *WebhookHandler) DeleteWebhook(c echo.Context) error {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return errorResponse(c, http.StatusBadRequest, "Invalid webhook ID")
+	}
+
+	if err := h.webhookRepo.Delete(c.Request().Context(), id); err != nil {
+		return webhookLookupError(c, err)
+	}
+
+	return c.JSON(http.StatusOK, map[string]string{
+		"message": "Webhook deleted successfully",
+	})
+}
+
+func (h *WebhookHandler) GetDeliveries(c echo.Context) error {
+	webhook, err := h.findWebhook(c)
+	if err != nil {
+		return webhookLookupError(c, err)
+	}
+
+	limit, err := strconv.Atoi(c.QueryParam("limit"))
+	if err != nil || limit <= 0 || limit > maxPageLimit {
+		limit = defaultDeliveryLimit
+	}
+
+	deliveries, err := h.webhookRepo.FindDeliveries(c.Request().Context(), webhook.ID, limit)
+	if err != nil {
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch webhook deliveries")
+	}
+
+	responses := make([]*WebhookDelivery, len(deliveries))
+	for i, d := range deliveries {
+		responses[i] = FromModelToWebhookDelivery(d)
+	}
+
+	return c.JSON(http.StatusOK, responses)
+}
+
+func (h *WebhookHandler) findWebhook(c echo.Context) (*model.Webhook, error) {
+	id, err := uuid.Parse(c.Param("id"))
+	if err != nil {
+		return nil, errInvalidWebhookID
+	}
+
+	return h.webhookRepo.FindByID(c.Request().Context(), id)
+}
+
+var errInvalidWebhookID = errors.New("invalid webhook ID")
+
+func webhookLookupError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, errInvalidWebhookID):
+		return errorResponse(c, http.StatusBadRequest, "Invalid webhook ID")
+	case errors.Is(err, repository.ErrNotFound):
+		return errorResponse(c, http.StatusNotFound, "Webhook not found")
+	default:
+		return errorResponse(c, http.StatusInternalServerError, "Failed to fetch webhook")
+	}
+}
+
+func webhookValidationError(c echo.Context, err error) error {
+	switch {
+	case errors.Is(err, model.ErrInvalidWebhookURL):
+		return errorResponse(c, http.StatusBadRequest, "Invalid webhook URL")
+	case errors.Is(err, model.ErrInvalidWebhookEvent):
+		return errorResponse(c, http.StatusBadRequest, "Invalid webhook events")
+	default:
+		return errorResponse(c, http.StatusInternalServerError, "Failed to create webhook")
+	}
+}
+
+func toEventTypes(events []string) []model.EventType {
+	types := make([]model.EventType, len(events))
+	for i, e := range events {
+		types[i] = model.EventType(e)
+	}
+
+	return types
+}
+
+// webhookPayload is the JSON body posted to webhook endpoints. ID is shared
+// by all deliveries of the same event so receivers can deduplicate retries.
+type webhookPayload struct {
+	ID        string    `json:"id"`
+	Event     string    `json:"event"`
+	CreatedAt time.Time `json:"created_at"`
+	Data      any       `json:"data"`
+}
+
+// notifyWebhooks queues a delivery of the event to every subscribed webhook.
+// Failures are only logged: the change itself has already been saved.
+func notifyWebhooks(c echo.Context, webhookRepo repository.WebhookRepository, event model.EventType, data any) {
+	ctx := c.Request().Context()
+
+	webhooks, err := webhookRepo.FindSubscribers(ctx, event)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to find webhook subscribers", "event", event, "error", err)
+
+		return
+	}
+	if len(webhooks) == 0 {
+		return
+	}
+
+	id, err := uuid.NewV7()
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to generate webhook event ID", "error", err)
+
+		return
+	}
+
+	payload, err := json.Marshal(webhookPayload{
+		ID:        id.String(),
+		Event:     string(event),
+		CreatedAt: time.Now(),
+		Data:      data,
+	})
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to marshal webhook payload", "event", event, "error", err)
+
+		return
+	}
+
+	deliveries := make([]*model.WebhookDelivery, 0, len(webhooks))
+	for _, w := range webhooks {
+		delivery, err := model.NewWebhookDelivery(w.ID, event, payload)
+		if err != nil {
+			logging.FromContext(ctx).Error("failed to create webhook delivery", "event", event, "webhook", w.ID, "error", err)
+
+			return
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := webhookRepo.EnqueueDeliveries(ctx, deliveries); err != nil {
+		logging.FromContext(ctx).Error("failed to enqueue webhook deliveries", "event", event, "error", err)
+	}
+}
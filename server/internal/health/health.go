@@ -0,0 +1,123 @@
+// Package health runs the checks behind the liveness and readiness probes.
+// Subsystems register a Checker under a name; every check runs concurrently
+// with its own timeout, and the report tells which components failed and how
+// long each took.
+package health
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// Checker reports whether a component works, returning nil when it does.
+type Checker interface {
+	Check(ctx context.Context) error
+}
+
+// CheckerFunc adapts a function such as (*sql.DB).PingContext to Checker.
+type CheckerFunc func(ctx context.Context) error
+
+func (f CheckerFunc) Check(ctx context.Context) error {
+	return f(ctx)
+}
+
+type namedChecker struct {
+	name    string
+	checker Checker
+}
+
+// Registry is a set of named checks.
+type Registry struct {
+	timeout time.Duration
+
+	mu       sync.RWMutex
+	checkers []namedChecker
+}
+
+// NewRegistry returns an empty registry whose checks fail when they take
+// longer than timeout.
+func NewRegistry(timeout time.Duration) *Registry {
+	return &Registry{
+		timeout: timeout,
+	}
+}
+
+// Register adds a check. Names identify the components in the report, so
+// they should be unique.
+func (r *Registry) Register(name string, checker Checker) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.checkers = append(r.checkers, namedChecker{name: name, checker: checker})
+}
+
+// Result is the outcome of a single check.
+type Result struct {
+	Name    string
+	Err     error
+	Latency time.Duration
+}
+
+// Report is the outcome of every check of a registry, in registration order.
+type Report struct {
+	Results []Result
+}
+
+// Healthy reports whether every check passed.
+func (r *Report) Healthy() bool {
+	for _, result := range r.Results {
+		if result.Err != nil {
+			return false
+		}
+	}
+
+	return true
+}
+
+// Run runs every check concurrently and waits for all of them.
+func (r *Registry) Run(ctx context.Context) *Report {
+	r.mu.RLock()
+	checkers := make([]namedChecker, len(r.checkers))
+	copy(checkers, r.checkers)
+	r.mu.RUnlock()
+
+	report := &Report{Results: make([]Result, len(checkers))}
+
+	var wg sync.WaitGroup
+	for i, c := range checkers {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			report.Results[i] = r.run(ctx, c)
+		}()
+	}
+	wg.Wait()
+
+	return report
+}
+
+func (r *Registry) run(ctx context.Context, c namedChecker) Result {
+	ctx, cancel := context.WithTimeout(ctx, r.timeout)
+	defer cancel()
+
+	start := time.Now()
+	// タイムアウトを無視するチェックがあっても、応答は待たせない
+	done := make(chan error, 1)
+	go func() {
+		done <- c.checker.Check(ctx)
+	}()
+
+	var err error
+	select {
+	case err = <-done:
+	case <-ctx.Done():
+		err = ctx.Err()
+	}
+
+	return Result{
+		Name:    c.name,
+		Err:     err,
+		Latency: time.Since(start),
+	}
+}
@@ -0,0 +1,163 @@
+package database
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type AuditLogDto struct {
+	ID             string    `db:"id"`
+	Actor          string    `db:"actor"`
+	Action         string    `db:"action"`
+	EntityType     string    `db:"entity_type"`
+	EntityID       string    `db:"entity_id"`
+	BeforeSnapshot []byte    `db:"before_snapshot"`
+	AfterSnapshot  []byte    `db:"after_snapshot"`
+	RequestID      string    `db:"request_id"`
+	CreatedAt      time.Time `db:"created_at"`
+}
+
+func (dto *AuditLogDto) ToModel() (*model.AuditLog, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse audit log UUID: %w", err)
+	}
+
+	entityID, err := uuid.Parse(dto.EntityID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse audited entity UUID: %w", err)
+	}
+
+	action, err := model.ParseAuditAction(dto.Action)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AuditAction: %w", err)
+	}
+
+	entityType, err := model.ParseAuditEntityType(dto.EntityType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse AuditEntityType: %w", err)
+	}
+
+	return &model.AuditLog{
+		ID:         id,
+		Actor:      model.UserID(dto.Actor),
+		Action:     action,
+		EntityType: entityType,
+		EntityID:   entityID,
+		Before:     dto.BeforeSnapshot,
+		After:      dto.AfterSnapshot,
+		RequestID:  dto.RequestID,
+		CreatedAt:  dto.CreatedAt,
+	}, nil
+}
+
+func (dto *AuditLogDto) FromModel(log *model.AuditLog) {
+	dto.ID = log.ID.String()
+	dto.Actor = string(log.Actor)
+	dto.Action = string(log.Action)
+	dto.EntityType = string(log.EntityType)
+	dto.EntityID = log.EntityID.String()
+	dto.BeforeSnapshot = log.Before
+	dto.AfterSnapshot = log.After
+	dto.RequestID = log.RequestID
+	dto.CreatedAt = log.CreatedAt
+}
+
+type AuditLogRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewAuditLogRepository(db *sqlx.DB) repository.AuditLogRepository {
+	return &AuditLogRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *AuditLogRepositoryImpl) Save(ctx context.Context, log *model.AuditLog) error {
+	dto := &AuditLogDto{}
+	dto.FromModel(log)
+
+	query := `
+INSERT INTO audit_logs (id, actor, action, entity_type, entity_id, before_snapshot, after_snapshot, request_id, created_at)
+VALUES (:id, :actor, :action, :entity_type, :entity_id, :before_snapshot, :after_snapshot, :request_id, :created_at)
+`
+	_, err := r.db.NamedExecContext(ctx, query, dto)
+	if err != nil {
+		return fmt.Errorf("failed to save audit log: %w", err)
+	}
+
+	return nil
+}
+
+func (r *AuditLogRepositoryImpl) Find(
+	ctx context.Context,
+	filter repository.AuditLogFilter,
+	limit int,
+	offset int,
+) ([]*model.AuditLog, error) {
+	var conditions []string
+	var args []any
+	if filter.Actor != "" {
+		conditions = append(conditions, "actor = ?")
+		args = append(args, string(filter.Actor))
+	}
+	if filter.Action != "" {
+		conditions = append(conditions, "action = ?")
+		args = append(args, string(filter.Action))
+	}
+	if filter.EntityType != "" {
+		conditions = append(conditions, "entity_type = ?")
+		args = append(args, string(filter.EntityType))
+	}
+	if filter.EntityID != uuid.Nil {
+		conditions = append(conditions, "entity_id = ?")
+		args = append(args, filter.EntityID.String())
+	}
+	if !filter.Since.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.Since)
+	}
+	if !filter.Until.IsZero() {
+		conditions = append(conditions, "created_at < ?")
+		args = append(args, filter.Until)
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	// IDs are UUIDv7, so they break ties between entries of the same second
+	query := fmt.Sprintf(`
+		SELECT *
+		FROM audit_logs
+		%s
+		ORDER BY created_at DESC, id DESC
+		LIMIT ? OFFSET ?
+	`, whereClause)
+	args = append(args, limit, offset)
+
+	var dtos []AuditLogDto
+	if err := r.db.SelectContext(ctx, &dtos, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get audit logs: %w", err)
+	}
+
+	logs := make([]*model.AuditLog, 0, len(dtos))
+	for _, dto := range dtos {
+		log, err := dto.ToModel()
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
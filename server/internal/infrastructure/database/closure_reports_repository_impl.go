@@ -0,0 +1,175 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type ClosureReportDto struct {
+	ID         string         `db:"id"`
+	ShopID     string         `db:"shop_id"`
+	Reporter   string         `db:"reporter"`
+	Status     string         `db:"status"`
+	MovedTo    sql.NullString `db:"moved_to"`
+	Note       string         `db:"note"`
+	CreatedAt  time.Time      `db:"created_at"`
+	ResolvedAt sql.NullTime   `db:"resolved_at"`
+}
+
+func (dto *ClosureReportDto) ToModel() (*model.ClosureReport, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse closure report UUID: %w", err)
+	}
+
+	shopID, err := uuid.Parse(dto.ShopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse shop UUID: %w", err)
+	}
+
+	status, err := model.ParseShopStatus(dto.Status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ShopStatus: %w", err)
+	}
+
+	movedTo := uuid.Nil
+	if dto.MovedTo.Valid {
+		movedTo, err = uuid.Parse(dto.MovedTo.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse successor shop UUID: %w", err)
+		}
+	}
+
+	return &model.ClosureReport{
+		ID:         id,
+		Shop:       shopID,
+		Reporter:   model.UserID(dto.Reporter),
+		Status:     status,
+		MovedTo:    movedTo,
+		Note:       dto.Note,
+		CreatedAt:  dto.CreatedAt,
+		ResolvedAt: dto.ResolvedAt.Time,
+	}, nil
+}
+
+func (dto *ClosureReportDto) FromModel(report *model.ClosureReport) {
+	dto.ID = report.ID.String()
+	dto.ShopID = report.Shop.String()
+	dto.Reporter = string(report.Reporter)
+	dto.Status = string(report.Status)
+	dto.MovedTo = sql.NullString{}
+	if report.MovedTo != uuid.Nil {
+		dto.MovedTo = sql.NullString{String: report.MovedTo.String(), Valid: true}
+	}
+	dto.Note = report.Note
+	dto.CreatedAt = report.CreatedAt
+	dto.ResolvedAt = sql.NullTime{Time: report.ResolvedAt, Valid: !report.ResolvedAt.IsZero()}
+}
+
+type ClosureReportRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewClosureReportRepository(db *sqlx.DB) repository.ClosureReportRepository {
+	return &ClosureReportRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *ClosureReportRepositoryImpl) Save(ctx context.Context, report *model.ClosureReport) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	dto := &ClosureReportDto{}
+	dto.FromModel(report)
+
+	// Replace the pending report of the same user
+	_, err = tx.ExecContext(ctx, `
+DELETE FROM closure_reports
+WHERE shop_id = ? AND reporter = ? AND resolved_at IS NULL AND id <> ?
+`, dto.ShopID, dto.Reporter, dto.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete previous closure report: %w", err)
+	}
+
+	query := `
+INSERT INTO closure_reports (id, shop_id, reporter, status, moved_to, note, created_at, resolved_at)
+VALUES (:id, :shop_id, :reporter, :status, :moved_to, :note, :created_at, :resolved_at)
+ON DUPLICATE KEY UPDATE
+status = VALUES(status),
+moved_to = VALUES(moved_to),
+note = VALUES(note),
+resolved_at = VALUES(resolved_at)
+`
+	_, err = tx.NamedExecContext(ctx, query, dto)
+	if err != nil {
+		return fmt.Errorf("failed to save closure report: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ClosureReportRepositoryImpl) FindPending(
+	ctx context.Context,
+	shopID uuid.UUID,
+	since time.Time,
+) ([]*model.ClosureReport, error) {
+	query := `
+SELECT *
+FROM closure_reports
+WHERE shop_id = ? AND resolved_at IS NULL AND created_at >= ?
+ORDER BY created_at DESC, id DESC
+`
+
+	var dtos []ClosureReportDto
+	err := r.db.SelectContext(ctx, &dtos, query, shopID.String(), since)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get closure reports: %w", err)
+	}
+
+	reports := make([]*model.ClosureReport, 0, len(dtos))
+	for _, dto := range dtos {
+		report, err := dto.ToModel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+// resolveClosureReportsTx marks every pending report of a shop as resolved
+// inside tx.
+func resolveClosureReportsTx(ctx context.Context, tx *sqlx.Tx, shopID uuid.UUID, at time.Time) error {
+	query := `UPDATE closure_reports SET resolved_at = ? WHERE shop_id = ? AND resolved_at IS NULL`
+
+	_, err := tx.ExecContext(ctx, query, at, shopID.String())
+	if err != nil {
+		return fmt.Errorf("failed to resolve closure reports: %w", err)
+	}
+
+	return nil
+}
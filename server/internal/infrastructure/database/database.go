@@ -0,0 +1,50 @@
+package database
+
+import (
+	"errors"
+	"strings"
+
+	"backend/internal/domain/model"
+
+	"github.com/go-sql-driver/mysql"
+)
+
+// iterateBatchSize is the number of rows fetched per round trip by the
+// Iterate methods. Rows are walked with keyset pagination on the primary key,
+// so memory usage stays constant regardless of the table size.
+const iterateBatchSize = 100
+
+// addedImages returns the images that are not contained in existingIDs.
+func addedImages(existingIDs []string, images []model.ImageFile) []model.ImageFile {
+	existing := make(map[string]struct{}, len(existingIDs))
+	for _, id := range existingIDs {
+		existing[id] = struct{}{}
+	}
+
+	var added []model.ImageFile
+	for _, image := range images {
+		if _, ok := existing[image.ID.String()]; !ok {
+			added = append(added, image)
+		}
+	}
+
+	return added
+}
+
+var likeEscaper = strings.NewReplacer(`\`, `\\`, "%", `\%`, "_", `\_`)
+
+// escapeLike escapes the wildcard characters of a LIKE pattern so user input
+// is matched literally.
+func escapeLike(s string) string {
+	return likeEscaper.Replace(s)
+}
+
+// mysqlErrDupEntry is the MySQL error number for a duplicate key.
+const mysqlErrDupEntry = 1062
+
+// isDuplicateEntry reports whether err is a unique key violation.
+func isDuplicateEntry(err error) bool {
+	var mysqlErr *mysql.MySQLError
+
+	return errors.As(err, &mysqlErr) && mysqlErr.Number == mysqlErrDupEntry
+}
@@ -0,0 +1,327 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type EditProposalDto struct {
+	ID         string         `db:"id"`
+	ShopID     string         `db:"shop_id"`
+	Proposer   string         `db:"proposer"`
+	Changes    []byte         `db:"changes"`
+	Note       string         `db:"note"`
+	Status     string         `db:"status"`
+	Reviewer   sql.NullString `db:"reviewer"`
+	ReviewNote string         `db:"review_note"`
+	CreatedAt  time.Time      `db:"created_at"`
+	ReviewedAt sql.NullTime   `db:"reviewed_at"`
+}
+
+type fieldChangeDto struct {
+	Field string          `json:"field"`
+	Old   json.RawMessage `json:"old"`
+	New   json.RawMessage `json:"new"`
+}
+
+func (dto *EditProposalDto) ToModel() (*model.EditProposal, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse edit proposal UUID: %w", err)
+	}
+
+	shopID, err := uuid.Parse(dto.ShopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse shop UUID: %w", err)
+	}
+
+	status, err := model.ParseEditProposalStatus(dto.Status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse EditProposalStatus: %w", err)
+	}
+
+	var changeDtos []fieldChangeDto
+	if err := json.Unmarshal(dto.Changes, &changeDtos); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal edit proposal changes: %w", err)
+	}
+
+	changes := make([]model.FieldChange, len(changeDtos))
+	for i, c := range changeDtos {
+		field := model.ShopField(c.Field)
+		old, err := decodeFieldValue(field, c.Old)
+		if err != nil {
+			return nil, err
+		}
+		value, err := decodeFieldValue(field, c.New)
+		if err != nil {
+			return nil, err
+		}
+		changes[i] = model.FieldChange{Field: field, Old: old, New: value}
+	}
+
+	return &model.EditProposal{
+		ID:         id,
+		Shop:       shopID,
+		Proposer:   model.UserID(dto.Proposer),
+		Changes:    changes,
+		Note:       dto.Note,
+		Status:     status,
+		Reviewer:   model.UserID(dto.Reviewer.String),
+		ReviewNote: dto.ReviewNote,
+		CreatedAt:  dto.CreatedAt,
+		ReviewedAt: dto.ReviewedAt.Time,
+	}, nil
+}
+
+func (dto *EditProposalDto) FromModel(proposal *model.EditProposal) error {
+	changeDtos := make([]fieldChangeDto, len(proposal.Changes))
+	for i, c := range proposal.Changes {
+		old, err := json.Marshal(c.Old)
+		if err != nil {
+			return fmt.Errorf("failed to marshal edit proposal change: %w", err)
+		}
+		value, err := json.Marshal(c.New)
+		if err != nil {
+			return fmt.Errorf("failed to marshal edit proposal change: %w", err)
+		}
+		changeDtos[i] = fieldChangeDto{Field: string(c.Field), Old: old, New: value}
+	}
+
+	changes, err := json.Marshal(changeDtos)
+	if err != nil {
+		return fmt.Errorf("failed to marshal edit proposal changes: %w", err)
+	}
+
+	dto.ID = proposal.ID.String()
+	dto.ShopID = proposal.Shop.String()
+	dto.Proposer = string(proposal.Proposer)
+	dto.Changes = changes
+	dto.Note = proposal.Note
+	dto.Status = string(proposal.Status)
+	dto.Reviewer = sql.NullString{String: string(proposal.Reviewer), Valid: proposal.Reviewer != ""}
+	dto.ReviewNote = proposal.ReviewNote
+	dto.CreatedAt = proposal.CreatedAt
+	dto.ReviewedAt = sql.NullTime{Time: proposal.ReviewedAt, Valid: !proposal.ReviewedAt.IsZero()}
+
+	return nil
+}
+
+// decodeFieldValue restores the Go type that the model uses for field.
+func decodeFieldValue(field model.ShopField, raw json.RawMessage) (any, error) {
+	var value any
+	var err error
+	switch field {
+	case model.ShopFieldName, model.ShopFieldAddress, model.ShopFieldPostCode:
+		var v string
+		err = json.Unmarshal(raw, &v)
+		value = v
+	case model.ShopFieldLocation:
+		var v [2]float64
+		err = json.Unmarshal(raw, &v)
+		value = v
+	case model.ShopFieldPaymentMethods:
+		var v []string
+		err = json.Unmarshal(raw, &v)
+		value = v
+	case model.ShopFieldStations, model.ShopFieldTags:
+		var v []uuid.UUID
+		err = json.Unmarshal(raw, &v)
+		value = v
+	default:
+		return nil, fmt.Errorf("unknown shop field %q", field)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to unmarshal %s: %w", field, err)
+	}
+
+	return value, nil
+}
+
+type EditProposalRepositoryImpl struct {
+	db    *sqlx.DB
+	shops *ShopRepositoryImpl
+}
+
+func NewEditProposalRepository(db *sqlx.DB, publisher repository.EventPublisher) repository.EditProposalRepository {
+	return &EditProposalRepositoryImpl{
+		db:    db,
+		shops: &ShopRepositoryImpl{db: db, publisher: publisher},
+	}
+}
+
+func (r *EditProposalRepositoryImpl) Save(ctx context.Context, proposal *model.EditProposal) error {
+	dto := &EditProposalDto{}
+	if err := dto.FromModel(proposal); err != nil {
+		return err
+	}
+
+	query := `
+INSERT INTO edit_proposals (id, shop_id, proposer, changes, note, status, reviewer, review_note, created_at, reviewed_at)
+VALUES (:id, :shop_id, :proposer, :changes, :note, :status, :reviewer, :review_note, :created_at, :reviewed_at)
+ON DUPLICATE KEY UPDATE
+status = VALUES(status),
+reviewer = VALUES(reviewer),
+review_note = VALUES(review_note),
+reviewed_at = VALUES(reviewed_at)
+`
+	_, err := r.db.NamedExecContext(ctx, query, dto)
+	if err != nil {
+		return fmt.Errorf("failed to save edit proposal: %w", err)
+	}
+
+	return nil
+}
+
+func (r *EditProposalRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*model.EditProposal, error) {
+	var dto EditProposalDto
+	err := r.db.GetContext(ctx, &dto, `SELECT * FROM edit_proposals WHERE id = ?`, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("edit proposal %w", repository.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to get edit proposal: %w", err)
+	}
+
+	return dto.ToModel()
+}
+
+func (r *EditProposalRepositoryImpl) FindByShop(ctx context.Context, shopID uuid.UUID) ([]*model.EditProposal, error) {
+	query := `
+SELECT *
+FROM edit_proposals
+WHERE shop_id = ?
+ORDER BY created_at DESC, id DESC
+`
+
+	return r.selectProposals(ctx, query, shopID.String())
+}
+
+func (r *EditProposalRepositoryImpl) FindPending(ctx context.Context, registerer model.UserID) ([]*model.EditProposal, error) {
+	if registerer == "" {
+		query := `
+SELECT *
+FROM edit_proposals
+WHERE status = ?
+ORDER BY created_at, id
+`
+
+		return r.selectProposals(ctx, query, string(model.EditProposalPending))
+	}
+
+	query := `
+SELECT p.*
+FROM edit_proposals p
+INNER JOIN shops s ON s.id = p.shop_id
+WHERE p.status = ? AND s.registerer = ?
+ORDER BY p.created_at, p.id
+`
+
+	return r.selectProposals(ctx, query, string(model.EditProposalPending), string(registerer))
+}
+
+func (r *EditProposalRepositoryImpl) selectProposals(ctx context.Context, query string, args ...any) ([]*model.EditProposal, error) {
+	var dtos []EditProposalDto
+	if err := r.db.SelectContext(ctx, &dtos, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get edit proposals: %w", err)
+	}
+
+	proposals := make([]*model.EditProposal, 0, len(dtos))
+	for _, dto := range dtos {
+		proposal, err := dto.ToModel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+		}
+		proposals = append(proposals, proposal)
+	}
+
+	return proposals, nil
+}
+
+func (r *EditProposalRepositoryImpl) Accept(
+	ctx context.Context,
+	proposal *model.EditProposal,
+	shop *model.Shop,
+	base time.Time,
+) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	// Lock the shop so that nobody updates it between the check and the save
+	var updatedAt time.Time
+	err = tx.GetContext(ctx, &updatedAt, `SELECT updated_at FROM shops WHERE id = ? FOR UPDATE`, shop.ID.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return fmt.Errorf("shop %w", repository.ErrNotFound)
+		}
+
+		return fmt.Errorf("failed to get shop: %w", err)
+	}
+	if !updatedAt.Equal(base) {
+		return fmt.Errorf("shop was updated: %w", repository.ErrConflict)
+	}
+
+	if err := saveReviewResult(ctx, tx, proposal); err != nil {
+		return err
+	}
+
+	events, err := r.shops.saveTx(ctx, tx, shop)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.shops.publisher.Publish(events...)
+
+	return nil
+}
+
+func (r *EditProposalRepositoryImpl) Reject(ctx context.Context, proposal *model.EditProposal) error {
+	return saveReviewResult(ctx, r.db, proposal)
+}
+
+// saveReviewResult stores the status and review of proposal, provided that it
+// is still pending. Otherwise it fails with ErrConflict, so that concurrent
+// reviews cannot overwrite each other.
+func saveReviewResult(ctx context.Context, db sqlx.ExecerContext, proposal *model.EditProposal) error {
+	result, err := db.ExecContext(ctx, `
+UPDATE edit_proposals
+SET status = ?, reviewer = ?, review_note = ?, reviewed_at = ?
+WHERE id = ? AND status = ?
+`, string(proposal.Status), string(proposal.Reviewer), proposal.ReviewNote, proposal.ReviewedAt,
+		proposal.ID.String(), string(model.EditProposalPending))
+	if err != nil {
+		return fmt.Errorf("failed to save edit proposal: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("edit proposal was reviewed: %w", repository.ErrConflict)
+	}
+
+	return nil
+}
@@ -0,0 +1,236 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type EventDto struct {
+	ID        string         `db:"id"`
+	Type      string         `db:"type"`
+	Actor     sql.NullString `db:"actor"`
+	ShopID    sql.NullString `db:"shop_id"`
+	ReviewID  sql.NullString `db:"review_id"`
+	StationID sql.NullString `db:"station_id"`
+	ImageID   sql.NullString `db:"image_id"`
+	Payload   []byte         `db:"payload"`
+	CreatedAt time.Time      `db:"created_at"`
+}
+
+func (dto *EventDto) ToModel() (*model.Event, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse event UUID: %w", err)
+	}
+
+	related := make([]uuid.UUID, 4)
+	for i, v := range []sql.NullString{dto.ShopID, dto.ReviewID, dto.StationID, dto.ImageID} {
+		if !v.Valid {
+			continue
+		}
+		related[i], err = uuid.Parse(v.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse related UUID: %w", err)
+		}
+	}
+
+	var payload map[string]any
+	if len(dto.Payload) > 0 {
+		if err := json.Unmarshal(dto.Payload, &payload); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal event payload: %w", err)
+		}
+	}
+
+	return &model.Event{
+		ID:        id,
+		Type:      model.EventType(dto.Type),
+		Actor:     model.UserID(dto.Actor.String),
+		Shop:      related[0],
+		Review:    related[1],
+		Station:   related[2],
+		Image:     related[3],
+		Payload:   payload,
+		CreatedAt: dto.CreatedAt,
+	}, nil
+}
+
+func (dto *EventDto) FromModel(event *model.Event) error {
+	payload, err := json.Marshal(event.Payload)
+	if err != nil {
+		return fmt.Errorf("failed to marshal event payload: %w", err)
+	}
+
+	dto.ID = event.ID.String()
+	dto.Type = string(event.Type)
+	dto.Actor = nullString(string(event.Actor))
+	dto.ShopID = nullUUID(event.Shop)
+	dto.ReviewID = nullUUID(event.Review)
+	dto.StationID = nullUUID(event.Station)
+	dto.ImageID = nullUUID(event.Image)
+	dto.Payload = payload
+	dto.CreatedAt = event.CreatedAt
+
+	return nil
+}
+
+func nullString(s string) sql.NullString {
+	return sql.NullString{String: s, Valid: s != ""}
+}
+
+func nullUUID(id uuid.UUID) sql.NullString {
+	if id == uuid.Nil {
+		return sql.NullString{}
+	}
+
+	return sql.NullString{String: id.String(), Valid: true}
+}
+
+// insertEvents writes events to the outbox as part of the caller's
+// transaction.
+func insertEvents(ctx context.Context, tx *sqlx.Tx, events ...*model.Event) error {
+	query := `
+		INSERT INTO events (id, type, actor, shop_id, review_id, station_id, image_id, payload, created_at)
+		VALUES (:id, :type, :actor, :shop_id, :review_id, :station_id, :image_id, :payload, :created_at)
+	`
+
+	for _, event := range events {
+		dto := &EventDto{}
+		if err := dto.FromModel(event); err != nil {
+			return err
+		}
+
+		if _, err := tx.NamedExecContext(ctx, query, dto); err != nil {
+			return fmt.Errorf("failed to save event: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// eventActor attributes a change to the authenticated user, falling back to
+// the given owner of the entity when the change was not made through the API.
+func eventActor(ctx context.Context, fallback model.UserID) model.UserID {
+	if id, ok := model.UserIDFromContext(ctx); ok {
+		return id
+	}
+
+	return fallback
+}
+
+// upsertEventType picks the event type from the affected row count of an
+// INSERT ... ON DUPLICATE KEY UPDATE: 1 for an insert, 2 for an update and 0
+// when nothing changed.
+func upsertEventType(result sql.Result, created, updated model.EventType) (model.EventType, bool, error) {
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	switch rowsAffected {
+	case 1:
+		return created, true, nil
+	case 0:
+		return "", false, nil
+	default:
+		return updated, true, nil
+	}
+}
+
+type EventRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewEventRepository(db *sqlx.DB) repository.EventRepository {
+	return &EventRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *EventRepositoryImpl) FindFeed(
+	ctx context.Context,
+	filter repository.EventFilter,
+	cursor uuid.UUID,
+	limit int,
+) ([]*model.Event, error) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if len(filter.Types) > 0 {
+		placeholders := make([]string, len(filter.Types))
+		for i, t := range filter.Types {
+			placeholders[i] = "?"
+			args = append(args, string(t))
+		}
+		conditions = append(conditions, "type IN ("+strings.Join(placeholders, ", ")+")")
+	}
+
+	if filter.Station != uuid.Nil {
+		conditions = append(conditions, `(station_id = ? OR shop_id IN (
+			SELECT shop_id FROM shop_stations WHERE station_id = ?
+		))`)
+		args = append(args, filter.Station.String(), filter.Station.String())
+	}
+
+	// フォロー中のユーザーとお気に入りの店舗はどちらかに当てはまれば表示する
+	personal := []string{}
+	if filter.Following != "" {
+		personal = append(personal, "actor IN (SELECT followee FROM user_follows WHERE follower = ?)")
+		args = append(args, string(filter.Following))
+	}
+	if filter.FavoritesOf != "" {
+		personal = append(personal, "shop_id IN (SELECT shop_id FROM favorite_shops WHERE user_id = ?)")
+		args = append(args, string(filter.FavoritesOf))
+	}
+	if len(personal) > 0 {
+		conditions = append(conditions, "("+strings.Join(personal, " OR ")+")")
+	}
+
+	// 非表示のレビューに関するイベントはフィードに出さない
+	conditions = append(conditions, "(review_id IS NULL OR review_id NOT IN (SELECT id FROM reviews WHERE hidden = TRUE))")
+
+	if cursor != uuid.Nil {
+		conditions = append(conditions, "id < ?")
+		args = append(args, cursor.String())
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT *
+		FROM events
+		%s
+		ORDER BY id DESC
+		LIMIT ?
+	`, whereClause)
+
+	args = append(args, limit)
+
+	var dtos []EventDto
+	if err := r.db.SelectContext(ctx, &dtos, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get events: %w", err)
+	}
+
+	events := make([]*model.Event, 0, len(dtos))
+	for _, dto := range dtos {
+		event, err := dto.ToModel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+		}
+		events = append(events, event)
+	}
+
+	return events, nil
+}
@@ -0,0 +1,147 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type IdempotencyRecordDto struct {
+	UserID         string    `db:"user_id"`
+	IdempotencyKey string    `db:"idempotency_key"`
+	ReservationID  string    `db:"reservation_id"`
+	RequestHash    string    `db:"request_hash"`
+	StatusCode     int       `db:"status_code"`
+	ContentType    string    `db:"content_type"`
+	Body           []byte    `db:"body"`
+	CreatedAt      time.Time `db:"created_at"`
+	ExpiresAt      time.Time `db:"expires_at"`
+}
+
+func (dto *IdempotencyRecordDto) ToModel() *model.IdempotencyRecord {
+	// マイグレーション前に予約されたキーは空になっている
+	reservationID, _ := uuid.Parse(dto.ReservationID)
+
+	return &model.IdempotencyRecord{
+		User:          model.UserID(dto.UserID),
+		Key:           dto.IdempotencyKey,
+		ReservationID: reservationID,
+		RequestHash:   dto.RequestHash,
+		StatusCode:    dto.StatusCode,
+		ContentType:   dto.ContentType,
+		Body:          dto.Body,
+		CreatedAt:     dto.CreatedAt,
+		ExpiresAt:     dto.ExpiresAt,
+	}
+}
+
+func (dto *IdempotencyRecordDto) FromModel(record *model.IdempotencyRecord) {
+	dto.UserID = string(record.User)
+	dto.IdempotencyKey = record.Key
+	dto.ReservationID = record.ReservationID.String()
+	dto.RequestHash = record.RequestHash
+	dto.StatusCode = record.StatusCode
+	dto.ContentType = record.ContentType
+	dto.Body = record.Body
+	dto.CreatedAt = record.CreatedAt
+	dto.ExpiresAt = record.ExpiresAt
+}
+
+type IdempotencyRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewIdempotencyRepository(db *sqlx.DB) repository.IdempotencyRepository {
+	return &IdempotencyRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *IdempotencyRepositoryImpl) Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	dto := &IdempotencyRecordDto{}
+	dto.FromModel(record)
+
+	// Expired keys of the user are cleaned up here, so a key past its TTL can
+	// be used again.
+	_, err := r.db.ExecContext(ctx, `
+DELETE FROM idempotency_keys WHERE user_id = ? AND expires_at <= ?
+`, dto.UserID, dto.CreatedAt)
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete expired idempotency keys: %w", err)
+	}
+
+	query := `
+INSERT INTO idempotency_keys (user_id, idempotency_key, reservation_id, request_hash, status_code, content_type, body, created_at, expires_at)
+VALUES (:user_id, :idempotency_key, :reservation_id, :request_hash, :status_code, :content_type, :body, :created_at, :expires_at)
+`
+	_, err = r.db.NamedExecContext(ctx, query, dto)
+	if err == nil {
+		return nil, nil
+	}
+	if !isDuplicateEntry(err) {
+		return nil, fmt.Errorf("failed to reserve idempotency key: %w", err)
+	}
+
+	existing := &IdempotencyRecordDto{}
+	err = r.db.GetContext(ctx, existing, `
+SELECT user_id, idempotency_key, reservation_id, request_hash, status_code, content_type, body, created_at, expires_at
+FROM idempotency_keys
+WHERE user_id = ? AND idempotency_key = ?
+`, dto.UserID, dto.IdempotencyKey)
+	// The other request released the key in the meantime
+	if errors.Is(err, sql.ErrNoRows) {
+		return nil, fmt.Errorf("idempotency key %w", repository.ErrConflict)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key: %w", err)
+	}
+
+	return existing.ToModel(), nil
+}
+
+func (r *IdempotencyRepositoryImpl) Complete(ctx context.Context, record *model.IdempotencyRecord) error {
+	dto := &IdempotencyRecordDto{}
+	dto.FromModel(record)
+
+	query := `
+UPDATE idempotency_keys
+SET status_code = :status_code, content_type = :content_type, body = :body, expires_at = :expires_at
+WHERE user_id = :user_id AND idempotency_key = :idempotency_key
+  AND reservation_id = :reservation_id AND status_code = 0
+`
+	result, err := r.db.NamedExecContext(ctx, query, dto)
+	if err != nil {
+		return fmt.Errorf("failed to complete idempotency key: %w", err)
+	}
+
+	rows, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get affected rows: %w", err)
+	}
+	// The lease expired and another request reserved the key
+	if rows == 0 {
+		return fmt.Errorf("idempotency key %w", repository.ErrConflict)
+	}
+
+	return nil
+}
+
+func (r *IdempotencyRepositoryImpl) Release(ctx context.Context, record *model.IdempotencyRecord) error {
+	_, err := r.db.ExecContext(ctx, `
+DELETE FROM idempotency_keys
+WHERE user_id = ? AND idempotency_key = ? AND reservation_id = ? AND status_code = 0
+`, string(record.User), record.Key, record.ReservationID.String())
+	if err != nil {
+		return fmt.Errorf("failed to release idempotency key: %w", err)
+	}
+
+	return nil
+}
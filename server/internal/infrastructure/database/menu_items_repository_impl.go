@@ -0,0 +1,265 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// recommendPriorWeight and recommendPriorRating shrink the average rating of
+// rarely rated dishes towards the middle of the 0-3 scale, so a single
+// 3-star rating does not outrank a dish many people liked.
+const (
+	recommendPriorWeight = 2
+	recommendPriorRating = 1.5
+)
+
+type MenuItemDto struct {
+	ID        string         `db:"id"`
+	ShopID    string         `db:"shop_id"`
+	Name      string         `db:"name"`
+	Price     int            `db:"price"`
+	Category  string         `db:"category"`
+	ImageID   sql.NullString `db:"image_id"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func (dto *MenuItemDto) ToModel() (*model.MenuItem, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse menu item UUID: %w", err)
+	}
+
+	shopID, err := uuid.Parse(dto.ShopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse shop UUID: %w", err)
+	}
+
+	name, err := model.NewMenuItemName(dto.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create MenuItemName: %w", err)
+	}
+
+	price, err := model.NewPrice(dto.Price)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create Price: %w", err)
+	}
+
+	var image *model.ImageFile
+	if dto.ImageID.Valid {
+		imageID, err := uuid.Parse(dto.ImageID.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse image ID: %w", err)
+		}
+		image = model.NewImageFile(imageID)
+	}
+
+	return &model.MenuItem{
+		ID:        id,
+		Shop:      shopID,
+		Name:      name,
+		Price:     price,
+		Category:  dto.Category,
+		Image:     image,
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+	}, nil
+}
+
+func (dto *MenuItemDto) FromModel(item *model.MenuItem) {
+	dto.ID = item.ID.String()
+	dto.ShopID = item.Shop.String()
+	dto.Name = string(item.Name)
+	dto.Price = int(item.Price)
+	dto.Category = item.Category
+	dto.ImageID = sql.NullString{}
+	if item.Image != nil {
+		dto.ImageID = sql.NullString{String: item.Image.ID.String(), Valid: true}
+	}
+	dto.CreatedAt = item.CreatedAt
+	dto.UpdatedAt = item.UpdatedAt
+}
+
+type MenuItemRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewMenuItemRepository(db *sqlx.DB) repository.MenuItemRepository {
+	return &MenuItemRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *MenuItemRepositoryImpl) Save(ctx context.Context, item *model.MenuItem) error {
+	dto := &MenuItemDto{}
+	dto.FromModel(item)
+
+	query := `
+		INSERT INTO menu_items (id, shop_id, name, price, category, image_id, created_at, updated_at)
+		VALUES (:id, :shop_id, :name, :price, :category, :image_id, :created_at, :updated_at)
+		ON DUPLICATE KEY UPDATE
+		name = VALUES(name),
+		price = VALUES(price),
+		category = VALUES(category),
+		image_id = VALUES(image_id),
+		updated_at = VALUES(updated_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, dto)
+	if err != nil {
+		return fmt.Errorf("failed to save menu item: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MenuItemRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*model.MenuItem, error) {
+	query := `
+		SELECT *
+		FROM menu_items
+		WHERE id = ?
+	`
+
+	var dto MenuItemDto
+	err := r.db.GetContext(ctx, &dto, query, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("menu item %w", repository.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to get menu item: %w", err)
+	}
+
+	item, err := dto.ToModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+	}
+
+	return item, nil
+}
+
+func (r *MenuItemRepositoryImpl) FindByShop(ctx context.Context, shopID uuid.UUID) ([]*model.MenuItem, error) {
+	query := `
+		SELECT *
+		FROM menu_items
+		WHERE shop_id = ?
+		ORDER BY category, name, id
+	`
+
+	var dtos []MenuItemDto
+	err := r.db.SelectContext(ctx, &dtos, query, shopID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get menu items: %w", err)
+	}
+
+	items := make([]*model.MenuItem, 0, len(dtos))
+	for _, dto := range dtos {
+		item, err := dto.ToModel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+		}
+		items = append(items, item)
+	}
+
+	return items, nil
+}
+
+func (r *MenuItemRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	// Delete dish ratings first (foreign key constraint)
+	_, err = tx.ExecContext(ctx, `DELETE FROM review_dishes WHERE menu_item_id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete dish ratings: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM menu_items WHERE id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete menu item: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("menu item %w", repository.ErrNotFound)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *MenuItemRepositoryImpl) FindRecommended(
+	ctx context.Context,
+	shopID uuid.UUID,
+	limit int,
+) ([]*model.RecommendedDish, error) {
+	query := `
+		SELECT m.*, AVG(d.rating) AS average, COUNT(*) AS count
+		FROM menu_items m
+		INNER JOIN review_dishes d ON d.menu_item_id = m.id
+		INNER JOIN reviews r ON r.id = d.review_id
+		WHERE m.shop_id = ? AND r.hidden = FALSE
+		GROUP BY m.id
+		ORDER BY (SUM(d.rating) + ? * ?) / (COUNT(*) + ?) DESC, count DESC, m.id
+		LIMIT ?
+	`
+
+	var rows []struct {
+		MenuItemDto
+		Average float64 `db:"average"`
+		Count   int     `db:"count"`
+	}
+	err := r.db.SelectContext(
+		ctx,
+		&rows,
+		query,
+		shopID.String(),
+		recommendPriorWeight,
+		recommendPriorRating,
+		recommendPriorWeight,
+		limit,
+	)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recommended dishes: %w", err)
+	}
+
+	dishes := make([]*model.RecommendedDish, 0, len(rows))
+	for _, row := range rows {
+		item, err := row.ToModel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+		}
+		dishes = append(dishes, &model.RecommendedDish{
+			Item:    item,
+			Average: row.Average,
+			Count:   row.Count,
+		})
+	}
+
+	return dishes, nil
+}
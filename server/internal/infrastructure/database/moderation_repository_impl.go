@@ -0,0 +1,289 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type ReviewReportDto struct {
+	ID         string       `db:"id"`
+	ReviewID   string       `db:"review_id"`
+	Reporter   string       `db:"reporter"`
+	Reason     string       `db:"reason"`
+	Note       string       `db:"note"`
+	CreatedAt  time.Time    `db:"created_at"`
+	ResolvedAt sql.NullTime `db:"resolved_at"`
+}
+
+func (dto *ReviewReportDto) ToModel() (*model.ReviewReport, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse review report UUID: %w", err)
+	}
+
+	reviewID, err := uuid.Parse(dto.ReviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse review UUID: %w", err)
+	}
+
+	reason, err := model.ParseReportReason(dto.Reason)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ReportReason: %w", err)
+	}
+
+	return &model.ReviewReport{
+		ID:         id,
+		Review:     reviewID,
+		Reporter:   model.UserID(dto.Reporter),
+		Reason:     reason,
+		Note:       dto.Note,
+		CreatedAt:  dto.CreatedAt,
+		ResolvedAt: dto.ResolvedAt.Time,
+	}, nil
+}
+
+func (dto *ReviewReportDto) FromModel(report *model.ReviewReport) {
+	dto.ID = report.ID.String()
+	dto.ReviewID = report.Review.String()
+	dto.Reporter = string(report.Reporter)
+	dto.Reason = string(report.Reason)
+	dto.Note = report.Note
+	dto.CreatedAt = report.CreatedAt
+	dto.ResolvedAt = sql.NullTime{Time: report.ResolvedAt, Valid: !report.ResolvedAt.IsZero()}
+}
+
+type ModerationLogDto struct {
+	ID        string    `db:"id"`
+	ReviewID  string    `db:"review_id"`
+	Author    string    `db:"author"`
+	Moderator string    `db:"moderator"`
+	Action    string    `db:"action"`
+	Note      string    `db:"note"`
+	CreatedAt time.Time `db:"created_at"`
+}
+
+func (dto *ModerationLogDto) ToModel() (*model.ModerationLog, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse moderation log UUID: %w", err)
+	}
+
+	reviewID, err := uuid.Parse(dto.ReviewID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse review UUID: %w", err)
+	}
+
+	action, err := model.ParseModerationAction(dto.Action)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ModerationAction: %w", err)
+	}
+
+	return &model.ModerationLog{
+		ID:        id,
+		Review:    reviewID,
+		Author:    model.UserID(dto.Author),
+		Moderator: model.UserID(dto.Moderator),
+		Action:    action,
+		Note:      dto.Note,
+		CreatedAt: dto.CreatedAt,
+	}, nil
+}
+
+func (dto *ModerationLogDto) FromModel(log *model.ModerationLog) {
+	dto.ID = log.ID.String()
+	dto.ReviewID = log.Review.String()
+	dto.Author = string(log.Author)
+	dto.Moderator = string(log.Moderator)
+	dto.Action = string(log.Action)
+	dto.Note = log.Note
+	dto.CreatedAt = log.CreatedAt
+}
+
+type ModerationRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewModerationRepository(db *sqlx.DB) repository.ModerationRepository {
+	return &ModerationRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *ModerationRepositoryImpl) SaveReport(ctx context.Context, report *model.ReviewReport) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	if err := saveReportTx(ctx, tx, report); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// saveReportTx stores report inside tx, replacing the open report of the
+// same user for the same review.
+func saveReportTx(ctx context.Context, tx *sqlx.Tx, report *model.ReviewReport) error {
+	dto := &ReviewReportDto{}
+	dto.FromModel(report)
+
+	_, err := tx.ExecContext(ctx, `
+DELETE FROM review_reports
+WHERE review_id = ? AND reporter = ? AND resolved_at IS NULL AND id <> ?
+`, dto.ReviewID, dto.Reporter, dto.ID)
+	if err != nil {
+		return fmt.Errorf("failed to delete previous review report: %w", err)
+	}
+
+	query := `
+INSERT INTO review_reports (id, review_id, reporter, reason, note, created_at, resolved_at)
+VALUES (:id, :review_id, :reporter, :reason, :note, :created_at, :resolved_at)
+ON DUPLICATE KEY UPDATE
+reason = VALUES(reason),
+note = VALUES(note),
+resolved_at = VALUES(resolved_at)
+`
+	_, err = tx.NamedExecContext(ctx, query, dto)
+	if err != nil {
+		return fmt.Errorf("failed to save review report: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ModerationRepositoryImpl) FindOpenReports(ctx context.Context) ([]*model.ReviewReport, error) {
+	query := `
+SELECT *
+FROM review_reports
+WHERE resolved_at IS NULL
+ORDER BY created_at, id
+`
+
+	var dtos []ReviewReportDto
+	if err := r.db.SelectContext(ctx, &dtos, query); err != nil {
+		return nil, fmt.Errorf("failed to get review reports: %w", err)
+	}
+
+	reports := make([]*model.ReviewReport, 0, len(dtos))
+	for _, dto := range dtos {
+		report, err := dto.ToModel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+		}
+		reports = append(reports, report)
+	}
+
+	return reports, nil
+}
+
+func (r *ModerationRepositoryImpl) Moderate(ctx context.Context, log *model.ModerationLog) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	reviewID := log.Review.String()
+
+	switch log.Action {
+	case model.ModerationHide, model.ModerationUnhide:
+		_, err = tx.ExecContext(ctx, `UPDATE reviews SET hidden = ? WHERE id = ?`, log.Action == model.ModerationHide, reviewID)
+		if err != nil {
+			return fmt.Errorf("failed to change review visibility: %w", err)
+		}
+	case model.ModerationDelete:
+		if err := deleteReviewTx(ctx, tx, log.Review); err != nil {
+			return err
+		}
+	case model.ModerationDismiss:
+	}
+
+	// Reports of a deleted review are removed by ON DELETE CASCADE
+	_, err = tx.ExecContext(ctx, `UPDATE review_reports SET resolved_at = ? WHERE review_id = ? AND resolved_at IS NULL`,
+		log.CreatedAt, reviewID)
+	if err != nil {
+		return fmt.Errorf("failed to resolve review reports: %w", err)
+	}
+
+	dto := &ModerationLogDto{}
+	dto.FromModel(log)
+	query := `
+INSERT INTO moderation_logs (id, review_id, author, moderator, action, note, created_at)
+VALUES (:id, :review_id, :author, :moderator, :action, :note, :created_at)
+`
+	if _, err := tx.NamedExecContext(ctx, query, dto); err != nil {
+		return fmt.Errorf("failed to save moderation log: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *ModerationRepositoryImpl) FindLogs(
+	ctx context.Context,
+	reviewID uuid.UUID,
+	limit int,
+	offset int,
+) ([]*model.ModerationLog, error) {
+	var dtos []ModerationLogDto
+	var err error
+	if reviewID == uuid.Nil {
+		err = r.db.SelectContext(ctx, &dtos, `
+SELECT *
+FROM moderation_logs
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`, limit, offset)
+	} else {
+		err = r.db.SelectContext(ctx, &dtos, `
+SELECT *
+FROM moderation_logs
+WHERE review_id = ?
+ORDER BY created_at DESC, id DESC
+LIMIT ? OFFSET ?
+`, reviewID.String(), limit, offset)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get moderation logs: %w", err)
+	}
+
+	logs := make([]*model.ModerationLog, 0, len(dtos))
+	for _, dto := range dtos {
+		log, err := dto.ToModel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+		}
+		logs = append(logs, log)
+	}
+
+	return logs, nil
+}
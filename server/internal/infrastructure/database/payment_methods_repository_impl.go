@@ -0,0 +1,68 @@
+package database
+
+import (
+	"context"
+	"fmt"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/jmoiron/sqlx"
+)
+
+type PaymentMethodDto struct {
+	Code      string `db:"code"`
+	Name      string `db:"name"`
+	Category  string `db:"category"`
+	SortOrder int    `db:"sort_order"`
+}
+
+type PaymentMethodAliasDto struct {
+	Alias string `db:"alias"`
+	Code  string `db:"code"`
+}
+
+func (dto *PaymentMethodDto) ToModel(aliases []string) *model.PaymentMethod {
+	return &model.PaymentMethod{
+		Code:     dto.Code,
+		Name:     dto.Name,
+		Category: model.PaymentMethodCategory(dto.Category),
+		Aliases:  aliases,
+	}
+}
+
+type PaymentMethodRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewPaymentMethodRepository(db *sqlx.DB) repository.PaymentMethodRepository {
+	return &PaymentMethodRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *PaymentMethodRepositoryImpl) FindCatalog(ctx context.Context) (*model.PaymentMethodCatalog, error) {
+	var dtos []PaymentMethodDto
+	err := r.db.SelectContext(ctx, &dtos, `SELECT * FROM payment_methods ORDER BY sort_order, code`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment methods: %w", err)
+	}
+
+	var aliasDtos []PaymentMethodAliasDto
+	err = r.db.SelectContext(ctx, &aliasDtos, `SELECT * FROM payment_method_aliases ORDER BY alias`)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get payment method aliases: %w", err)
+	}
+
+	aliases := make(map[string][]string)
+	for _, dto := range aliasDtos {
+		aliases[dto.Code] = append(aliases[dto.Code], dto.Alias)
+	}
+
+	methods := make([]*model.PaymentMethod, 0, len(dtos))
+	for _, dto := range dtos {
+		methods = append(methods, dto.ToModel(aliases[dto.Code]))
+	}
+
+	return model.NewPaymentMethodCatalog(methods), nil
+}
@@ -10,6 +10,7 @@ import (
 
 	"backend/internal/domain/model"
 	"backend/internal/domain/repository"
+	"backend/internal/logging"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -21,6 +22,7 @@ type ReviewDto struct {
 	ShopID    string    `db:"shop_id"`
 	Rating    int       `db:"rating"`
 	Content   string    `db:"content"`
+	Hidden    bool      `db:"hidden"`
 	CreatedAt time.Time `db:"created_at"`
 	UpdatedAt time.Time `db:"updated_at"`
 }
@@ -30,6 +32,11 @@ type ReviewImageDto struct {
 	ImageID  string `db:"image_id"`
 }
 
+type ReviewDishDto struct {
+	MenuItemID string `db:"menu_item_id"`
+	Rating     int    `db:"rating"`
+}
+
 func (dto *ReviewDto) ToModel(images []model.ImageFile) (*model.Review, error) {
 	id, err := uuid.Parse(dto.ID)
 	if err != nil {
@@ -58,6 +65,7 @@ func (dto *ReviewDto) ToModel(images []model.ImageFile) (*model.Review, error) {
 		Rating:    rating,
 		Content:   dto.Content,
 		Images:    images,
+		Hidden:    dto.Hidden,
 		CreatedAt: dto.CreatedAt,
 		UpdatedAt: dto.UpdatedAt,
 	}, nil
@@ -69,17 +77,20 @@ func (dto *ReviewDto) FromModel(review *model.Review) {
 	dto.ShopID = review.Shop.String()
 	dto.Rating = int(review.Rating)
 	dto.Content = review.Content
+	dto.Hidden = review.Hidden
 	dto.CreatedAt = review.CreatedAt
 	dto.UpdatedAt = review.UpdatedAt
 }
 
 type ReviewRepositoryImpl struct {
-	db *sqlx.DB
+	db        *sqlx.DB
+	publisher repository.EventPublisher
 }
 
-func NewReviewRepository(db *sqlx.DB) repository.ReviewRepository {
+func NewReviewRepository(db *sqlx.DB, publisher repository.EventPublisher) repository.ReviewRepository {
 	return &ReviewRepositoryImpl{
-		db: db,
+		db:        db,
+		publisher: publisher,
 	}
 }
 
@@ -91,35 +102,97 @@ func (r *ReviewRepositoryImpl) Save(ctx context.Context, review *model.Review) e
 	defer func() {
 		err := tx.Rollback()
 		if err != nil && !errors.Is(err, sql.ErrTxDone) {
-			fmt.Printf("failed to rollback transaction: %v\n", err)
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
 		}
 	}()
 
+	events, err := r.saveTx(ctx, tx, review)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.publisher.Publish(events...)
+
+	return nil
+}
+
+func (r *ReviewRepositoryImpl) SaveHeld(ctx context.Context, review *model.Review, report *model.ReviewReport) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	review.Hidden = true
+	// Hidden reviews record no events, so there is nothing to publish
+	if _, err := r.saveTx(ctx, tx, review); err != nil {
+		return err
+	}
+
+	if err := saveReportTx(ctx, tx, report); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// saveTx upserts the review and replaces its images and dish ratings inside
+// tx, recording the corresponding events in the outbox. Hidden reviews must
+// not reach the feed or the stream, so no events are recorded for them. The
+// events are returned so the caller can publish them once tx is committed.
+func (r *ReviewRepositoryImpl) saveTx(ctx context.Context, tx *sqlx.Tx, review *model.Review) ([]*model.Event, error) {
 	// Save review
 	dto := &ReviewDto{}
 	dto.FromModel(review)
 
 	query := `
-		INSERT INTO reviews (id, author, shop_id, rating, content, created_at, updated_at)
-		VALUES (:id, :author, :shop_id, :rating, :content, :created_at, :updated_at)
+		INSERT INTO reviews (id, author, shop_id, rating, content, hidden, created_at, updated_at)
+		VALUES (:id, :author, :shop_id, :rating, :content, :hidden, :created_at, :updated_at)
 		ON DUPLICATE KEY UPDATE
 			author = VALUES(author),
 			shop_id = VALUES(shop_id),
 			rating = VALUES(rating),
 			content = VALUES(content),
-			updated_at = VALUES(updated_at)
+			updated_at = VALUES(updated_at),
+			hidden = hidden OR VALUES(hidden)
 	`
 
-	_, err = tx.NamedExecContext(ctx, query, dto)
+	result, err := tx.NamedExecContext(ctx, query, dto)
 	if err != nil {
-		return fmt.Errorf("failed to save review: %w", err)
+		return nil, fmt.Errorf("failed to save review: %w", err)
+	}
+
+	// A moderator may have hidden the review since it was loaded
+	if err := tx.GetContext(ctx, &review.Hidden, `SELECT hidden FROM reviews WHERE id = ?`, review.ID.String()); err != nil {
+		return nil, fmt.Errorf("failed to get review visibility: %w", err)
+	}
+
+	// Collect existing images to detect newly added ones
+	var existingImageIDs []string
+	existingImagesQuery := `SELECT image_id FROM review_images WHERE review_id = ?`
+	err = tx.SelectContext(ctx, &existingImageIDs, existingImagesQuery, review.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing review images: %w", err)
 	}
 
 	// Delete existing review images
 	deleteImagesQuery := `DELETE FROM review_images WHERE review_id = ?`
 	_, err = tx.ExecContext(ctx, deleteImagesQuery, review.ID.String())
 	if err != nil {
-		return fmt.Errorf("failed to delete existing review images: %w", err)
+		return nil, fmt.Errorf("failed to delete existing review images: %w", err)
 	}
 
 	// Save review images
@@ -128,16 +201,83 @@ func (r *ReviewRepositoryImpl) Save(ctx context.Context, review *model.Review) e
 		for _, image := range review.Images {
 			_, err = tx.ExecContext(ctx, imageQuery, review.ID.String(), image.ID.String())
 			if err != nil {
-				return fmt.Errorf("failed to save review image: %w", err)
+				return nil, fmt.Errorf("failed to save review image: %w", err)
 			}
 		}
 	}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	// Replace dish ratings
+	_, err = tx.ExecContext(ctx, `DELETE FROM review_dishes WHERE review_id = ?`, review.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete existing review dishes: %w", err)
 	}
 
-	return nil
+	dishQuery := `INSERT INTO review_dishes (review_id, menu_item_id, rating) VALUES (?, ?, ?)`
+	for _, dish := range review.Dishes {
+		_, err = tx.ExecContext(ctx, dishQuery, review.ID.String(), dish.MenuItem.String(), int(dish.Rating))
+		if err != nil {
+			return nil, fmt.Errorf("failed to save review dish: %w", err)
+		}
+	}
+
+	if review.Hidden {
+		return nil, nil
+	}
+
+	actor := eventActor(ctx, review.Author)
+	var events []*model.Event
+
+	// Stations of the shop, so subscribers can filter review events by station
+	var stationIDs []string
+	stationsQuery := `SELECT station_id FROM shop_stations WHERE shop_id = ?`
+	if err := tx.SelectContext(ctx, &stationIDs, stationsQuery, review.Shop.String()); err != nil {
+		return nil, fmt.Errorf("failed to get shop stations: %w", err)
+	}
+	stations := make([]uuid.UUID, 0, len(stationIDs))
+	for _, id := range stationIDs {
+		stationID, err := uuid.Parse(id)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse station ID: %w", err)
+		}
+		stations = append(stations, stationID)
+	}
+
+	eventType, changed, err := upsertEventType(result, model.EventReviewCreated, model.EventReviewUpdated)
+	if err != nil {
+		return nil, err
+	}
+	if changed {
+		event, err := model.NewEvent(eventType, actor, map[string]any{
+			"rating":   int(review.Rating),
+			"content":  review.Content,
+			"stations": stations,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create event: %w", err)
+		}
+		event.Shop = review.Shop
+		event.Review = review.ID
+		events = append(events, event)
+	}
+
+	for _, image := range addedImages(existingImageIDs, review.Images) {
+		event, err := model.NewEvent(model.EventImageAdded, actor, map[string]any{
+			"stations": stations,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create event: %w", err)
+		}
+		event.Shop = review.Shop
+		event.Review = review.ID
+		event.Image = image.ID
+		events = append(events, event)
+	}
+
+	if err := insertEvents(ctx, tx, events...); err != nil {
+		return nil, err
+	}
+
+	return events, nil
 }
 
 func (r *ReviewRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*model.Review, error) {
@@ -151,7 +291,7 @@ func (r *ReviewRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*mod
 	err := r.db.GetContext(ctx, &dto, query, id.String())
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("review not found")
+			return nil, fmt.Errorf("review %w", repository.ErrNotFound)
 		}
 
 		return nil, fmt.Errorf("failed to get review: %w", err)
@@ -168,39 +308,27 @@ func (r *ReviewRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*mod
 		return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
 	}
 
+	review.Dishes, err = r.getReviewDishes(ctx, id)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review dishes: %w", err)
+	}
+
 	return review, nil
 }
 
 func (r *ReviewRepositoryImpl) FindRecentReviews(
 	ctx context.Context,
-	after time.Time,
-	before time.Time,
+	filter repository.ReviewFilter,
+	cursor *model.ReviewCursor,
 	limit int,
 	offset int,
-	shopID uuid.UUID,
-	authorID model.UserID,
 ) ([]*model.Review, error) {
-	conditions := []string{}
-	args := []interface{}{}
+	conditions, args := reviewFilterConditions(filter)
 
-	if !after.IsZero() {
-		conditions = append(conditions, "created_at >= ?")
-		args = append(args, after)
-	}
-
-	if !before.IsZero() {
-		conditions = append(conditions, "created_at <= ?")
-		args = append(args, before)
-	}
-
-	if shopID != uuid.Nil {
-		conditions = append(conditions, "shop_id = ?")
-		args = append(args, shopID.String())
-	}
-
-	if authorID != "" {
-		conditions = append(conditions, "author = ?")
-		args = append(args, string(authorID))
+	if cursor != nil {
+		conditions = append(conditions, "(created_at < ? OR (created_at = ? AND id < ?))")
+		args = append(args, cursor.CreatedAt, cursor.CreatedAt, cursor.ID.String())
+		offset = 0
 	}
 
 	whereClause := ""
@@ -212,7 +340,7 @@ func (r *ReviewRepositoryImpl) FindRecentReviews(
 		SELECT *
 		FROM reviews
 		%s
-		ORDER BY created_at DESC
+		ORDER BY created_at DESC, id DESC
 		LIMIT ? OFFSET ?
 	`, whereClause)
 
@@ -241,12 +369,119 @@ func (r *ReviewRepositoryImpl) FindRecentReviews(
 		if err != nil {
 			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
 		}
+
+		review.Dishes, err = r.getReviewDishes(ctx, reviewID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get review dishes: %w", err)
+		}
 		reviews = append(reviews, review)
 	}
 
 	return reviews, nil
 }
 
+func (r *ReviewRepositoryImpl) Count(ctx context.Context, filter repository.ReviewFilter) (int, error) {
+	conditions, args := reviewFilterConditions(filter)
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := fmt.Sprintf(`
+		SELECT COUNT(*)
+		FROM reviews
+		%s
+	`, whereClause)
+
+	var count int
+	if err := r.db.GetContext(ctx, &count, query, args...); err != nil {
+		return 0, fmt.Errorf("failed to count reviews: %w", err)
+	}
+
+	return count, nil
+}
+
+func (r *ReviewRepositoryImpl) SummarizeRatings(
+	ctx context.Context,
+	shopIDs []uuid.UUID,
+) (map[uuid.UUID]*model.RatingSummary, error) {
+	summaries := make(map[uuid.UUID]*model.RatingSummary, len(shopIDs))
+	if len(shopIDs) == 0 {
+		return summaries, nil
+	}
+
+	ids := make([]string, len(shopIDs))
+	for i, id := range shopIDs {
+		ids[i] = id.String()
+	}
+
+	query, args, err := sqlx.In(`
+		SELECT shop_id, AVG(rating) AS average, COUNT(*) AS count
+		FROM reviews
+		WHERE shop_id IN (?) AND hidden = FALSE
+		GROUP BY shop_id
+	`, ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	var rows []struct {
+		ShopID  string  `db:"shop_id"`
+		Average float64 `db:"average"`
+		Count   int     `db:"count"`
+	}
+	if err := r.db.SelectContext(ctx, &rows, r.db.Rebind(query), args...); err != nil {
+		return nil, fmt.Errorf("failed to summarize ratings: %w", err)
+	}
+
+	for _, row := range rows {
+		shopID, err := uuid.Parse(row.ShopID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse shop ID: %w", err)
+		}
+		summaries[shopID] = &model.RatingSummary{
+			Shop:    shopID,
+			Average: row.Average,
+			Count:   row.Count,
+		}
+	}
+
+	return summaries, nil
+}
+
+func reviewFilterConditions(filter repository.ReviewFilter) ([]string, []interface{}) {
+	conditions := []string{}
+	args := []interface{}{}
+
+	if !filter.After.IsZero() {
+		conditions = append(conditions, "created_at >= ?")
+		args = append(args, filter.After)
+	}
+
+	if !filter.Before.IsZero() {
+		conditions = append(conditions, "created_at <= ?")
+		args = append(args, filter.Before)
+	}
+
+	if filter.ShopID != uuid.Nil {
+		conditions = append(conditions, "shop_id = ?")
+		args = append(args, filter.ShopID.String())
+	}
+
+	if filter.AuthorID != "" {
+		conditions = append(conditions, "author = ?")
+		args = append(args, string(filter.AuthorID))
+	}
+
+	if !filter.IncludeHidden {
+		conditions = append(conditions, "(hidden = FALSE OR author = ?)")
+		args = append(args, string(filter.Viewer))
+	}
+
+	return conditions, args
+}
+
 func (r *ReviewRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	tx, err := r.db.BeginTxx(ctx, nil)
 	if err != nil {
@@ -255,17 +490,35 @@ func (r *ReviewRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	defer func() {
 		err := tx.Rollback()
 		if err != nil && !errors.Is(err, sql.ErrTxDone) {
-			fmt.Printf("failed to rollback transaction: %v\n", err)
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
 		}
 	}()
 
+	if err := deleteReviewTx(ctx, tx, id); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// deleteReviewTx deletes the review and its images and dish ratings inside tx.
+func deleteReviewTx(ctx context.Context, tx *sqlx.Tx, id uuid.UUID) error {
 	// Delete review images first (foreign key constraint)
 	deleteImagesQuery := `DELETE FROM review_images WHERE review_id = ?`
-	_, err = tx.ExecContext(ctx, deleteImagesQuery, id.String())
+	_, err := tx.ExecContext(ctx, deleteImagesQuery, id.String())
 	if err != nil {
 		return fmt.Errorf("failed to delete review images: %w", err)
 	}
 
+	_, err = tx.ExecContext(ctx, `DELETE FROM review_dishes WHERE review_id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete review dishes: %w", err)
+	}
+
 	// Delete review
 	deleteReviewQuery := `DELETE FROM reviews WHERE id = ?`
 	result, err := tx.ExecContext(ctx, deleteReviewQuery, id.String())
@@ -279,11 +532,7 @@ func (r *ReviewRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	}
 
 	if rowsAffected == 0 {
-		return fmt.Errorf("review not found")
-	}
-
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+		return fmt.Errorf("review %w", repository.ErrNotFound)
 	}
 
 	return nil
@@ -313,3 +562,83 @@ func (r *ReviewRepositoryImpl) getReviewImages(ctx context.Context, reviewID uui
 
 	return images, nil
 }
+
+func (r *ReviewRepositoryImpl) getReviewDishes(ctx context.Context, reviewID uuid.UUID) ([]model.DishRating, error) {
+	query := `
+		SELECT menu_item_id, rating
+		FROM review_dishes
+		WHERE review_id = ?
+		ORDER BY created_at, menu_item_id
+	`
+
+	var dtos []ReviewDishDto
+	err := r.db.SelectContext(ctx, &dtos, query, reviewID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get review dish ratings: %w", err)
+	}
+
+	dishes := make([]model.DishRating, 0, len(dtos))
+	for _, dto := range dtos {
+		itemID, err := uuid.Parse(dto.MenuItemID)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse menu item ID: %w", err)
+		}
+		rating, err := model.NewRating(dto.Rating)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Rating: %w", err)
+		}
+		dishes = append(dishes, model.DishRating{MenuItem: itemID, Rating: rating})
+	}
+
+	return dishes, nil
+}
+
+func (r *ReviewRepositoryImpl) Iterate(ctx context.Context, fn func(*model.Review) error) error {
+	query := `
+		SELECT *
+		FROM reviews
+		WHERE id > ? AND hidden = FALSE
+		ORDER BY id
+		LIMIT ?
+	`
+
+	lastID := ""
+	for {
+		var dtos []ReviewDto
+		err := r.db.SelectContext(ctx, &dtos, query, lastID, iterateBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get reviews: %w", err)
+		}
+
+		for _, dto := range dtos {
+			reviewID, err := uuid.Parse(dto.ID)
+			if err != nil {
+				return fmt.Errorf("failed to parse review ID: %w", err)
+			}
+
+			images, err := r.getReviewImages(ctx, reviewID)
+			if err != nil {
+				return fmt.Errorf("failed to get review images: %w", err)
+			}
+
+			review, err := dto.ToModel(images)
+			if err != nil {
+				return fmt.Errorf("failed to convert DTO to model: %w", err)
+			}
+
+			review.Dishes, err = r.getReviewDishes(ctx, reviewID)
+			if err != nil {
+				return fmt.Errorf("failed to get review dishes: %w", err)
+			}
+
+			if err := fn(review); err != nil {
+				return err
+			}
+		}
+
+		if len(dtos) < iterateBatchSize {
+			return nil
+		}
+		lastID = dtos[len(dtos)-1].ID
+	}
+}
@@ -0,0 +1,146 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/google/uuid"
+)
+
+func (r *ShopRepositoryImpl) Merge(ctx context.Context, merge *model.ShopMerge, target *model.Shop) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	sourceID, targetID := merge.Source.ID.String(), target.ID.String()
+
+	// Move the images first so that saveTx does not report them as newly added
+	_, err = tx.ExecContext(ctx, `
+INSERT IGNORE INTO shop_images (shop_id, image_id, created_at)
+SELECT ?, image_id, created_at FROM shop_images WHERE shop_id = ?
+`, targetID, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to move shop images: %w", err)
+	}
+
+	events, err := r.saveTx(ctx, tx, target)
+	if err != nil {
+		return err
+	}
+
+	moves := []struct {
+		name  string
+		query string
+	}{
+		{"reviews", `UPDATE reviews SET shop_id = ? WHERE shop_id = ?`},
+		{"menu items", `UPDATE menu_items SET shop_id = ? WHERE shop_id = ?`},
+		{"favorites", `
+INSERT IGNORE INTO favorite_shops (user_id, shop_id, created_at)
+SELECT user_id, ?, created_at FROM favorite_shops WHERE shop_id = ?
+`},
+		{"events", `UPDATE events SET shop_id = ? WHERE shop_id = ?`},
+		{"successor shops", `UPDATE shops SET moved_to = ? WHERE moved_to = ?`},
+		{"redirects", `UPDATE shop_redirects SET to_shop_id = ? WHERE to_shop_id = ?`},
+	}
+	for _, move := range moves {
+		if _, err := tx.ExecContext(ctx, move.query, targetID, sourceID); err != nil {
+			return fmt.Errorf("failed to move %s: %w", move.name, err)
+		}
+	}
+
+	// The remaining links and pending closure reports are removed by ON DELETE CASCADE
+	result, err := tx.ExecContext(ctx, `DELETE FROM shops WHERE id = ?`, sourceID)
+	if err != nil {
+		return fmt.Errorf("failed to delete merged shop: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("shop %w", repository.ErrNotFound)
+	}
+
+	_, err = tx.ExecContext(ctx, `INSERT INTO shop_redirects (from_shop_id, to_shop_id, created_at) VALUES (?, ?, ?)`,
+		sourceID, targetID, merge.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save shop redirect: %w", err)
+	}
+
+	snapshot, err := json.Marshal(shopSnapshot(merge.Source))
+	if err != nil {
+		return fmt.Errorf("failed to marshal merged shop: %w", err)
+	}
+	_, err = tx.ExecContext(ctx, `
+INSERT INTO shop_merges (id, source_shop_id, target_shop_id, actor, source_snapshot, created_at)
+VALUES (?, ?, ?, ?, ?, ?)
+`, merge.ID.String(), sourceID, targetID, string(merge.Actor), snapshot, merge.CreatedAt)
+	if err != nil {
+		return fmt.Errorf("failed to save shop merge: %w", err)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.publisher.Publish(events...)
+
+	return nil
+}
+
+func (r *ShopRepositoryImpl) FindRedirect(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	var to string
+	err := r.db.GetContext(ctx, &to, `SELECT to_shop_id FROM shop_redirects WHERE from_shop_id = ?`, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return uuid.Nil, fmt.Errorf("shop redirect %w", repository.ErrNotFound)
+		}
+
+		return uuid.Nil, fmt.Errorf("failed to get shop redirect: %w", err)
+	}
+
+	toID, err := uuid.Parse(to)
+	if err != nil {
+		return uuid.Nil, fmt.Errorf("failed to parse shop UUID: %w", err)
+	}
+
+	return toID, nil
+}
+
+// shopSnapshot is what the audit record keeps of a merged shop.
+func shopSnapshot(shop *model.Shop) map[string]any {
+	images := make([]uuid.UUID, len(shop.Images))
+	for i, image := range shop.Images {
+		images[i] = image.ID
+	}
+
+	return map[string]any{
+		"id":              shop.ID,
+		"name":            shop.Name,
+		"post_code":       shop.PostCode,
+		"address":         shop.Address,
+		"latitude":        shop.Latitude,
+		"longitude":       shop.Longitude,
+		"registerer":      shop.Registerer,
+		"stations":        shop.Stations,
+		"payment_methods": shop.PaymentMethods,
+		"tags":            shop.Tags,
+		"images":          images,
+		"status":          shop.Status,
+		"created_at":      shop.CreatedAt,
+	}
+}
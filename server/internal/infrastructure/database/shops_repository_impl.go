@@ -9,21 +9,24 @@ import (
 
 	"backend/internal/domain/model"
 	"backend/internal/domain/repository"
+	"backend/internal/logging"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
 )
 
 type ShopDto struct {
-	ID         string    `db:"id"`
-	Name       string    `db:"name"`
-	PostCode   string    `db:"post_code"`
-	Address    string    `db:"address"`
-	Latitude   string    `db:"latitude"`
-	Longitude  string    `db:"longitude"`
-	Registerer string    `db:"registerer"`
-	CreatedAt  time.Time `db:"created_at"`
-	UpdatedAt  time.Time `db:"updated_at"`
+	ID         string         `db:"id"`
+	Name       string         `db:"name"`
+	PostCode   string         `db:"post_code"`
+	Address    string         `db:"address"`
+	Latitude   string         `db:"latitude"`
+	Longitude  string         `db:"longitude"`
+	Registerer string         `db:"registerer"`
+	Status     string         `db:"status"`
+	MovedTo    sql.NullString `db:"moved_to"`
+	CreatedAt  time.Time      `db:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at"`
 }
 
 type ShopStationDto struct {
@@ -41,6 +44,20 @@ type ShopImageDto struct {
 	ImageID string `db:"image_id"`
 }
 
+type ShopBusinessHourDto struct {
+	ShopID      string `db:"shop_id"`
+	Weekday     int    `db:"weekday"`
+	OpenMinute  int    `db:"open_minute"`
+	CloseMinute int    `db:"close_minute"`
+}
+
+type ShopClosureDto struct {
+	ShopID string `db:"shop_id"`
+	// DATE型はタイムゾーンの変換を避けるため YYYY-MM-DD の文字列で扱う
+	Date string `db:"date"`
+	Note string `db:"note"`
+}
+
 func (dto *ShopDto) ToModel(stations []uuid.UUID, images []model.ImageFile, paymentMethods []string) (*model.Shop, error) {
 	id, err := uuid.Parse(dto.ID)
 	if err != nil {
@@ -80,6 +97,19 @@ func (dto *ShopDto) ToModel(stations []uuid.UUID, images []model.ImageFile, paym
 		}
 	}
 
+	status, err := model.ParseShopStatus(dto.Status)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ShopStatus: %w", err)
+	}
+
+	movedTo := uuid.Nil
+	if dto.MovedTo.Valid {
+		movedTo, err = uuid.Parse(dto.MovedTo.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse successor shop UUID: %w", err)
+		}
+	}
+
 	return &model.Shop{
 		ID:             id,
 		Name:           shopName,
@@ -91,6 +121,8 @@ func (dto *ShopDto) ToModel(stations []uuid.UUID, images []model.ImageFile, paym
 		Images:         images,
 		PaymentMethods: paymentMethods,
 		Registerer:     registerer,
+		Status:         status,
+		MovedTo:        movedTo,
 		CreatedAt:      dto.CreatedAt,
 		UpdatedAt:      dto.UpdatedAt,
 	}, nil
@@ -104,17 +136,24 @@ func (dto *ShopDto) FromModel(shop *model.Shop) {
 	dto.Latitude = fmt.Sprintf("%f", shop.Latitude)
 	dto.Longitude = fmt.Sprintf("%f", shop.Longitude)
 	dto.Registerer = string(shop.Registerer)
+	dto.Status = string(shop.Status)
+	dto.MovedTo = sql.NullString{}
+	if shop.MovedTo != uuid.Nil {
+		dto.MovedTo = sql.NullString{String: shop.MovedTo.String(), Valid: true}
+	}
 	dto.CreatedAt = shop.CreatedAt
 	dto.UpdatedAt = shop.UpdatedAt
 }
 
 type ShopRepositoryImpl struct {
-	db *sqlx.DB
+	db        *sqlx.DB
+	publisher repository.EventPublisher
 }
 
-func NewShopRepository(db *sqlx.DB) repository.ShopRepository {
+func NewShopRepository(db *sqlx.DB, publisher repository.EventPublisher) repository.ShopRepository {
 	return &ShopRepositoryImpl{
-		db: db,
+		db:        db,
+		publisher: publisher,
 	}
 }
 
@@ -126,17 +165,95 @@ func (r *ShopRepositoryImpl) Save(ctx context.Context, shop *model.Shop) error {
 	defer func() {
 		err := tx.Rollback()
 		if err != nil && !errors.Is(err, sql.ErrTxDone) {
-			fmt.Printf("failed to rollback transaction: %v\n", err)
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	events, err := r.saveTx(ctx, tx, shop)
+	if err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.publisher.Publish(events...)
+
+	return nil
+}
+
+func (r *ShopRepositoryImpl) UpdateStatus(ctx context.Context, shop *model.Shop) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
 		}
 	}()
 
+	dto := &ShopDto{}
+	dto.FromModel(shop)
+
+	// Only the status columns, so that concurrent edits of other fields survive
+	result, err := tx.ExecContext(ctx, `
+UPDATE shops
+SET status = ?, moved_to = ?, updated_at = ?
+WHERE id = ?
+`, dto.Status, dto.MovedTo, dto.UpdatedAt, dto.ID)
+	if err != nil {
+		return fmt.Errorf("failed to update shop status: %w", err)
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("shop %w", repository.ErrNotFound)
+	}
+
+	if err := resolveClosureReportsTx(ctx, tx, shop.ID, shop.UpdatedAt); err != nil {
+		return err
+	}
+
+	event, err := model.NewEvent(model.EventShopUpdated, eventActor(ctx, shop.Registerer), map[string]any{
+		"name":     string(shop.Name),
+		"address":  shop.Address,
+		"stations": shop.Stations,
+		"status":   string(shop.Status),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to create event: %w", err)
+	}
+	event.Shop = shop.ID
+
+	if err := insertEvents(ctx, tx, event); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.publisher.Publish(event)
+
+	return nil
+}
+
+// saveTx upserts the shop and replaces its link tables inside tx, recording
+// the corresponding events in the outbox. The events are returned so the
+// caller can publish them once tx is committed.
+func (r *ShopRepositoryImpl) saveTx(ctx context.Context, tx *sqlx.Tx, shop *model.Shop) ([]*model.Event, error) {
 	// Save shop
 	dto := &ShopDto{}
 	dto.FromModel(shop)
 
 	query := `
-INSERT INTO shops (id, name, post_code, address, latitude, longitude, registerer, created_at, updated_at)
-VALUES (:id, :name, :post_code, :address, :latitude, :longitude, :registerer, :created_at, :updated_at)
+INSERT INTO shops (id, name, post_code, address, latitude, longitude, registerer, status, moved_to, created_at, updated_at)
+VALUES (:id, :name, :post_code, :address, :latitude, :longitude, :registerer, :status, :moved_to, :created_at, :updated_at)
 ON DUPLICATE KEY UPDATE
 name = VALUES(name),
 post_code = VALUES(post_code),
@@ -144,19 +261,41 @@ address = VALUES(address),
 latitude = VALUES(latitude),
 longitude = VALUES(longitude),
 registerer = VALUES(registerer),
+status = VALUES(status),
+moved_to = VALUES(moved_to),
 updated_at = VALUES(updated_at)
 `
 
-	_, err = tx.NamedExecContext(ctx, query, dto)
+	result, err := tx.NamedExecContext(ctx, query, dto)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save shop: %w", err)
+	}
+
+	actor := eventActor(ctx, shop.Registerer)
+	var events []*model.Event
+
+	eventType, changed, err := upsertEventType(result, model.EventShopCreated, model.EventShopUpdated)
 	if err != nil {
-		return fmt.Errorf("failed to save shop: %w", err)
+		return nil, err
+	}
+	if changed {
+		event, err := model.NewEvent(eventType, actor, map[string]any{
+			"name":     string(shop.Name),
+			"address":  shop.Address,
+			"stations": shop.Stations,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create event: %w", err)
+		}
+		event.Shop = shop.ID
+		events = append(events, event)
 	}
 
 	// Delete existing shop stations
 	deleteStationsQuery := `DELETE FROM shop_stations WHERE shop_id = ?`
 	_, err = tx.ExecContext(ctx, deleteStationsQuery, shop.ID.String())
 	if err != nil {
-		return fmt.Errorf("failed to delete existing shop stations: %w", err)
+		return nil, fmt.Errorf("failed to delete existing shop stations: %w", err)
 	}
 
 	// Save shop stations
@@ -165,7 +304,7 @@ updated_at = VALUES(updated_at)
 		for _, stationID := range shop.Stations {
 			_, err = tx.ExecContext(ctx, stationQuery, shop.ID.String(), stationID.String())
 			if err != nil {
-				return fmt.Errorf("failed to save shop station: %w", err)
+				return nil, fmt.Errorf("failed to save shop station: %w", err)
 			}
 		}
 	}
@@ -174,7 +313,7 @@ updated_at = VALUES(updated_at)
 	deletePaymentMethodsQuery := `DELETE FROM shop_payment_methods WHERE shop_id = ?`
 	_, err = tx.ExecContext(ctx, deletePaymentMethodsQuery, shop.ID.String())
 	if err != nil {
-		return fmt.Errorf("failed to delete existing shop payment methods: %w", err)
+		return nil, fmt.Errorf("failed to delete existing shop payment methods: %w", err)
 	}
 
 	// Save shop payment methods
@@ -183,16 +322,71 @@ updated_at = VALUES(updated_at)
 		for _, paymentMethod := range shop.PaymentMethods {
 			_, err = tx.ExecContext(ctx, paymentMethodQuery, shop.ID.String(), paymentMethod)
 			if err != nil {
-				return fmt.Errorf("failed to save shop payment method: %w", err)
+				return nil, fmt.Errorf("failed to save shop payment method: %w", err)
+			}
+		}
+	}
+
+	// Replace shop tags
+	_, err = tx.ExecContext(ctx, `DELETE FROM shop_tags WHERE shop_id = ?`, shop.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete existing shop tags: %w", err)
+	}
+
+	tagQuery := `INSERT INTO shop_tags (shop_id, tag_id) VALUES (?, ?)`
+	for _, tagID := range shop.Tags {
+		_, err = tx.ExecContext(ctx, tagQuery, shop.ID.String(), tagID.String())
+		if err != nil {
+			return nil, fmt.Errorf("failed to save shop tag: %w", err)
+		}
+	}
+
+	// Replace shop business hours and closures
+	_, err = tx.ExecContext(ctx, `DELETE FROM shop_business_hours WHERE shop_id = ?`, shop.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete existing shop business hours: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM shop_closures WHERE shop_id = ?`, shop.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to delete existing shop closures: %w", err)
+	}
+
+	if shop.BusinessHours != nil {
+		hourQuery := `
+INSERT INTO shop_business_hours (shop_id, weekday, open_minute, close_minute)
+VALUES (?, ?, ?, ?)
+`
+		for _, timeRange := range shop.BusinessHours.Ranges {
+			_, err = tx.ExecContext(ctx, hourQuery, shop.ID.String(), int(timeRange.Weekday), timeRange.Start, timeRange.End)
+			if err != nil {
+				return nil, fmt.Errorf("failed to save shop business hours: %w", err)
+			}
+		}
+
+		closureQuery := `INSERT INTO shop_closures (shop_id, date, note) VALUES (?, ?, ?)`
+		for _, closure := range shop.BusinessHours.Closures {
+			date := closure.Date.In(model.Tokyo).Format(time.DateOnly)
+			_, err = tx.ExecContext(ctx, closureQuery, shop.ID.String(), date, closure.Note)
+			if err != nil {
+				return nil, fmt.Errorf("failed to save shop closure: %w", err)
 			}
 		}
 	}
 
+	// Collect existing images to detect newly added ones
+	var existingImageIDs []string
+	existingImagesQuery := `SELECT image_id FROM shop_images WHERE shop_id = ?`
+	err = tx.SelectContext(ctx, &existingImageIDs, existingImagesQuery, shop.ID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get existing shop images: %w", err)
+	}
+
 	// Delete existing shop images
 	deleteImagesQuery := `DELETE FROM shop_images WHERE shop_id = ?`
 	_, err = tx.ExecContext(ctx, deleteImagesQuery, shop.ID.String())
 	if err != nil {
-		return fmt.Errorf("failed to delete existing shop images: %w", err)
+		return nil, fmt.Errorf("failed to delete existing shop images: %w", err)
 	}
 
 	// Save shop images
@@ -201,16 +395,28 @@ updated_at = VALUES(updated_at)
 		for _, image := range shop.Images {
 			_, err = tx.ExecContext(ctx, imageQuery, shop.ID.String(), image.ID.String())
 			if err != nil {
-				return fmt.Errorf("failed to save shop image: %w", err)
+				return nil, fmt.Errorf("failed to save shop image: %w", err)
 			}
 		}
 	}
 
-	if err = tx.Commit(); err != nil {
-		return fmt.Errorf("failed to commit transaction: %w", err)
+	for _, image := range addedImages(existingImageIDs, shop.Images) {
+		event, err := model.NewEvent(model.EventImageAdded, actor, map[string]any{
+			"stations": shop.Stations,
+		})
+		if err != nil {
+			return nil, fmt.Errorf("failed to create event: %w", err)
+		}
+		event.Shop = shop.ID
+		event.Image = image.ID
+		events = append(events, event)
 	}
 
-	return nil
+	if err := insertEvents(ctx, tx, events...); err != nil {
+		return nil, err
+	}
+
+	return events, nil
 }
 
 func (r *ShopRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*model.Shop, error) {
@@ -224,36 +430,13 @@ WHERE id = ?
 	err := r.db.GetContext(ctx, &dto, query, id.String())
 	if err != nil {
 		if errors.Is(err, sql.ErrNoRows) {
-			return nil, fmt.Errorf("shop not found")
+			return nil, fmt.Errorf("shop %w", repository.ErrNotFound)
 		}
 
 		return nil, fmt.Errorf("failed to get shop: %w", err)
 	}
 
-	// Get shop stations
-	stations, err := r.getShopStations(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get shop stations: %w", err)
-	}
-
-	// Get shop images
-	images, err := r.getShopImages(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get shop images: %w", err)
-	}
-
-	// Get shop payment methods
-	paymentMethods, err := r.getShopPaymentMethods(ctx, id)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get shop payment methods: %w", err)
-	}
-
-	shop, err := dto.ToModel(stations, images, paymentMethods)
-	if err != nil {
-		return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
-	}
-
-	return shop, nil
+	return r.hydrate(ctx, &dto)
 }
 
 func (r *ShopRepositoryImpl) FindAll(ctx context.Context) ([]*model.Shop, error) {
@@ -270,30 +453,9 @@ FROM shops
 
 	shops := make([]*model.Shop, 0, len(dtos))
 	for _, dto := range dtos {
-		// Get shop details for each shop
-		shopID, err := uuid.Parse(dto.ID)
+		shop, err := r.hydrate(ctx, &dto)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse shop ID: %w", err)
-		}
-
-		stations, err := r.getShopStations(ctx, shopID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get shop stations: %w", err)
-		}
-
-		images, err := r.getShopImages(ctx, shopID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get shop images: %w", err)
-		}
-
-		paymentMethods, err := r.getShopPaymentMethods(ctx, shopID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get shop payment methods: %w", err)
-		}
-
-		shop, err := dto.ToModel(stations, images, paymentMethods)
-		if err != nil {
-			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+			return nil, err
 		}
 		shops = append(shops, shop)
 	}
@@ -309,7 +471,7 @@ func (r *ShopRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 	defer func() {
 		err := tx.Rollback()
 		if err != nil && !errors.Is(err, sql.ErrTxDone) {
-			fmt.Printf("failed to rollback transaction: %v\n", err)
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
 		}
 	}()
 
@@ -334,6 +496,22 @@ func (r *ShopRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
 		return fmt.Errorf("failed to delete shop images: %w", err)
 	}
 
+	_, err = tx.ExecContext(ctx, `DELETE FROM shop_tags WHERE shop_id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete shop tags: %w", err)
+	}
+
+	// Delete shop business hours and closures
+	_, err = tx.ExecContext(ctx, `DELETE FROM shop_business_hours WHERE shop_id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete shop business hours: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM shop_closures WHERE shop_id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete shop closures: %w", err)
+	}
+
 	// Delete shop
 	deleteShopQuery := `DELETE FROM shops WHERE id = ?`
 	result, err := tx.ExecContext(ctx, deleteShopQuery, id.String())
@@ -373,30 +551,38 @@ WHERE ss.station_id = ?
 
 	shops := make([]*model.Shop, 0, len(dtos))
 	for _, dto := range dtos {
-		// Get shop details for each shop
-		shopID, err := uuid.Parse(dto.ID)
+		shop, err := r.hydrate(ctx, &dto)
 		if err != nil {
-			return nil, fmt.Errorf("failed to parse shop ID: %w", err)
+			return nil, err
 		}
+		shops = append(shops, shop)
+	}
 
-		stations, err := r.getShopStations(ctx, shopID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get shop stations: %w", err)
-		}
+	return shops, nil
+}
 
-		images, err := r.getShopImages(ctx, shopID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get shop images: %w", err)
-		}
+func (r *ShopRepositoryImpl) SearchByName(ctx context.Context, keyword string, limit int) ([]*model.Shop, error) {
+	query := `
+		SELECT *
+		FROM shops
+		WHERE name LIKE ?
+		ORDER BY name = ? DESC, name LIKE ? DESC, CHAR_LENGTH(name), id
+		LIMIT ?
+	`
 
-		paymentMethods, err := r.getShopPaymentMethods(ctx, shopID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get shop payment methods: %w", err)
-		}
+	escaped := escapeLike(keyword)
 
-		shop, err := dto.ToModel(stations, images, paymentMethods)
+	var dtos []ShopDto
+	err := r.db.SelectContext(ctx, &dtos, query, "%"+escaped+"%", keyword, escaped+"%", limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to search shops: %w", err)
+	}
+
+	shops := make([]*model.Shop, 0, len(dtos))
+	for _, dto := range dtos {
+		shop, err := r.hydrate(ctx, &dto)
 		if err != nil {
-			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+			return nil, err
 		}
 		shops = append(shops, shop)
 	}
@@ -429,6 +615,33 @@ WHERE shop_id = ?
 	return stationIDs, nil
 }
 
+func (r *ShopRepositoryImpl) getShopTags(ctx context.Context, shopID uuid.UUID) ([]uuid.UUID, error) {
+	query := `
+SELECT st.tag_id
+FROM shop_tags st
+INNER JOIN tags t ON t.id = st.tag_id
+WHERE st.shop_id = ?
+ORDER BY t.kind = 'category' DESC, t.name
+`
+
+	var tagIDs []string
+	err := r.db.SelectContext(ctx, &tagIDs, query, shopID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shop tag IDs: %w", err)
+	}
+
+	tags := make([]uuid.UUID, 0, len(tagIDs))
+	for _, tagIDStr := range tagIDs {
+		tagID, err := uuid.Parse(tagIDStr)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse tag ID: %w", err)
+		}
+		tags = append(tags, tagID)
+	}
+
+	return tags, nil
+}
+
 func (r *ShopRepositoryImpl) getShopImages(ctx context.Context, shopID uuid.UUID) ([]model.ImageFile, error) {
 	query := `
 SELECT image_id
@@ -469,3 +682,133 @@ WHERE shop_id = ?
 
 	return paymentMethods, nil
 }
+
+// getShopBusinessHours returns nil when the shop has neither weekly hours nor
+// closures registered.
+func (r *ShopRepositoryImpl) getShopBusinessHours(ctx context.Context, shopID uuid.UUID) (*model.BusinessHours, error) {
+	hoursQuery := `
+SELECT shop_id, weekday, open_minute, close_minute
+FROM shop_business_hours
+WHERE shop_id = ?
+`
+
+	var hourDtos []ShopBusinessHourDto
+	err := r.db.SelectContext(ctx, &hourDtos, hoursQuery, shopID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shop business hours: %w", err)
+	}
+
+	closuresQuery := `
+SELECT shop_id, DATE_FORMAT(date, '%Y-%m-%d') AS date, note
+FROM shop_closures
+WHERE shop_id = ?
+`
+
+	var closureDtos []ShopClosureDto
+	err = r.db.SelectContext(ctx, &closureDtos, closuresQuery, shopID.String())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shop closures: %w", err)
+	}
+
+	if len(hourDtos) == 0 && len(closureDtos) == 0 {
+		return nil, nil
+	}
+
+	ranges := make([]model.TimeRange, 0, len(hourDtos))
+	for _, dto := range hourDtos {
+		timeRange, err := model.NewTimeRange(time.Weekday(dto.Weekday), dto.OpenMinute, dto.CloseMinute)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create TimeRange: %w", err)
+		}
+		ranges = append(ranges, timeRange)
+	}
+
+	closures := make([]model.Closure, 0, len(closureDtos))
+	for _, dto := range closureDtos {
+		date, err := time.ParseInLocation(time.DateOnly, dto.Date, model.Tokyo)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse closure date: %w", err)
+		}
+		closures = append(closures, model.NewClosure(date, dto.Note))
+	}
+
+	return model.NewBusinessHours(ranges, closures), nil
+}
+
+func (r *ShopRepositoryImpl) Iterate(ctx context.Context, fn func(*model.Shop) error) error {
+	query := `
+SELECT *
+FROM shops
+WHERE id > ?
+ORDER BY id
+LIMIT ?
+`
+
+	lastID := ""
+	for {
+		var dtos []ShopDto
+		err := r.db.SelectContext(ctx, &dtos, query, lastID, iterateBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get shops: %w", err)
+		}
+
+		for _, dto := range dtos {
+			shop, err := r.hydrate(ctx, &dto)
+			if err != nil {
+				return err
+			}
+
+			if err := fn(shop); err != nil {
+				return err
+			}
+		}
+
+		if len(dtos) < iterateBatchSize {
+			return nil
+		}
+		lastID = dtos[len(dtos)-1].ID
+	}
+}
+
+// hydrate loads the stations, images, payment methods, business hours and
+// tags of a shop row.
+func (r *ShopRepositoryImpl) hydrate(ctx context.Context, dto *ShopDto) (*model.Shop, error) {
+	shopID, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse shop ID: %w", err)
+	}
+
+	stations, err := r.getShopStations(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shop stations: %w", err)
+	}
+
+	images, err := r.getShopImages(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shop images: %w", err)
+	}
+
+	paymentMethods, err := r.getShopPaymentMethods(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shop payment methods: %w", err)
+	}
+
+	businessHours, err := r.getShopBusinessHours(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shop business hours: %w", err)
+	}
+
+	tags, err := r.getShopTags(ctx, shopID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get shop tags: %w", err)
+	}
+
+	shop, err := dto.ToModel(stations, images, paymentMethods)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+	}
+	shop.BusinessHours = businessHours
+	shop.Tags = tags
+
+	return shop, nil
+}
@@ -9,6 +9,7 @@ import (
 
 	"backend/internal/domain/model"
 	"backend/internal/domain/repository"
+	"backend/internal/logging"
 
 	"github.com/google/uuid"
 	"github.com/jmoiron/sqlx"
@@ -43,16 +44,29 @@ func (dto *StationDto) FromModel(station *model.Station) {
 }
 
 type StationRepositoryImpl struct {
-	db *sqlx.DB
+	db        *sqlx.DB
+	publisher repository.EventPublisher
 }
 
-func NewStationRepository(db *sqlx.DB) repository.StationRepository {
+func NewStationRepository(db *sqlx.DB, publisher repository.EventPublisher) repository.StationRepository {
 	return &StationRepositoryImpl{
-		db: db,
+		db:        db,
+		publisher: publisher,
 	}
 }
 
 func (r *StationRepositoryImpl) Save(ctx context.Context, station *model.Station) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
 	dto := &StationDto{}
 	dto.FromModel(station)
 
@@ -64,11 +78,37 @@ func (r *StationRepositoryImpl) Save(ctx context.Context, station *model.Station
 		updated_at = VALUES(updated_at)
 	`
 
-	_, err := r.db.NamedExecContext(ctx, query, dto)
+	result, err := tx.NamedExecContext(ctx, query, dto)
 	if err != nil {
 		return fmt.Errorf("failed to save station: %w", err)
 	}
 
+	eventType, changed, err := upsertEventType(result, model.EventStationCreated, model.EventStationUpdated)
+	if err != nil {
+		return err
+	}
+	var events []*model.Event
+	if changed {
+		event, err := model.NewEvent(eventType, eventActor(ctx, ""), map[string]any{
+			"name": station.Name,
+		})
+		if err != nil {
+			return fmt.Errorf("failed to create event: %w", err)
+		}
+		event.Station = station.ID
+		events = append(events, event)
+	}
+
+	if err := insertEvents(ctx, tx, events...); err != nil {
+		return err
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	r.publisher.Publish(events...)
+
 	return nil
 }
 
@@ -97,6 +137,33 @@ func (r *StationRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*mo
 	return station, nil
 }
 
+func (r *StationRepositoryImpl) FindByName(ctx context.Context, name string) (*model.Station, error) {
+	query := `
+		SELECT *
+		FROM stations
+		WHERE name LIKE ?
+		ORDER BY name = ? DESC, CHAR_LENGTH(name), id
+		LIMIT 1
+	`
+
+	var dto StationDto
+	err := r.db.GetContext(ctx, &dto, query, escapeLike(name)+"%", name)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("station %w", repository.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to get station: %w", err)
+	}
+
+	station, err := dto.ToModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+	}
+
+	return station, nil
+}
+
 func (r *StationRepositoryImpl) FindAll(ctx context.Context) ([]*model.Station, error) {
 	query := `
 		SELECT *
@@ -140,3 +207,38 @@ func (r *StationRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error
 
 	return nil
 }
+
+func (r *StationRepositoryImpl) Iterate(ctx context.Context, fn func(*model.Station) error) error {
+	query := `
+		SELECT *
+		FROM stations
+		WHERE id > ?
+		ORDER BY id
+		LIMIT ?
+	`
+
+	lastID := ""
+	for {
+		var dtos []StationDto
+		err := r.db.SelectContext(ctx, &dtos, query, lastID, iterateBatchSize)
+		if err != nil {
+			return fmt.Errorf("failed to get stations: %w", err)
+		}
+
+		for _, dto := range dtos {
+			station, err := dto.ToModel()
+			if err != nil {
+				return fmt.Errorf("failed to convert DTO to model: %w", err)
+			}
+
+			if err := fn(station); err != nil {
+				return err
+			}
+		}
+
+		if len(dtos) < iterateBatchSize {
+			return nil
+		}
+		lastID = dtos[len(dtos)-1].ID
+	}
+}
@@ -0,0 +1,305 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+// tagSelectQuery selects tags together with the number of shops using them.
+const tagSelectQuery = `
+SELECT t.*, COUNT(st.shop_id) AS usage_count
+FROM tags t
+LEFT JOIN shop_tags st ON st.tag_id = t.id
+`
+
+type TagDto struct {
+	ID         string         `db:"id"`
+	Name       string         `db:"name"`
+	Kind       string         `db:"kind"`
+	ParentID   sql.NullString `db:"parent_id"`
+	CreatedBy  string         `db:"created_by"`
+	UsageCount int            `db:"usage_count"`
+	CreatedAt  time.Time      `db:"created_at"`
+	UpdatedAt  time.Time      `db:"updated_at"`
+}
+
+func (dto *TagDto) ToModel() (*model.Tag, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse tag UUID: %w", err)
+	}
+
+	name, err := model.NewTagName(dto.Name)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create TagName: %w", err)
+	}
+
+	kind, err := model.ParseTagKind(dto.Kind)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse TagKind: %w", err)
+	}
+
+	parent := uuid.Nil
+	if dto.ParentID.Valid {
+		parent, err = uuid.Parse(dto.ParentID.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse parent tag UUID: %w", err)
+		}
+	}
+
+	return &model.Tag{
+		ID:         id,
+		Name:       name,
+		Kind:       kind,
+		Parent:     parent,
+		UsageCount: dto.UsageCount,
+		CreatedBy:  model.UserID(dto.CreatedBy),
+		CreatedAt:  dto.CreatedAt,
+		UpdatedAt:  dto.UpdatedAt,
+	}, nil
+}
+
+func (dto *TagDto) FromModel(tag *model.Tag) {
+	dto.ID = tag.ID.String()
+	dto.Name = string(tag.Name)
+	dto.Kind = string(tag.Kind)
+	dto.ParentID = sql.NullString{}
+	if tag.Parent != uuid.Nil {
+		dto.ParentID = sql.NullString{String: tag.Parent.String(), Valid: true}
+	}
+	dto.CreatedBy = string(tag.CreatedBy)
+	dto.UsageCount = tag.UsageCount
+	dto.CreatedAt = tag.CreatedAt
+	dto.UpdatedAt = tag.UpdatedAt
+}
+
+type TagRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewTagRepository(db *sqlx.DB) repository.TagRepository {
+	return &TagRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *TagRepositoryImpl) Save(ctx context.Context, tag *model.Tag) error {
+	dto := &TagDto{}
+	dto.FromModel(tag)
+
+	// ON DUPLICATE KEY UPDATE would also fire on the unique name and overwrite
+	// another tag, so insert and update are issued separately.
+	query := `
+UPDATE tags
+SET name = :name, kind = :kind, parent_id = :parent_id, updated_at = :updated_at
+WHERE id = :id
+`
+	var exists bool
+	err := r.db.GetContext(ctx, &exists, `SELECT EXISTS(SELECT 1 FROM tags WHERE id = ?)`, dto.ID)
+	if err != nil {
+		return fmt.Errorf("failed to check tag: %w", err)
+	}
+	if !exists {
+		query = `
+INSERT INTO tags (id, name, kind, parent_id, created_by, created_at, updated_at)
+VALUES (:id, :name, :kind, :parent_id, :created_by, :created_at, :updated_at)
+`
+	}
+
+	_, err = r.db.NamedExecContext(ctx, query, dto)
+	if err != nil {
+		if isDuplicateEntry(err) {
+			return fmt.Errorf("tag name %w", repository.ErrConflict)
+		}
+
+		return fmt.Errorf("failed to save tag: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TagRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*model.Tag, error) {
+	return r.findOne(ctx, "t.id = ?", id.String())
+}
+
+func (r *TagRepositoryImpl) FindByName(ctx context.Context, name model.TagName) (*model.Tag, error) {
+	return r.findOne(ctx, "t.name = ?", string(name))
+}
+
+func (r *TagRepositoryImpl) findOne(ctx context.Context, condition string, arg any) (*model.Tag, error) {
+	query := tagSelectQuery + `
+WHERE ` + condition + `
+GROUP BY t.id
+`
+
+	var dto TagDto
+	err := r.db.GetContext(ctx, &dto, query, arg)
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("tag %w", repository.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to get tag: %w", err)
+	}
+
+	tag, err := dto.ToModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+	}
+
+	return tag, nil
+}
+
+func (r *TagRepositoryImpl) FindAll(ctx context.Context, filter repository.TagFilter) ([]*model.Tag, error) {
+	var conditions []string
+	var args []interface{}
+
+	if filter.Kind != "" {
+		conditions = append(conditions, "t.kind = ?")
+		args = append(args, string(filter.Kind))
+	}
+
+	if filter.Query != "" {
+		conditions = append(conditions, "t.name LIKE ?")
+		args = append(args, "%"+escapeLike(filter.Query)+"%")
+	}
+
+	whereClause := ""
+	if len(conditions) > 0 {
+		whereClause = "WHERE " + strings.Join(conditions, " AND ")
+	}
+
+	query := tagSelectQuery + whereClause + `
+GROUP BY t.id
+ORDER BY t.kind = 'category' DESC, usage_count DESC, t.name
+`
+
+	var dtos []TagDto
+	err := r.db.SelectContext(ctx, &dtos, query, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get tags: %w", err)
+	}
+
+	tags := make([]*model.Tag, 0, len(dtos))
+	for _, dto := range dtos {
+		tag, err := dto.ToModel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+		}
+		tags = append(tags, tag)
+	}
+
+	return tags, nil
+}
+
+func (r *TagRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	// Move subcategories up to the parent of the deleted tag
+	_, err = tx.ExecContext(ctx, `
+UPDATE tags c
+INNER JOIN tags p ON c.parent_id = p.id
+SET c.parent_id = p.parent_id
+WHERE p.id = ?
+`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to move subcategories: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM shop_tags WHERE tag_id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete shop tags: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = ?`, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("tag %w", repository.ErrNotFound)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *TagRepositoryImpl) Merge(ctx context.Context, source, target uuid.UUID) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	// Retag shops, skipping the ones that already have the target tag
+	_, err = tx.ExecContext(ctx, `
+INSERT IGNORE INTO shop_tags (shop_id, tag_id)
+SELECT shop_id, ? FROM shop_tags WHERE tag_id = ?
+`, target.String(), source.String())
+	if err != nil {
+		return fmt.Errorf("failed to move shop tags: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `DELETE FROM shop_tags WHERE tag_id = ?`, source.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete shop tags: %w", err)
+	}
+
+	_, err = tx.ExecContext(ctx, `UPDATE tags SET parent_id = ? WHERE parent_id = ?`, target.String(), source.String())
+	if err != nil {
+		return fmt.Errorf("failed to move subcategories: %w", err)
+	}
+
+	result, err := tx.ExecContext(ctx, `DELETE FROM tags WHERE id = ?`, source.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete tag: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("tag %w", repository.ErrNotFound)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
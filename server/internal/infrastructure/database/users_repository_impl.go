@@ -0,0 +1,253 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type UserDto struct {
+	ID            string         `db:"id"`
+	DisplayName   string         `db:"display_name"`
+	AvatarImageID sql.NullString `db:"avatar_image_id"`
+	CreatedAt     time.Time      `db:"created_at"`
+	UpdatedAt     time.Time      `db:"updated_at"`
+}
+
+func (dto *UserDto) ToModel() (*model.User, error) {
+	userID, err := model.NewUserID(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create UserID: %w", err)
+	}
+
+	var avatar *model.ImageFile
+	if dto.AvatarImageID.Valid {
+		imageID, err := uuid.Parse(dto.AvatarImageID.String)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse avatar image ID: %w", err)
+		}
+		avatar = model.NewImageFile(imageID)
+	}
+
+	return &model.User{
+		ID:          userID,
+		DisplayName: model.DisplayName(dto.DisplayName),
+		Avatar:      avatar,
+		CreatedAt:   dto.CreatedAt,
+		UpdatedAt:   dto.UpdatedAt,
+	}, nil
+}
+
+func (dto *UserDto) FromModel(user *model.User) {
+	dto.ID = string(user.ID)
+	dto.DisplayName = string(user.DisplayName)
+	dto.AvatarImageID = sql.NullString{}
+	if user.Avatar != nil {
+		dto.AvatarImageID = sql.NullString{String: user.Avatar.ID.String(), Valid: true}
+	}
+	dto.CreatedAt = user.CreatedAt
+	dto.UpdatedAt = user.UpdatedAt
+}
+
+type UserRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewUserRepository(db *sqlx.DB) repository.UserRepository {
+	return &UserRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *UserRepositoryImpl) Save(ctx context.Context, user *model.User) error {
+	dto := &UserDto{}
+	dto.FromModel(user)
+
+	query := `
+		INSERT INTO users (id, display_name, avatar_image_id, created_at, updated_at)
+		VALUES (:id, :display_name, :avatar_image_id, :created_at, :updated_at)
+		ON DUPLICATE KEY UPDATE
+		display_name = VALUES(display_name),
+		avatar_image_id = VALUES(avatar_image_id),
+		updated_at = VALUES(updated_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, dto)
+	if err != nil {
+		return fmt.Errorf("failed to save user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepositoryImpl) FindByID(ctx context.Context, id model.UserID) (*model.User, error) {
+	query := `
+		SELECT *
+		FROM users
+		WHERE id = ?
+	`
+
+	var dto UserDto
+	err := r.db.GetContext(ctx, &dto, query, string(id))
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("user %w", repository.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to get user: %w", err)
+	}
+
+	user, err := dto.ToModel()
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+	}
+
+	return user, nil
+}
+
+func (r *UserRepositoryImpl) EnsureExists(ctx context.Context, id model.UserID) error {
+	dto := &UserDto{}
+	dto.FromModel(model.NewUser(id))
+
+	query := `
+		INSERT IGNORE INTO users (id, display_name, created_at, updated_at)
+		VALUES (:id, :display_name, :created_at, :updated_at)
+	`
+
+	_, err := r.db.NamedExecContext(ctx, query, dto)
+	if err != nil {
+		return fmt.Errorf("failed to create user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepositoryImpl) GetProfile(ctx context.Context, id model.UserID, includeHidden bool) (*model.UserProfile, error) {
+	user, err := r.FindByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	profile := &model.UserProfile{
+		User:               user,
+		RatingDistribution: make(map[model.Rating]int),
+	}
+
+	reviewCondition := "author = ?"
+	if !includeHidden {
+		reviewCondition += " AND hidden = FALSE"
+	}
+
+	// Rating distribution
+	ratingQuery := `
+		SELECT rating, COUNT(*) AS count
+		FROM reviews
+		WHERE ` + reviewCondition + `
+		GROUP BY rating
+	`
+
+	var ratings []struct {
+		Rating int `db:"rating"`
+		Count  int `db:"count"`
+	}
+	if err := r.db.SelectContext(ctx, &ratings, ratingQuery, string(id)); err != nil {
+		return nil, fmt.Errorf("failed to get rating distribution: %w", err)
+	}
+
+	for _, v := range ratings {
+		rating, err := model.NewRating(v.Rating)
+		if err != nil {
+			return nil, fmt.Errorf("failed to create Rating: %w", err)
+		}
+		profile.RatingDistribution[rating] = v.Count
+		profile.ReviewCount += v.Count
+	}
+
+	// Registered shops
+	shopQuery := `SELECT COUNT(*) FROM shops WHERE registerer = ?`
+	if err := r.db.GetContext(ctx, &profile.ShopCount, shopQuery, string(id)); err != nil {
+		return nil, fmt.Errorf("failed to count registered shops: %w", err)
+	}
+
+	// First and last activity across reviews and shops
+	activityQuery := `
+		SELECT MIN(t) AS first_at, MAX(t) AS last_at
+		FROM (
+			SELECT created_at AS t FROM reviews WHERE ` + reviewCondition + `
+			UNION ALL
+			SELECT updated_at AS t FROM reviews WHERE ` + reviewCondition + `
+			UNION ALL
+			SELECT created_at AS t FROM shops WHERE registerer = ?
+		) activities
+	`
+
+	var activity struct {
+		FirstAt sql.NullTime `db:"first_at"`
+		LastAt  sql.NullTime `db:"last_at"`
+	}
+	err = r.db.GetContext(ctx, &activity, activityQuery, string(id), string(id), string(id))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user activity: %w", err)
+	}
+
+	if activity.FirstAt.Valid {
+		profile.FirstActivityAt = &activity.FirstAt.Time
+	}
+	if activity.LastAt.Valid {
+		profile.LastActivityAt = &activity.LastAt.Time
+	}
+
+	return profile, nil
+}
+
+func (r *UserRepositoryImpl) Follow(ctx context.Context, follower, followee model.UserID) error {
+	query := `INSERT IGNORE INTO user_follows (follower, followee) VALUES (?, ?)`
+
+	_, err := r.db.ExecContext(ctx, query, string(follower), string(followee))
+	if err != nil {
+		return fmt.Errorf("failed to follow user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepositoryImpl) Unfollow(ctx context.Context, follower, followee model.UserID) error {
+	query := `DELETE FROM user_follows WHERE follower = ? AND followee = ?`
+
+	_, err := r.db.ExecContext(ctx, query, string(follower), string(followee))
+	if err != nil {
+		return fmt.Errorf("failed to unfollow user: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepositoryImpl) AddFavoriteShop(ctx context.Context, id model.UserID, shopID uuid.UUID) error {
+	query := `INSERT IGNORE INTO favorite_shops (user_id, shop_id) VALUES (?, ?)`
+
+	_, err := r.db.ExecContext(ctx, query, string(id), shopID.String())
+	if err != nil {
+		return fmt.Errorf("failed to add favorite shop: %w", err)
+	}
+
+	return nil
+}
+
+func (r *UserRepositoryImpl) RemoveFavoriteShop(ctx context.Context, id model.UserID, shopID uuid.UUID) error {
+	query := `DELETE FROM favorite_shops WHERE user_id = ? AND shop_id = ?`
+
+	_, err := r.db.ExecContext(ctx, query, string(id), shopID.String())
+	if err != nil {
+		return fmt.Errorf("failed to remove favorite shop: %w", err)
+	}
+
+	return nil
+}
@@ -0,0 +1,424 @@
+package database
+
+import (
+	"context"
+	"database/sql"
+	"errors"
+	"fmt"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/google/uuid"
+	"github.com/jmoiron/sqlx"
+)
+
+type WebhookDto struct {
+	ID        string         `db:"id"`
+	URL       string         `db:"url"`
+	Secret    string         `db:"secret"`
+	Active    bool           `db:"active"`
+	CreatedBy sql.NullString `db:"created_by"`
+	CreatedAt time.Time      `db:"created_at"`
+	UpdatedAt time.Time      `db:"updated_at"`
+}
+
+func (dto *WebhookDto) ToModel(events []model.EventType) (*model.Webhook, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook UUID: %w", err)
+	}
+
+	return &model.Webhook{
+		ID:        id,
+		URL:       dto.URL,
+		Secret:    dto.Secret,
+		Events:    events,
+		Active:    dto.Active,
+		CreatedBy: model.UserID(dto.CreatedBy.String),
+		CreatedAt: dto.CreatedAt,
+		UpdatedAt: dto.UpdatedAt,
+	}, nil
+}
+
+func (dto *WebhookDto) FromModel(webhook *model.Webhook) {
+	dto.ID = webhook.ID.String()
+	dto.URL = webhook.URL
+	dto.Secret = webhook.Secret
+	dto.Active = webhook.Active
+	dto.CreatedBy = nullString(string(webhook.CreatedBy))
+	dto.CreatedAt = webhook.CreatedAt
+	dto.UpdatedAt = webhook.UpdatedAt
+}
+
+type WebhookDeliveryDto struct {
+	ID             string         `db:"id"`
+	WebhookID      string         `db:"webhook_id"`
+	EventType      string         `db:"event_type"`
+	Payload        string         `db:"payload"`
+	Status         string         `db:"status"`
+	Attempts       int            `db:"attempts"`
+	NextAttemptAt  sql.NullTime   `db:"next_attempt_at"`
+	LastError      sql.NullString `db:"last_error"`
+	ResponseStatus sql.NullInt64  `db:"response_status"`
+	CreatedAt      time.Time      `db:"created_at"`
+	UpdatedAt      time.Time      `db:"updated_at"`
+	DeliveredAt    sql.NullTime   `db:"delivered_at"`
+}
+
+func (dto *WebhookDeliveryDto) ToModel() (*model.WebhookDelivery, error) {
+	id, err := uuid.Parse(dto.ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse delivery UUID: %w", err)
+	}
+
+	webhookID, err := uuid.Parse(dto.WebhookID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse webhook UUID: %w", err)
+	}
+
+	var deliveredAt *time.Time
+	if dto.DeliveredAt.Valid {
+		deliveredAt = &dto.DeliveredAt.Time
+	}
+
+	return &model.WebhookDelivery{
+		ID:             id,
+		Webhook:        webhookID,
+		Event:          model.EventType(dto.EventType),
+		Payload:        []byte(dto.Payload),
+		Status:         model.DeliveryStatus(dto.Status),
+		Attempts:       dto.Attempts,
+		NextAttemptAt:  dto.NextAttemptAt.Time,
+		LastError:      dto.LastError.String,
+		ResponseStatus: int(dto.ResponseStatus.Int64),
+		CreatedAt:      dto.CreatedAt,
+		UpdatedAt:      dto.UpdatedAt,
+		DeliveredAt:    deliveredAt,
+	}, nil
+}
+
+func (dto *WebhookDeliveryDto) FromModel(delivery *model.WebhookDelivery) {
+	dto.ID = delivery.ID.String()
+	dto.WebhookID = delivery.Webhook.String()
+	dto.EventType = string(delivery.Event)
+	dto.Payload = string(delivery.Payload)
+	dto.Status = string(delivery.Status)
+	dto.Attempts = delivery.Attempts
+	dto.NextAttemptAt = sql.NullTime{Time: delivery.NextAttemptAt, Valid: !delivery.NextAttemptAt.IsZero()}
+	dto.LastError = nullString(delivery.LastError)
+	dto.ResponseStatus = sql.NullInt64{Int64: int64(delivery.ResponseStatus), Valid: delivery.ResponseStatus != 0}
+	dto.CreatedAt = delivery.CreatedAt
+	dto.UpdatedAt = delivery.UpdatedAt
+	dto.DeliveredAt = sql.NullTime{}
+	if delivery.DeliveredAt != nil {
+		dto.DeliveredAt = sql.NullTime{Time: *delivery.DeliveredAt, Valid: true}
+	}
+}
+
+type WebhookRepositoryImpl struct {
+	db *sqlx.DB
+}
+
+func NewWebhookRepository(db *sqlx.DB) repository.WebhookRepository {
+	return &WebhookRepositoryImpl{
+		db: db,
+	}
+}
+
+func (r *WebhookRepositoryImpl) Save(ctx context.Context, webhook *model.Webhook) error {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	dto := &WebhookDto{}
+	dto.FromModel(webhook)
+
+	query := `
+		INSERT INTO webhooks (id, url, secret, active, created_by, created_at, updated_at)
+		VALUES (:id, :url, :secret, :active, :created_by, :created_at, :updated_at)
+		ON DUPLICATE KEY UPDATE
+		url = VALUES(url),
+		secret = VALUES(secret),
+		active = VALUES(active),
+		updated_at = VALUES(updated_at)
+	`
+
+	if _, err := tx.NamedExecContext(ctx, query, dto); err != nil {
+		return fmt.Errorf("failed to save webhook: %w", err)
+	}
+
+	// Replace subscribed events
+	deleteEventsQuery := `DELETE FROM webhook_events WHERE webhook_id = ?`
+	if _, err := tx.ExecContext(ctx, deleteEventsQuery, webhook.ID.String()); err != nil {
+		return fmt.Errorf("failed to delete existing webhook events: %w", err)
+	}
+
+	eventQuery := `INSERT INTO webhook_events (webhook_id, event_type) VALUES (?, ?)`
+	for _, event := range webhook.Events {
+		if _, err := tx.ExecContext(ctx, eventQuery, webhook.ID.String(), string(event)); err != nil {
+			return fmt.Errorf("failed to save webhook event: %w", err)
+		}
+	}
+
+	if err = tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepositoryImpl) FindByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	query := `
+		SELECT *
+		FROM webhooks
+		WHERE id = ?
+	`
+
+	var dto WebhookDto
+	err := r.db.GetContext(ctx, &dto, query, id.String())
+	if err != nil {
+		if errors.Is(err, sql.ErrNoRows) {
+			return nil, fmt.Errorf("webhook %w", repository.ErrNotFound)
+		}
+
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return r.toModel(ctx, &dto)
+}
+
+func (r *WebhookRepositoryImpl) FindAll(ctx context.Context) ([]*model.Webhook, error) {
+	query := `
+		SELECT *
+		FROM webhooks
+		ORDER BY created_at
+	`
+
+	return r.selectWebhooks(ctx, query)
+}
+
+func (r *WebhookRepositoryImpl) FindSubscribers(ctx context.Context, event model.EventType) ([]*model.Webhook, error) {
+	query := `
+		SELECT w.*
+		FROM webhooks w
+		INNER JOIN webhook_events we ON w.id = we.webhook_id
+		WHERE w.active = TRUE AND we.event_type = ?
+	`
+
+	return r.selectWebhooks(ctx, query, string(event))
+}
+
+func (r *WebhookRepositoryImpl) Delete(ctx context.Context, id uuid.UUID) error {
+	query := `DELETE FROM webhooks WHERE id = ?`
+
+	result, err := r.db.ExecContext(ctx, query, id.String())
+	if err != nil {
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook %w", repository.ErrNotFound)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepositoryImpl) EnqueueDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	query := `
+		INSERT INTO webhook_deliveries (
+			id, webhook_id, event_type, payload, status, attempts, next_attempt_at, created_at, updated_at
+		)
+		VALUES (
+			:id, :webhook_id, :event_type, :payload, :status, :attempts, :next_attempt_at, :created_at, :updated_at
+		)
+	`
+
+	for _, delivery := range deliveries {
+		dto := &WebhookDeliveryDto{}
+		dto.FromModel(delivery)
+
+		if _, err := r.db.NamedExecContext(ctx, query, dto); err != nil {
+			return fmt.Errorf("failed to enqueue webhook delivery: %w", err)
+		}
+	}
+
+	return nil
+}
+
+func (r *WebhookRepositoryImpl) ClaimDueDeliveries(
+	ctx context.Context,
+	now time.Time,
+	limit int,
+	lease time.Duration,
+) ([]*model.WebhookDelivery, error) {
+	tx, err := r.db.BeginTxx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer func() {
+		err := tx.Rollback()
+		if err != nil && !errors.Is(err, sql.ErrTxDone) {
+			logging.FromContext(ctx).Error("failed to rollback transaction", "error", err)
+		}
+	}()
+
+	// 無効化された Webhook への配信は送らずに失敗として終わらせる
+	_, err = tx.ExecContext(ctx, `
+		UPDATE webhook_deliveries d
+		JOIN webhooks w ON w.id = d.webhook_id
+		SET d.status = ?, d.last_error = ?, d.updated_at = ?
+		WHERE d.status = ? AND d.next_attempt_at <= ? AND w.active = FALSE
+	`, string(model.DeliveryFailed), model.ErrWebhookInactive.Error(), now,
+		string(model.DeliveryPending), now)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fail deliveries of inactive webhooks: %w", err)
+	}
+
+	// 複数のワーカーが同じ配信を取得しないよう行ロックを取り、ロック中の行は飛ばす
+	query := `
+		SELECT *
+		FROM webhook_deliveries
+		WHERE status = ? AND next_attempt_at <= ?
+		ORDER BY next_attempt_at
+		LIMIT ?
+		FOR UPDATE SKIP LOCKED
+	`
+
+	var dtos []WebhookDeliveryDto
+	err = tx.SelectContext(ctx, &dtos, query, string(model.DeliveryPending), now, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get due webhook deliveries: %w", err)
+	}
+
+	leaseQuery := `UPDATE webhook_deliveries SET next_attempt_at = ? WHERE id = ?`
+	deliveries := make([]*model.WebhookDelivery, 0, len(dtos))
+	for _, dto := range dtos {
+		if _, err := tx.ExecContext(ctx, leaseQuery, now.Add(lease), dto.ID); err != nil {
+			return nil, fmt.Errorf("failed to lease webhook delivery: %w", err)
+		}
+
+		delivery, err := dto.ToModel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err = tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepositoryImpl) SaveDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	dto := &WebhookDeliveryDto{}
+	dto.FromModel(delivery)
+
+	query := `
+		UPDATE webhook_deliveries
+		SET
+			status = :status,
+			attempts = :attempts,
+			next_attempt_at = :next_attempt_at,
+			last_error = :last_error,
+			response_status = :response_status,
+			updated_at = :updated_at,
+			delivered_at = :delivered_at
+		WHERE id = :id
+	`
+
+	if _, err := r.db.NamedExecContext(ctx, query, dto); err != nil {
+		return fmt.Errorf("failed to save webhook delivery: %w", err)
+	}
+
+	return nil
+}
+
+func (r *WebhookRepositoryImpl) FindDeliveries(
+	ctx context.Context,
+	webhookID uuid.UUID,
+	limit int,
+) ([]*model.WebhookDelivery, error) {
+	query := `
+		SELECT *
+		FROM webhook_deliveries
+		WHERE webhook_id = ?
+		ORDER BY created_at DESC, id DESC
+		LIMIT ?
+	`
+
+	var dtos []WebhookDeliveryDto
+	if err := r.db.SelectContext(ctx, &dtos, query, webhookID.String(), limit); err != nil {
+		return nil, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+
+	deliveries := make([]*model.WebhookDelivery, 0, len(dtos))
+	for _, dto := range dtos {
+		delivery, err := dto.ToModel()
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	return deliveries, nil
+}
+
+func (r *WebhookRepositoryImpl) selectWebhooks(ctx context.Context, query string, args ...interface{}) ([]*model.Webhook, error) {
+	var dtos []WebhookDto
+	if err := r.db.SelectContext(ctx, &dtos, query, args...); err != nil {
+		return nil, fmt.Errorf("failed to get webhooks: %w", err)
+	}
+
+	webhooks := make([]*model.Webhook, 0, len(dtos))
+	for _, dto := range dtos {
+		webhook, err := r.toModel(ctx, &dto)
+		if err != nil {
+			return nil, err
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	return webhooks, nil
+}
+
+func (r *WebhookRepositoryImpl) toModel(ctx context.Context, dto *WebhookDto) (*model.Webhook, error) {
+	query := `
+		SELECT event_type
+		FROM webhook_events
+		WHERE webhook_id = ?
+	`
+
+	var eventTypes []string
+	if err := r.db.SelectContext(ctx, &eventTypes, query, dto.ID); err != nil {
+		return nil, fmt.Errorf("failed to get webhook events: %w", err)
+	}
+
+	events := make([]model.EventType, len(eventTypes))
+	for i, e := range eventTypes {
+		events[i] = model.EventType(e)
+	}
+
+	webhook, err := dto.ToModel(events)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert DTO to model: %w", err)
+	}
+
+	return webhook, nil
+}
@@ -5,7 +5,6 @@ import (
 	"fmt"
 	"io"
 
-	"backend/internal/domain/repository"
 	"backend/pkg/config"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
@@ -13,6 +12,7 @@ import (
 	"github.com/aws/aws-sdk-go-v2/credentials"
 	"github.com/aws/aws-sdk-go-v2/service/s3"
 	"github.com/google/uuid"
+	"go.opentelemetry.io/contrib/instrumentation/github.com/aws/aws-sdk-go-v2/otelaws"
 )
 
 type RepositoryImpl struct {
@@ -20,7 +20,7 @@ type RepositoryImpl struct {
 	bucket   string
 }
 
-func NewFileRepository() (repository.FileRepository, error) {
+func NewFileRepository() (*RepositoryImpl, error) {
 	awsConf := config.AWS()
 
 	var cfg aws.Config
@@ -46,6 +46,9 @@ func NewFileRepository() (repository.FileRepository, error) {
 		return nil, fmt.Errorf("failed to load AWS config: %w", err)
 	}
 
+	// S3の呼び出しごとにスパンを作る
+	otelaws.AppendMiddlewares(&cfg.APIOptions)
+
 	// S3クライアントのオプションを設定
 	var s3Options []func(*s3.Options)
 
@@ -121,3 +124,16 @@ func (r *RepositoryImpl) GetImage(ctx context.Context, fileID uuid.UUID) (io.Rea
 
 	return result.Body, contentType, nil
 }
+
+// Check reports whether the bucket exists and is accessible with the
+// configured credentials.
+func (r *RepositoryImpl) Check(ctx context.Context) error {
+	_, err := r.s3Client.HeadBucket(ctx, &s3.HeadBucketInput{
+		Bucket: aws.String(r.bucket),
+	})
+	if err != nil {
+		return fmt.Errorf("failed to head bucket %s: %w", r.bucket, err)
+	}
+
+	return nil
+}
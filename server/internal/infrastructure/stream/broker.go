@@ -0,0 +1,159 @@
+package stream
+
+import (
+	"sync"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+const (
+	// DefaultBufferSize is the number of recent events kept for Last-Event-ID
+	// resumption.
+	DefaultBufferSize = 1024
+
+	// 購読者ごとの送信待ちキューの長さ。溢れた購読者は切断され、再接続時に
+	// リングバッファから追いつく
+	subscriberQueueSize = 64
+)
+
+// Discard is an EventPublisher that drops every event, for processes that do
+// not serve live streams.
+var Discard repository.EventPublisher = discard{}
+
+type discard struct{}
+
+func (discard) Publish(...*model.Event) {}
+
+// Broker fans published events out to subscribers and keeps the most recent
+// ones in a fixed-size ring buffer so reconnecting clients can catch up.
+type Broker struct {
+	mu          sync.Mutex
+	buffer      []*model.Event
+	next        int
+	full        bool
+	subscribers map[*Subscription]struct{}
+	// Shutdown 後は購読を受け付けない
+	closed bool
+}
+
+func NewBroker(size int) *Broker {
+	return &Broker{
+		buffer:      make([]*model.Event, size),
+		subscribers: make(map[*Subscription]struct{}),
+	}
+}
+
+// Subscription receives events published after it was created. C is closed
+// when the subscriber falls too far behind, Close is called or the broker is
+// shut down.
+type Subscription struct {
+	C <-chan *model.Event
+
+	ch     chan *model.Event
+	broker *Broker
+}
+
+func (b *Broker) Publish(events ...*model.Event) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	for _, event := range events {
+		if len(b.buffer) > 0 {
+			b.buffer[b.next] = event
+			b.next = (b.next + 1) % len(b.buffer)
+			if b.next == 0 {
+				b.full = true
+			}
+		}
+
+		for sub := range b.subscribers {
+			select {
+			case sub.ch <- event:
+			default:
+				// 詰まった購読者は待たずに切断する
+				b.remove(sub)
+			}
+		}
+	}
+}
+
+// Subscribe registers a new subscriber. When lastEventID is not uuid.Nil the
+// buffered events published after it are returned for replay; complete is
+// false when lastEventID is no longer in the buffer and events may have been
+// missed.
+func (b *Broker) Subscribe(lastEventID uuid.UUID) (sub *Subscription, replay []*model.Event, complete bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	ch := make(chan *model.Event, subscriberQueueSize)
+	sub = &Subscription{C: ch, ch: ch, broker: b}
+	if b.closed {
+		close(ch)
+
+		return sub, nil, true
+	}
+	b.subscribers[sub] = struct{}{}
+
+	if lastEventID == uuid.Nil {
+		return sub, nil, true
+	}
+
+	buffered := b.buffered()
+	for i, event := range buffered {
+		if event.ID == lastEventID {
+			return sub, buffered[i+1:], true
+		}
+	}
+
+	// 見つからない場合はIDの時刻順で新しいものを返す(UUIDv7)
+	for i, event := range buffered {
+		if event.ID.String() > lastEventID.String() {
+			return sub, buffered[i:], false
+		}
+	}
+
+	return sub, nil, false
+}
+
+// Shutdown disconnects every subscriber, including the ones subscribing
+// later, so that open streams do not keep the server from shutting down.
+func (b *Broker) Shutdown() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.closed = true
+	for sub := range b.subscribers {
+		b.remove(sub)
+	}
+}
+
+// Close unregisters the subscription.
+func (s *Subscription) Close() {
+	s.broker.mu.Lock()
+	defer s.broker.mu.Unlock()
+
+	s.broker.remove(s)
+}
+
+func (b *Broker) remove(sub *Subscription) {
+	if _, ok := b.subscribers[sub]; !ok {
+		return
+	}
+	delete(b.subscribers, sub)
+	close(sub.ch)
+}
+
+// buffered returns the buffered events oldest first. The caller must hold mu.
+func (b *Broker) buffered() []*model.Event {
+	if !b.full {
+		return append([]*model.Event(nil), b.buffer[:b.next]...)
+	}
+
+	events := make([]*model.Event, 0, len(b.buffer))
+	events = append(events, b.buffer[b.next:]...)
+
+	return append(events, b.buffer[:b.next]...)
+}
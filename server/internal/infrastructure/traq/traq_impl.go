@@ -0,0 +1,70 @@
+package traq
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+
+	"backend/internal/domain/repository"
+	"backend/pkg/config"
+
+	"github.com/google/uuid"
+)
+
+const maxErrorBodyBytes = 1024
+
+type RepositoryImpl struct {
+	client      *http.Client
+	baseURL     string
+	accessToken string
+}
+
+func NewTraqRepository() repository.TraqRepository {
+	traqConf := config.TraqBot()
+
+	return &RepositoryImpl{
+		client:      &http.Client{Timeout: 10 * time.Second},
+		baseURL:     strings.TrimSuffix(traqConf.APIBaseURL, "/"),
+		accessToken: traqConf.AccessToken,
+	}
+}
+
+type postMessageRequest struct {
+	Content string `json:"content"`
+	// メンションやチャンネルリンクを埋め込み形式に変換する
+	Embed bool `json:"embed"`
+}
+
+func (r *RepositoryImpl) PostMessage(ctx context.Context, channelID uuid.UUID, content string) error {
+	body, err := json.Marshal(postMessageRequest{Content: content, Embed: true})
+	if err != nil {
+		return fmt.Errorf("failed to marshal message: %w", err)
+	}
+
+	url := fmt.Sprintf("%s/channels/%s/messages", r.baseURL, channelID)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", "Bearer "+r.accessToken)
+
+	res, err := r.client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to post message: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		resBody, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodyBytes))
+
+		return fmt.Errorf("failed to post message: unexpected status %d: %s", res.StatusCode, resBody)
+	}
+
+	return nil
+}
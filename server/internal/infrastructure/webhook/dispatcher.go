@@ -0,0 +1,187 @@
+package webhook
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"strconv"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+)
+
+const (
+	pollInterval = 5 * time.Second
+	batchSize    = 20
+	// 送信中の配信を他のワーカーが拾わないようにする時間。バッチは1件ずつ
+	// 送るので、最後の配信が終わるまで lease が切れないようにする
+	leaseDuration = batchSize * deliveryTimeout
+	// これを超えて失敗した配信は failed として諦める
+	maxAttempts = 8
+	baseBackoff = 30 * time.Second
+	maxBackoff  = 6 * time.Hour
+
+	maxErrorBodyBytes = 1024
+
+	requestTimeout = 10 * time.Second
+	// 1件の配信（Webhookの取得、送信、結果の保存）にかける時間の上限。
+	// 停止時も、送信中の配信はこの時間まで待って終わらせる
+	deliveryTimeout = 30 * time.Second
+)
+
+// Sign returns the value of the X-Webhook-Signature header for a payload.
+// Receivers recompute HMAC-SHA256 over "<timestamp>.<body>" with the shared
+// secret and compare it in constant time.
+func Sign(secret string, timestamp int64, payload []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(payload)
+
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+// Dispatcher sends queued webhook deliveries and reschedules failed ones
+// with exponential backoff.
+type Dispatcher struct {
+	webhookRepo repository.WebhookRepository
+	client      *http.Client
+}
+
+func NewDispatcher(webhookRepo repository.WebhookRepository) *Dispatcher {
+	return &Dispatcher{
+		webhookRepo: webhookRepo,
+		client:      &http.Client{Timeout: requestTimeout},
+	}
+}
+
+// Run polls the delivery queue until ctx is cancelled. On cancellation it
+// stops before the next delivery, but finishes the one being sent, so that
+// its result is saved.
+func (d *Dispatcher) Run(ctx context.Context) {
+	ticker := time.NewTicker(pollInterval)
+	defer ticker.Stop()
+
+	for {
+		d.dispatchDue(ctx)
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (d *Dispatcher) dispatchDue(ctx context.Context) {
+	deliveries, err := d.webhookRepo.ClaimDueDeliveries(ctx, time.Now(), batchSize, leaseDuration)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to claim webhook deliveries", "error", err)
+
+		return
+	}
+
+	for _, delivery := range deliveries {
+		// 残りは lease が切れた後に再び配信される
+		if ctx.Err() != nil {
+			return
+		}
+
+		deliverCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), deliveryTimeout)
+		d.deliver(deliverCtx, delivery)
+		cancel()
+	}
+}
+
+func (d *Dispatcher) deliver(ctx context.Context, delivery *model.WebhookDelivery) {
+	webhook, err := d.webhookRepo.FindByID(ctx, delivery.Webhook)
+	if err != nil {
+		logging.FromContext(ctx).Error("failed to find webhook", "webhook", delivery.Webhook, "error", err)
+
+		return
+	}
+
+	delivery.UpdatedAt = time.Now()
+
+	// 無効化された Webhook には、無効化前に積まれた配信も送らない
+	if !webhook.Active {
+		delivery.Status = model.DeliveryFailed
+		delivery.LastError = model.ErrWebhookInactive.Error()
+		if err := d.webhookRepo.SaveDelivery(ctx, delivery); err != nil {
+			logging.FromContext(ctx).Error("failed to save webhook delivery", "delivery", delivery.ID, "error", err)
+		}
+
+		return
+	}
+
+	delivery.Attempts++
+	status, err := d.send(ctx, webhook, delivery)
+	delivery.ResponseStatus = status
+
+	switch {
+	case err == nil:
+		now := time.Now()
+		delivery.Status = model.DeliverySucceeded
+		delivery.LastError = ""
+		delivery.DeliveredAt = &now
+	case delivery.Attempts >= maxAttempts:
+		delivery.Status = model.DeliveryFailed
+		delivery.LastError = err.Error()
+	default:
+		delivery.LastError = err.Error()
+		delivery.NextAttemptAt = time.Now().Add(backoff(delivery.Attempts))
+	}
+
+	if err := d.webhookRepo.SaveDelivery(ctx, delivery); err != nil {
+		logging.FromContext(ctx).Error("failed to save webhook delivery", "delivery", delivery.ID, "error", err)
+	}
+}
+
+func (d *Dispatcher) send(ctx context.Context, webhook *model.Webhook, delivery *model.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("failed to create request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "h25s01-webhook/1.0")
+	req.Header.Set("X-Webhook-ID", webhook.ID.String())
+	req.Header.Set("X-Webhook-Delivery", delivery.ID.String())
+	req.Header.Set("X-Webhook-Event", string(delivery.Event))
+	req.Header.Set("X-Webhook-Timestamp", strconv.FormatInt(timestamp, 10))
+	req.Header.Set("X-Webhook-Signature", Sign(webhook.Secret, timestamp, delivery.Payload))
+
+	res, err := d.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to send request: %w", err)
+	}
+	defer res.Body.Close()
+
+	if res.StatusCode < 200 || res.StatusCode >= 300 {
+		body, _ := io.ReadAll(io.LimitReader(res.Body, maxErrorBodyBytes))
+
+		return res.StatusCode, fmt.Errorf("unexpected status %d: %s", res.StatusCode, body)
+	}
+
+	return res.StatusCode, nil
+}
+
+// backoff returns the delay before the next attempt: 30s, 1m, 2m, ... capped
+// at maxBackoff, with up to 10% jitter so retries do not arrive in bursts.
+func backoff(attempts int) time.Duration {
+	delay := baseBackoff << (attempts - 1)
+	if delay <= 0 || delay > maxBackoff {
+		delay = maxBackoff
+	}
+
+	return delay + rand.N(delay/10+1)
+}
@@ -0,0 +1,108 @@
+// Package logging sets up structured JSON logging with log/slog. Code that
+// serves a request takes its logger from the context, so that every line it
+// logs carries the request ID and the user.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"time"
+
+	"backend/internal/domain/model"
+
+	"github.com/google/uuid"
+	"github.com/labstack/echo/v4"
+	"go.opentelemetry.io/otel/trace"
+)
+
+type loggerContextKey struct{}
+
+// Setup installs a JSON logger writing to w as the default, at the given
+// level: debug, info, warn or error. Output of the standard log package goes
+// through it too.
+func Setup(w io.Writer, level string) error {
+	var lvl slog.Level
+	if err := lvl.UnmarshalText([]byte(level)); err != nil {
+		return fmt.Errorf("invalid log level %q: %w", level, err)
+	}
+
+	slog.SetDefault(slog.New(slog.NewJSONHandler(w, &slog.HandlerOptions{Level: lvl})))
+
+	return nil
+}
+
+func WithLogger(ctx context.Context, logger *slog.Logger) context.Context {
+	return context.WithValue(ctx, loggerContextKey{}, logger)
+}
+
+// FromContext returns the logger of the request ctx belongs to, or the
+// default logger outside of requests.
+func FromContext(ctx context.Context) *slog.Logger {
+	if logger, ok := ctx.Value(loggerContextKey{}).(*slog.Logger); ok {
+		return logger
+	}
+
+	return slog.Default()
+}
+
+// NewMiddleware gives each request an ID and a logger carrying it, and logs
+// the request when it finishes. The ID is taken from the X-Request-ID header
+// set by the proxy or the client, or generated, and returned in the same
+// header of the response.
+func NewMiddleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			req := c.Request()
+			id := req.Header.Get(echo.HeaderXRequestID)
+			if id == "" {
+				generated, err := uuid.NewV7()
+				if err != nil {
+					return err
+				}
+				id = generated.String()
+			}
+			c.Response().Header().Set(echo.HeaderXRequestID, id)
+
+			logger := slog.Default().With("request_id", id)
+			// トレースと突き合わせられるようにする
+			if span := trace.SpanContextFromContext(req.Context()); span.HasTraceID() {
+				logger = logger.With("trace_id", span.TraceID().String())
+			}
+			c.SetRequest(req.WithContext(WithLogger(req.Context(), logger)))
+
+			start := time.Now()
+			err := next(c)
+			if err != nil {
+				c.Error(err)
+			}
+
+			// ユーザーIDは後段のミドルウェアで設定される
+			ctx := c.Request().Context()
+			attrs := []any{
+				"method", req.Method,
+				"uri", req.RequestURI,
+				"route", c.Path(),
+				"status", c.Response().Status,
+				"latency", time.Since(start),
+				"remote_ip", c.RealIP(),
+				"bytes_out", c.Response().Size,
+			}
+			if user, ok := model.UserIDFromContext(ctx); ok {
+				attrs = append(attrs, "user", string(user))
+			}
+			if err != nil {
+				attrs = append(attrs, "error", err.Error())
+			}
+
+			level := slog.LevelInfo
+			if c.Response().Status >= 500 {
+				level = slog.LevelError
+			}
+			logger.Log(ctx, level, "request", attrs...)
+
+			return err
+		}
+	}
+}
@@ -0,0 +1,46 @@
+package metrics
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/labstack/echo/v4"
+)
+
+// Middleware records the duration of every request by its route template, so
+// that /shops/:id is one series rather than one per shop.
+func Middleware() echo.MiddlewareFunc {
+	return func(next echo.HandlerFunc) echo.HandlerFunc {
+		return func(c echo.Context) error {
+			start := time.Now()
+			err := next(c)
+
+			route := c.Path()
+			if route == "" {
+				route = "unmatched"
+			}
+			HTTPRequestDuration.
+				WithLabelValues(c.Request().Method, route, strconv.Itoa(responseStatus(c, err))).
+				Observe(time.Since(start).Seconds())
+
+			return err
+		}
+	}
+}
+
+// responseStatus returns the status the client gets. Errors returned by the
+// handler are only written by echo's error handler after the middleware.
+func responseStatus(c echo.Context, err error) int {
+	if err == nil || c.Response().Committed {
+		return c.Response().Status
+	}
+
+	var httpErr *echo.HTTPError
+	if errors.As(err, &httpErr) {
+		return httpErr.Code
+	}
+
+	return http.StatusInternalServerError
+}
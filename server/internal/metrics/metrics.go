@@ -0,0 +1,106 @@
+// Package metrics exposes the Prometheus metrics of the server: HTTP
+// requests, database and storage operations, the connection pool, and
+// business counters.
+package metrics
+
+import (
+	"database/sql"
+	"net/http"
+	"time"
+
+	"github.com/prometheus/client_golang/prometheus"
+	"github.com/prometheus/client_golang/prometheus/collectors"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
+)
+
+var registry = prometheus.NewRegistry()
+
+var (
+	HTTPRequestDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "http_request_duration_seconds",
+		Help:    "Duration of HTTP requests by route template and status.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method", "route", "status"})
+
+	DBQueryDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "db_query_duration_seconds",
+		Help:    "Duration of repository methods backed by the database.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"repository", "method"})
+
+	DBQueryErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "db_query_errors_total",
+		Help: "Repository methods backed by the database that failed, not counting not found.",
+	}, []string{"repository", "method"})
+
+	StorageOperationDuration = prometheus.NewHistogramVec(prometheus.HistogramOpts{
+		Name:    "storage_operation_duration_seconds",
+		Help:    "Duration of object storage operations.",
+		Buckets: prometheus.DefBuckets,
+	}, []string{"method"})
+
+	StorageOperationErrors = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "storage_operation_errors_total",
+		Help: "Object storage operations that failed.",
+	}, []string{"method"})
+
+	ReviewsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "reviews_created_total",
+		Help: "Reviews created.",
+	})
+
+	ReviewsHeld = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "reviews_held_total",
+		Help: "Reviews held for moderation by the content policy.",
+	})
+
+	ShopsCreated = prometheus.NewCounter(prometheus.CounterOpts{
+		Name: "shops_created_total",
+		Help: "Shops created.",
+	})
+
+	ImagesUploaded = prometheus.NewCounterVec(prometheus.CounterOpts{
+		Name: "images_uploaded_total",
+		Help: "Images uploaded by what they are attached to.",
+	}, []string{"target"})
+)
+
+func init() {
+	registry.MustRegister(
+		collectors.NewGoCollector(),
+		collectors.NewProcessCollector(collectors.ProcessCollectorOpts{}),
+		HTTPRequestDuration,
+		DBQueryDuration,
+		DBQueryErrors,
+		StorageOperationDuration,
+		StorageOperationErrors,
+		ReviewsCreated,
+		ReviewsHeld,
+		ShopsCreated,
+		ImagesUploaded,
+	)
+}
+
+// RegisterDB exports the connection pool statistics of db.
+func RegisterDB(db *sql.DB, name string) {
+	registry.MustRegister(collectors.NewDBStatsCollector(db, name))
+}
+
+// Handler serves the metrics in the Prometheus text format.
+func Handler() http.Handler {
+	return promhttp.HandlerFor(registry, promhttp.HandlerOpts{})
+}
+
+// NewServer returns a server exposing the metrics at /metrics on addr. It
+// listens apart from the API, so that only the port of the API has to be
+// published outside the cluster.
+func NewServer(addr string) *http.Server {
+	mux := http.NewServeMux()
+	mux.Handle("GET /metrics", Handler())
+
+	return &http.Server{
+		Addr:              addr,
+		Handler:           mux,
+		ReadHeaderTimeout: 10 * time.Second,
+	}
+}
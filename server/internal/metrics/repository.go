@@ -0,0 +1,603 @@
+package metrics
+
+import (
+	"context"
+	"errors"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/internal/domain/repository"
+	"backend/internal/logging"
+
+	"github.com/google/uuid"
+)
+
+// observeDB records the duration of a repository method and counts it as an
+// error when it fails. Not found is an expected result, not an error. Errors
+// are also logged with the logger of ctx, so that they can be traced back to
+// the request and the user.
+func observeDB(ctx context.Context, repo, method string, f func() error) error {
+	start := time.Now()
+	err := f()
+	DBQueryDuration.WithLabelValues(repo, method).Observe(time.Since(start).Seconds())
+	if err != nil && !errors.Is(err, repository.ErrNotFound) {
+		DBQueryErrors.WithLabelValues(repo, method).Inc()
+		logging.FromContext(ctx).ErrorContext(ctx, "repository method failed",
+			"repository", repo,
+			"method", method,
+			"error", err,
+		)
+	}
+
+	return err
+}
+
+func observeDBValue[T any](ctx context.Context, repo, method string, f func() (T, error)) (T, error) {
+	var v T
+	err := observeDB(ctx, repo, method, func() error {
+		var err error
+		v, err = f()
+
+		return err
+	})
+
+	return v, err
+}
+
+type shopRepository struct {
+	next repository.ShopRepository
+}
+
+// ShopRepository records the duration and errors of the methods of next.
+func ShopRepository(next repository.ShopRepository) repository.ShopRepository {
+	return &shopRepository{next: next}
+}
+
+func (r *shopRepository) Save(ctx context.Context, shop *model.Shop) error {
+	return observeDB(ctx, "shop", "Save", func() error {
+		return r.next.Save(ctx, shop)
+	})
+}
+
+func (r *shopRepository) UpdateStatus(ctx context.Context, shop *model.Shop) error {
+	return observeDB(ctx, "shop", "UpdateStatus", func() error {
+		return r.next.UpdateStatus(ctx, shop)
+	})
+}
+
+func (r *shopRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Shop, error) {
+	return observeDBValue(ctx, "shop", "FindByID", func() (*model.Shop, error) {
+		return r.next.FindByID(ctx, id)
+	})
+}
+
+func (r *shopRepository) FindAll(ctx context.Context) ([]*model.Shop, error) {
+	return observeDBValue(ctx, "shop", "FindAll", func() ([]*model.Shop, error) {
+		return r.next.FindAll(ctx)
+	})
+}
+
+func (r *shopRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return observeDB(ctx, "shop", "Delete", func() error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *shopRepository) FindByStation(ctx context.Context, id uuid.UUID) ([]*model.Shop, error) {
+	return observeDBValue(ctx, "shop", "FindByStation", func() ([]*model.Shop, error) {
+		return r.next.FindByStation(ctx, id)
+	})
+}
+
+func (r *shopRepository) SearchByName(ctx context.Context, keyword string, limit int) ([]*model.Shop, error) {
+	return observeDBValue(ctx, "shop", "SearchByName", func() ([]*model.Shop, error) {
+		return r.next.SearchByName(ctx, keyword, limit)
+	})
+}
+
+func (r *shopRepository) Iterate(ctx context.Context, fn func(*model.Shop) error) error {
+	return observeDB(ctx, "shop", "Iterate", func() error {
+		return r.next.Iterate(ctx, fn)
+	})
+}
+
+func (r *shopRepository) Merge(ctx context.Context, merge *model.ShopMerge, target *model.Shop) error {
+	return observeDB(ctx, "shop", "Merge", func() error {
+		return r.next.Merge(ctx, merge, target)
+	})
+}
+
+func (r *shopRepository) FindRedirect(ctx context.Context, id uuid.UUID) (uuid.UUID, error) {
+	return observeDBValue(ctx, "shop", "FindRedirect", func() (uuid.UUID, error) {
+		return r.next.FindRedirect(ctx, id)
+	})
+}
+
+type reviewRepository struct {
+	next repository.ReviewRepository
+}
+
+// ReviewRepository records the duration and errors of the methods of next.
+func ReviewRepository(next repository.ReviewRepository) repository.ReviewRepository {
+	return &reviewRepository{next: next}
+}
+
+func (r *reviewRepository) Save(ctx context.Context, review *model.Review) error {
+	return observeDB(ctx, "review", "Save", func() error {
+		return r.next.Save(ctx, review)
+	})
+}
+
+func (r *reviewRepository) SaveHeld(ctx context.Context, review *model.Review, report *model.ReviewReport) error {
+	return observeDB(ctx, "review", "SaveHeld", func() error {
+		return r.next.SaveHeld(ctx, review, report)
+	})
+}
+
+func (r *reviewRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Review, error) {
+	return observeDBValue(ctx, "review", "FindByID", func() (*model.Review, error) {
+		return r.next.FindByID(ctx, id)
+	})
+}
+
+func (r *reviewRepository) FindRecentReviews(
+	ctx context.Context,
+	filter repository.ReviewFilter,
+	cursor *model.ReviewCursor,
+	limit int,
+	offset int,
+) ([]*model.Review, error) {
+	return observeDBValue(ctx, "review", "FindRecentReviews", func() ([]*model.Review, error) {
+		return r.next.FindRecentReviews(ctx, filter, cursor, limit, offset)
+	})
+}
+
+func (r *reviewRepository) Count(ctx context.Context, filter repository.ReviewFilter) (int, error) {
+	return observeDBValue(ctx, "review", "Count", func() (int, error) {
+		return r.next.Count(ctx, filter)
+	})
+}
+
+func (r *reviewRepository) SummarizeRatings(ctx context.Context, shopIDs []uuid.UUID) (map[uuid.UUID]*model.RatingSummary, error) {
+	return observeDBValue(ctx, "review", "SummarizeRatings", func() (map[uuid.UUID]*model.RatingSummary, error) {
+		return r.next.SummarizeRatings(ctx, shopIDs)
+	})
+}
+
+func (r *reviewRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return observeDB(ctx, "review", "Delete", func() error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *reviewRepository) Iterate(ctx context.Context, fn func(*model.Review) error) error {
+	return observeDB(ctx, "review", "Iterate", func() error {
+		return r.next.Iterate(ctx, fn)
+	})
+}
+
+type stationRepository struct {
+	next repository.StationRepository
+}
+
+// StationRepository records the duration and errors of the methods of next.
+func StationRepository(next repository.StationRepository) repository.StationRepository {
+	return &stationRepository{next: next}
+}
+
+func (r *stationRepository) Save(ctx context.Context, station *model.Station) error {
+	return observeDB(ctx, "station", "Save", func() error {
+		return r.next.Save(ctx, station)
+	})
+}
+
+func (r *stationRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Station, error) {
+	return observeDBValue(ctx, "station", "FindByID", func() (*model.Station, error) {
+		return r.next.FindByID(ctx, id)
+	})
+}
+
+func (r *stationRepository) FindAll(ctx context.Context) ([]*model.Station, error) {
+	return observeDBValue(ctx, "station", "FindAll", func() ([]*model.Station, error) {
+		return r.next.FindAll(ctx)
+	})
+}
+
+func (r *stationRepository) FindByName(ctx context.Context, name string) (*model.Station, error) {
+	return observeDBValue(ctx, "station", "FindByName", func() (*model.Station, error) {
+		return r.next.FindByName(ctx, name)
+	})
+}
+
+func (r *stationRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return observeDB(ctx, "station", "Delete", func() error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *stationRepository) Iterate(ctx context.Context, fn func(*model.Station) error) error {
+	return observeDB(ctx, "station", "Iterate", func() error {
+		return r.next.Iterate(ctx, fn)
+	})
+}
+
+type userRepository struct {
+	next repository.UserRepository
+}
+
+// UserRepository records the duration and errors of the methods of next.
+func UserRepository(next repository.UserRepository) repository.UserRepository {
+	return &userRepository{next: next}
+}
+
+func (r *userRepository) Save(ctx context.Context, user *model.User) error {
+	return observeDB(ctx, "user", "Save", func() error {
+		return r.next.Save(ctx, user)
+	})
+}
+
+func (r *userRepository) FindByID(ctx context.Context, id model.UserID) (*model.User, error) {
+	return observeDBValue(ctx, "user", "FindByID", func() (*model.User, error) {
+		return r.next.FindByID(ctx, id)
+	})
+}
+
+func (r *userRepository) EnsureExists(ctx context.Context, id model.UserID) error {
+	return observeDB(ctx, "user", "EnsureExists", func() error {
+		return r.next.EnsureExists(ctx, id)
+	})
+}
+
+func (r *userRepository) GetProfile(ctx context.Context, id model.UserID, includeHidden bool) (*model.UserProfile, error) {
+	return observeDBValue(ctx, "user", "GetProfile", func() (*model.UserProfile, error) {
+		return r.next.GetProfile(ctx, id, includeHidden)
+	})
+}
+
+func (r *userRepository) Follow(ctx context.Context, follower model.UserID, followee model.UserID) error {
+	return observeDB(ctx, "user", "Follow", func() error {
+		return r.next.Follow(ctx, follower, followee)
+	})
+}
+
+func (r *userRepository) Unfollow(ctx context.Context, follower model.UserID, followee model.UserID) error {
+	return observeDB(ctx, "user", "Unfollow", func() error {
+		return r.next.Unfollow(ctx, follower, followee)
+	})
+}
+
+func (r *userRepository) AddFavoriteShop(ctx context.Context, id model.UserID, shopID uuid.UUID) error {
+	return observeDB(ctx, "user", "AddFavoriteShop", func() error {
+		return r.next.AddFavoriteShop(ctx, id, shopID)
+	})
+}
+
+func (r *userRepository) RemoveFavoriteShop(ctx context.Context, id model.UserID, shopID uuid.UUID) error {
+	return observeDB(ctx, "user", "RemoveFavoriteShop", func() error {
+		return r.next.RemoveFavoriteShop(ctx, id, shopID)
+	})
+}
+
+type eventRepository struct {
+	next repository.EventRepository
+}
+
+// EventRepository records the duration and errors of the methods of next.
+func EventRepository(next repository.EventRepository) repository.EventRepository {
+	return &eventRepository{next: next}
+}
+
+func (r *eventRepository) FindFeed(ctx context.Context, filter repository.EventFilter, cursor uuid.UUID, limit int) ([]*model.Event, error) {
+	return observeDBValue(ctx, "event", "FindFeed", func() ([]*model.Event, error) {
+		return r.next.FindFeed(ctx, filter, cursor, limit)
+	})
+}
+
+type webhookRepository struct {
+	next repository.WebhookRepository
+}
+
+// WebhookRepository records the duration and errors of the methods of next.
+func WebhookRepository(next repository.WebhookRepository) repository.WebhookRepository {
+	return &webhookRepository{next: next}
+}
+
+func (r *webhookRepository) Save(ctx context.Context, webhook *model.Webhook) error {
+	return observeDB(ctx, "webhook", "Save", func() error {
+		return r.next.Save(ctx, webhook)
+	})
+}
+
+func (r *webhookRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Webhook, error) {
+	return observeDBValue(ctx, "webhook", "FindByID", func() (*model.Webhook, error) {
+		return r.next.FindByID(ctx, id)
+	})
+}
+
+func (r *webhookRepository) FindAll(ctx context.Context) ([]*model.Webhook, error) {
+	return observeDBValue(ctx, "webhook", "FindAll", func() ([]*model.Webhook, error) {
+		return r.next.FindAll(ctx)
+	})
+}
+
+func (r *webhookRepository) FindSubscribers(ctx context.Context, event model.EventType) ([]*model.Webhook, error) {
+	return observeDBValue(ctx, "webhook", "FindSubscribers", func() ([]*model.Webhook, error) {
+		return r.next.FindSubscribers(ctx, event)
+	})
+}
+
+func (r *webhookRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return observeDB(ctx, "webhook", "Delete", func() error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *webhookRepository) EnqueueDeliveries(ctx context.Context, deliveries []*model.WebhookDelivery) error {
+	return observeDB(ctx, "webhook", "EnqueueDeliveries", func() error {
+		return r.next.EnqueueDeliveries(ctx, deliveries)
+	})
+}
+
+func (r *webhookRepository) ClaimDueDeliveries(ctx context.Context, now time.Time, limit int, lease time.Duration) ([]*model.WebhookDelivery, error) {
+	return observeDBValue(ctx, "webhook", "ClaimDueDeliveries", func() ([]*model.WebhookDelivery, error) {
+		return r.next.ClaimDueDeliveries(ctx, now, limit, lease)
+	})
+}
+
+func (r *webhookRepository) SaveDelivery(ctx context.Context, delivery *model.WebhookDelivery) error {
+	return observeDB(ctx, "webhook", "SaveDelivery", func() error {
+		return r.next.SaveDelivery(ctx, delivery)
+	})
+}
+
+func (r *webhookRepository) FindDeliveries(ctx context.Context, webhookID uuid.UUID, limit int) ([]*model.WebhookDelivery, error) {
+	return observeDBValue(ctx, "webhook", "FindDeliveries", func() ([]*model.WebhookDelivery, error) {
+		return r.next.FindDeliveries(ctx, webhookID, limit)
+	})
+}
+
+type menuItemRepository struct {
+	next repository.MenuItemRepository
+}
+
+// MenuItemRepository records the duration and errors of the methods of next.
+func MenuItemRepository(next repository.MenuItemRepository) repository.MenuItemRepository {
+	return &menuItemRepository{next: next}
+}
+
+func (r *menuItemRepository) Save(ctx context.Context, item *model.MenuItem) error {
+	return observeDB(ctx, "menu_item", "Save", func() error {
+		return r.next.Save(ctx, item)
+	})
+}
+
+func (r *menuItemRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.MenuItem, error) {
+	return observeDBValue(ctx, "menu_item", "FindByID", func() (*model.MenuItem, error) {
+		return r.next.FindByID(ctx, id)
+	})
+}
+
+func (r *menuItemRepository) FindByShop(ctx context.Context, shopID uuid.UUID) ([]*model.MenuItem, error) {
+	return observeDBValue(ctx, "menu_item", "FindByShop", func() ([]*model.MenuItem, error) {
+		return r.next.FindByShop(ctx, shopID)
+	})
+}
+
+func (r *menuItemRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return observeDB(ctx, "menu_item", "Delete", func() error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *menuItemRepository) FindRecommended(ctx context.Context, shopID uuid.UUID, limit int) ([]*model.RecommendedDish, error) {
+	return observeDBValue(ctx, "menu_item", "FindRecommended", func() ([]*model.RecommendedDish, error) {
+		return r.next.FindRecommended(ctx, shopID, limit)
+	})
+}
+
+type tagRepository struct {
+	next repository.TagRepository
+}
+
+// TagRepository records the duration and errors of the methods of next.
+func TagRepository(next repository.TagRepository) repository.TagRepository {
+	return &tagRepository{next: next}
+}
+
+func (r *tagRepository) Save(ctx context.Context, tag *model.Tag) error {
+	return observeDB(ctx, "tag", "Save", func() error {
+		return r.next.Save(ctx, tag)
+	})
+}
+
+func (r *tagRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.Tag, error) {
+	return observeDBValue(ctx, "tag", "FindByID", func() (*model.Tag, error) {
+		return r.next.FindByID(ctx, id)
+	})
+}
+
+func (r *tagRepository) FindByName(ctx context.Context, name model.TagName) (*model.Tag, error) {
+	return observeDBValue(ctx, "tag", "FindByName", func() (*model.Tag, error) {
+		return r.next.FindByName(ctx, name)
+	})
+}
+
+func (r *tagRepository) FindAll(ctx context.Context, filter repository.TagFilter) ([]*model.Tag, error) {
+	return observeDBValue(ctx, "tag", "FindAll", func() ([]*model.Tag, error) {
+		return r.next.FindAll(ctx, filter)
+	})
+}
+
+func (r *tagRepository) Delete(ctx context.Context, id uuid.UUID) error {
+	return observeDB(ctx, "tag", "Delete", func() error {
+		return r.next.Delete(ctx, id)
+	})
+}
+
+func (r *tagRepository) Merge(ctx context.Context, source uuid.UUID, target uuid.UUID) error {
+	return observeDB(ctx, "tag", "Merge", func() error {
+		return r.next.Merge(ctx, source, target)
+	})
+}
+
+type paymentMethodRepository struct {
+	next repository.PaymentMethodRepository
+}
+
+// PaymentMethodRepository records the duration and errors of the methods of next.
+func PaymentMethodRepository(next repository.PaymentMethodRepository) repository.PaymentMethodRepository {
+	return &paymentMethodRepository{next: next}
+}
+
+func (r *paymentMethodRepository) FindCatalog(ctx context.Context) (*model.PaymentMethodCatalog, error) {
+	return observeDBValue(ctx, "payment_method", "FindCatalog", func() (*model.PaymentMethodCatalog, error) {
+		return r.next.FindCatalog(ctx)
+	})
+}
+
+type closureReportRepository struct {
+	next repository.ClosureReportRepository
+}
+
+// ClosureReportRepository records the duration and errors of the methods of next.
+func ClosureReportRepository(next repository.ClosureReportRepository) repository.ClosureReportRepository {
+	return &closureReportRepository{next: next}
+}
+
+func (r *closureReportRepository) Save(ctx context.Context, report *model.ClosureReport) error {
+	return observeDB(ctx, "closure_report", "Save", func() error {
+		return r.next.Save(ctx, report)
+	})
+}
+
+func (r *closureReportRepository) FindPending(ctx context.Context, shopID uuid.UUID, since time.Time) ([]*model.ClosureReport, error) {
+	return observeDBValue(ctx, "closure_report", "FindPending", func() ([]*model.ClosureReport, error) {
+		return r.next.FindPending(ctx, shopID, since)
+	})
+}
+
+type editProposalRepository struct {
+	next repository.EditProposalRepository
+}
+
+// EditProposalRepository records the duration and errors of the methods of next.
+func EditProposalRepository(next repository.EditProposalRepository) repository.EditProposalRepository {
+	return &editProposalRepository{next: next}
+}
+
+func (r *editProposalRepository) Save(ctx context.Context, proposal *model.EditProposal) error {
+	return observeDB(ctx, "edit_proposal", "Save", func() error {
+		return r.next.Save(ctx, proposal)
+	})
+}
+
+func (r *editProposalRepository) FindByID(ctx context.Context, id uuid.UUID) (*model.EditProposal, error) {
+	return observeDBValue(ctx, "edit_proposal", "FindByID", func() (*model.EditProposal, error) {
+		return r.next.FindByID(ctx, id)
+	})
+}
+
+func (r *editProposalRepository) FindByShop(ctx context.Context, shopID uuid.UUID) ([]*model.EditProposal, error) {
+	return observeDBValue(ctx, "edit_proposal", "FindByShop", func() ([]*model.EditProposal, error) {
+		return r.next.FindByShop(ctx, shopID)
+	})
+}
+
+func (r *editProposalRepository) FindPending(ctx context.Context, registerer model.UserID) ([]*model.EditProposal, error) {
+	return observeDBValue(ctx, "edit_proposal", "FindPending", func() ([]*model.EditProposal, error) {
+		return r.next.FindPending(ctx, registerer)
+	})
+}
+
+func (r *editProposalRepository) Accept(ctx context.Context, proposal *model.EditProposal, shop *model.Shop, base time.Time) error {
+	return observeDB(ctx, "edit_proposal", "Accept", func() error {
+		return r.next.Accept(ctx, proposal, shop, base)
+	})
+}
+
+func (r *editProposalRepository) Reject(ctx context.Context, proposal *model.EditProposal) error {
+	return observeDB(ctx, "edit_proposal", "Reject", func() error {
+		return r.next.Reject(ctx, proposal)
+	})
+}
+
+type moderationRepository struct {
+	next repository.ModerationRepository
+}
+
+// ModerationRepository records the duration and errors of the methods of next.
+func ModerationRepository(next repository.ModerationRepository) repository.ModerationRepository {
+	return &moderationRepository{next: next}
+}
+
+func (r *moderationRepository) SaveReport(ctx context.Context, report *model.ReviewReport) error {
+	return observeDB(ctx, "moderation", "SaveReport", func() error {
+		return r.next.SaveReport(ctx, report)
+	})
+}
+
+func (r *moderationRepository) FindOpenReports(ctx context.Context) ([]*model.ReviewReport, error) {
+	return observeDBValue(ctx, "moderation", "FindOpenReports", func() ([]*model.ReviewReport, error) {
+		return r.next.FindOpenReports(ctx)
+	})
+}
+
+func (r *moderationRepository) Moderate(ctx context.Context, log *model.ModerationLog) error {
+	return observeDB(ctx, "moderation", "Moderate", func() error {
+		return r.next.Moderate(ctx, log)
+	})
+}
+
+func (r *moderationRepository) FindLogs(ctx context.Context, reviewID uuid.UUID, limit int, offset int) ([]*model.ModerationLog, error) {
+	return observeDBValue(ctx, "moderation", "FindLogs", func() ([]*model.ModerationLog, error) {
+		return r.next.FindLogs(ctx, reviewID, limit, offset)
+	})
+}
+
+type idempotencyRepository struct {
+	next repository.IdempotencyRepository
+}
+
+// IdempotencyRepository records the duration and errors of the methods of next.
+func IdempotencyRepository(next repository.IdempotencyRepository) repository.IdempotencyRepository {
+	return &idempotencyRepository{next: next}
+}
+
+func (r *idempotencyRepository) Reserve(ctx context.Context, record *model.IdempotencyRecord) (*model.IdempotencyRecord, error) {
+	return observeDBValue(ctx, "idempotency", "Reserve", func() (*model.IdempotencyRecord, error) {
+		return r.next.Reserve(ctx, record)
+	})
+}
+
+func (r *idempotencyRepository) Complete(ctx context.Context, record *model.IdempotencyRecord) error {
+	return observeDB(ctx, "idempotency", "Complete", func() error {
+		return r.next.Complete(ctx, record)
+	})
+}
+
+func (r *idempotencyRepository) Release(ctx context.Context, record *model.IdempotencyRecord) error {
+	return observeDB(ctx, "idempotency", "Release", func() error {
+		return r.next.Release(ctx, record)
+	})
+}
+
+type auditLogRepository struct {
+	next repository.AuditLogRepository
+}
+
+// AuditLogRepository records the duration and errors of the methods of next.
+func AuditLogRepository(next repository.AuditLogRepository) repository.AuditLogRepository {
+	return &auditLogRepository{next: next}
+}
+
+func (r *auditLogRepository) Save(ctx context.Context, log *model.AuditLog) error {
+	return observeDB(ctx, "audit_log", "Save", func() error {
+		return r.next.Save(ctx, log)
+	})
+}
+
+func (r *auditLogRepository) Find(ctx context.Context, filter repository.AuditLogFilter, limit int, offset int) ([]*model.AuditLog, error) {
+	return observeDBValue(ctx, "audit_log", "Find", func() ([]*model.AuditLog, error) {
+		return r.next.Find(ctx, filter, limit, offset)
+	})
+}
@@ -0,0 +1,65 @@
+package metrics
+
+import (
+	"context"
+	"io"
+	"time"
+
+	"backend/internal/domain/repository"
+
+	"github.com/google/uuid"
+)
+
+func observeStorage(method string, f func() error) error {
+	start := time.Now()
+	err := f()
+	StorageOperationDuration.WithLabelValues(method).Observe(time.Since(start).Seconds())
+	if err != nil {
+		StorageOperationErrors.WithLabelValues(method).Inc()
+	}
+
+	return err
+}
+
+type fileRepository struct {
+	next repository.FileRepository
+}
+
+// FileRepository records the duration and errors of the object storage
+// operations of next.
+func FileRepository(next repository.FileRepository) repository.FileRepository {
+	return &fileRepository{next: next}
+}
+
+func (r *fileRepository) UploadImage(ctx context.Context, contentType string, reader io.Reader) (uuid.UUID, error) {
+	var id uuid.UUID
+	err := observeStorage("UploadImage", func() error {
+		var err error
+		id, err = r.next.UploadImage(ctx, contentType, reader)
+
+		return err
+	})
+
+	return id, err
+}
+
+func (r *fileRepository) DeleteImage(ctx context.Context, fileID uuid.UUID) error {
+	return observeStorage("DeleteImage", func() error {
+		return r.next.DeleteImage(ctx, fileID)
+	})
+}
+
+// GetImage only measures until the object starts streaming, not the time the
+// caller takes to read it.
+func (r *fileRepository) GetImage(ctx context.Context, fileID uuid.UUID) (io.ReadCloser, string, error) {
+	var body io.ReadCloser
+	var contentType string
+	err := observeStorage("GetImage", func() error {
+		var err error
+		body, contentType, err = r.next.GetImage(ctx, fileID)
+
+		return err
+	})
+
+	return body, contentType, err
+}
@@ -0,0 +1,187 @@
+// Package ratelimit limits how fast and how much each user can use the API.
+// Requests are limited per route with token buckets, and creating reviews,
+// shops and images is limited per day with quotas.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"backend/internal/domain/model"
+	"backend/pkg/config"
+)
+
+// Bucket is a token bucket refilled at Rate tokens per second up to Burst
+// tokens. The zero value does not limit anything.
+type Bucket struct {
+	Rate  float64
+	Burst int
+}
+
+func (b Bucket) Unlimited() bool {
+	return b.Rate <= 0 || b.Burst <= 0
+}
+
+// ParseBucket parses a bucket written as "rate:burst". An empty string is an
+// unlimited bucket.
+func ParseBucket(s string) (Bucket, error) {
+	if s == "" {
+		return Bucket{}, nil
+	}
+
+	rate, burst, ok := strings.Cut(s, ":")
+	if !ok {
+		return Bucket{}, fmt.Errorf("invalid bucket %q: want rate:burst", s)
+	}
+	r, err := strconv.ParseFloat(strings.TrimSpace(rate), 64)
+	if err != nil {
+		return Bucket{}, fmt.Errorf("invalid bucket rate %q: %w", rate, err)
+	}
+	b, err := strconv.Atoi(strings.TrimSpace(burst))
+	if err != nil {
+		return Bucket{}, fmt.Errorf("invalid bucket burst %q: %w", burst, err)
+	}
+
+	return Bucket{Rate: r, Burst: b}, nil
+}
+
+// Store keeps the state of the limits. MemoryStore keeps it in the process;
+// replicas can share the limits through an implementation backed by a shared
+// store.
+type Store interface {
+	// Take removes a token from the bucket under key. When the bucket is
+	// empty it returns false and how long until the next token.
+	Take(ctx context.Context, key string, bucket Bucket, now time.Time) (bool, time.Duration, error)
+	// Add adds delta to the counter under key and returns the new value. The
+	// counter is reset to zero at resetAt.
+	Add(ctx context.Context, key string, delta int, resetAt time.Time) (int, error)
+}
+
+// Quota is a kind of write limited per day.
+type Quota string
+
+const (
+	QuotaReviews Quota = "reviews"
+	QuotaShops   Quota = "shops"
+	QuotaImages  Quota = "images"
+)
+
+type Limiter struct {
+	store  Store
+	read   Bucket
+	write  Bucket
+	routes map[string]Bucket
+	quotas map[Quota]int
+}
+
+func NewLimiter(store Store, cfg *config.RateLimitConfig) (*Limiter, error) {
+	read, err := ParseBucket(cfg.Read)
+	if err != nil {
+		return nil, fmt.Errorf("read limit: %w", err)
+	}
+	write, err := ParseBucket(cfg.Write)
+	if err != nil {
+		return nil, fmt.Errorf("write limit: %w", err)
+	}
+
+	routes := make(map[string]Bucket, len(cfg.Routes))
+	for _, r := range cfg.Routes {
+		route, bucket, ok := strings.Cut(r, "=")
+		if !ok {
+			return nil, fmt.Errorf("invalid route limit %q: want METHOD path=rate:burst", r)
+		}
+		method, path, ok := strings.Cut(strings.TrimSpace(route), " ")
+		if !ok {
+			return nil, fmt.Errorf("invalid route %q: want METHOD path", route)
+		}
+		b, err := ParseBucket(strings.TrimSpace(bucket))
+		if err != nil {
+			return nil, fmt.Errorf("route %s: %w", route, err)
+		}
+		routes[routeKey(method, strings.TrimSpace(path))] = b
+	}
+
+	return &Limiter{
+		store:  store,
+		read:   read,
+		write:  write,
+		routes: routes,
+		quotas: map[Quota]int{
+			QuotaReviews: cfg.ReviewsPerDay,
+			QuotaShops:   cfg.ShopsPerDay,
+			QuotaImages:  cfg.ImagesPerDay,
+		},
+	}, nil
+}
+
+// Allow takes a token from the bucket of userID for the route. Every route has
+// its own bucket, so a busy route does not block the others.
+func (l *Limiter) Allow(ctx context.Context, userID, method, path string, now time.Time) (bool, time.Duration, error) {
+	key := routeKey(method, path)
+	bucket, ok := l.routes[key]
+	if !ok {
+		bucket = l.write
+		if method == http.MethodGet || method == http.MethodHead {
+			bucket = l.read
+		}
+	}
+	if bucket.Unlimited() {
+		return true, 0, nil
+	}
+
+	return l.store.Take(ctx, "rate:"+userID+":"+key, bucket, now)
+}
+
+// Reserve counts a write toward today's quota of userID. When the quota is
+// used up it returns false and how long until the quota resets. Call Release
+// if the write does not happen after all.
+func (l *Limiter) Reserve(ctx context.Context, userID string, quota Quota, now time.Time) (bool, time.Duration, error) {
+	limit := l.quotas[quota]
+	if limit <= 0 {
+		return true, 0, nil
+	}
+
+	key, resetAt := quotaKey(userID, quota, now)
+	n, err := l.store.Add(ctx, key, 1, resetAt)
+	if err != nil {
+		return false, 0, err
+	}
+	if n > limit {
+		if _, err := l.store.Add(ctx, key, -1, resetAt); err != nil {
+			return false, 0, err
+		}
+
+		return false, resetAt.Sub(now), nil
+	}
+
+	return true, 0, nil
+}
+
+// Release gives back a write reserved at now.
+func (l *Limiter) Release(ctx context.Context, userID string, quota Quota, now time.Time) error {
+	if l.quotas[quota] <= 0 {
+		return nil
+	}
+
+	key, resetAt := quotaKey(userID, quota, now)
+	_, err := l.store.Add(ctx, key, -1, resetAt)
+
+	return err
+}
+
+func routeKey(method, path string) string {
+	return strings.ToUpper(method) + " " + path
+}
+
+// quotaKey returns the counter key of the day of now and when the day ends.
+// Days are counted in Asia/Tokyo.
+func quotaKey(userID string, quota Quota, now time.Time) (string, time.Time) {
+	t := now.In(model.Tokyo)
+	day := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, model.Tokyo)
+
+	return "quota:" + string(quota) + ":" + userID + ":" + day.Format(time.DateOnly), day.AddDate(0, 0, 1)
+}
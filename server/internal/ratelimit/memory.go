@@ -0,0 +1,104 @@
+package ratelimit
+
+import (
+	"context"
+	"sync"
+	"time"
+)
+
+// sweepInterval is how often MemoryStore drops state that no longer limits
+// anything.
+const sweepInterval = time.Minute
+
+type bucketState struct {
+	tokens float64
+	last   time.Time
+	// full is when the bucket is full again if nothing is taken.
+	full time.Time
+}
+
+type counterState struct {
+	value   int
+	resetAt time.Time
+}
+
+// MemoryStore keeps the limits in the memory of the process. Each replica
+// limits the requests it receives on its own.
+type MemoryStore struct {
+	mu        sync.Mutex
+	buckets   map[string]*bucketState
+	counters  map[string]*counterState
+	lastSweep time.Time
+}
+
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		buckets:  make(map[string]*bucketState),
+		counters: make(map[string]*counterState),
+	}
+}
+
+func (s *MemoryStore) Take(_ context.Context, key string, bucket Bucket, now time.Time) (bool, time.Duration, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.sweep(now)
+
+	burst := float64(bucket.Burst)
+	state, ok := s.buckets[key]
+	if !ok {
+		state = &bucketState{tokens: burst, last: now}
+		s.buckets[key] = state
+	}
+
+	if elapsed := now.Sub(state.last).Seconds(); elapsed > 0 {
+		state.tokens = min(burst, state.tokens+elapsed*bucket.Rate)
+		state.last = now
+	}
+
+	if state.tokens < 1 {
+		wait := time.Duration((1 - state.tokens) / bucket.Rate * float64(time.Second))
+
+		return false, wait, nil
+	}
+
+	state.tokens--
+	state.full = now.Add(time.Duration((burst - state.tokens) / bucket.Rate * float64(time.Second)))
+
+	return true, 0, nil
+}
+
+func (s *MemoryStore) Add(_ context.Context, key string, delta int, resetAt time.Time) (int, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	now := time.Now()
+	s.sweep(now)
+
+	state, ok := s.counters[key]
+	if !ok || !now.Before(state.resetAt) {
+		state = &counterState{resetAt: resetAt}
+		s.counters[key] = state
+	}
+	state.value = max(0, state.value+delta)
+
+	return state.value, nil
+}
+
+// sweep drops full buckets and expired counters. It must be called with mu
+// held.
+func (s *MemoryStore) sweep(now time.Time) {
+	if now.Sub(s.lastSweep) < sweepInterval {
+		return
+	}
+	s.lastSweep = now
+
+	for key, state := range s.buckets {
+		if !now.Before(state.full) {
+			delete(s.buckets, key)
+		}
+	}
+	for key, state := range s.counters {
+		if !now.Before(state.resetAt) {
+			delete(s.counters, key)
+		}
+	}
+}
@@ -1,9 +1,10 @@
 package router
 
 import (
-	"net/http"
-
 	"backend/internal/handler"
+	"backend/internal/logging"
+	"backend/internal/metrics"
+	"backend/internal/ratelimit"
 
 	"github.com/labstack/echo/v4"
 	"github.com/labstack/echo/v4/middleware"
@@ -14,24 +15,67 @@ func NewRouter(
 	reviewHandler *handler.ReviewHandler,
 	stationHandler *handler.StationHandler,
 	fileHandler *handler.FileHandler,
+	exportHandler *handler.ExportHandler,
+	userHandler *handler.UserHandler,
+	feedHandler *handler.FeedHandler,
+	webhookHandler *handler.WebhookHandler,
+	botHandler *handler.BotHandler,
+	streamHandler *handler.StreamHandler,
+	menuHandler *handler.MenuHandler,
+	tagHandler *handler.TagHandler,
+	paymentMethodHandler *handler.PaymentMethodHandler,
+	shopStatusHandler *handler.ShopStatusHandler,
+	editProposalHandler *handler.EditProposalHandler,
+	moderationHandler *handler.ModerationHandler,
+	auditHandler *handler.AuditHandler,
+	healthHandler *handler.HealthHandler,
+	userIDMiddleware echo.MiddlewareFunc,
+	adminMiddleware echo.MiddlewareFunc,
+	rateLimitMiddleware echo.MiddlewareFunc,
+	quotaMiddleware func(ratelimit.Quota) echo.MiddlewareFunc,
+	idempotencyMiddleware echo.MiddlewareFunc,
+	tracingMiddleware echo.MiddlewareFunc,
 ) *echo.Echo {
 	e := echo.New()
+	// 起動時のメッセージも構造化ログで出す
+	e.HideBanner = true
+	e.HidePort = true
+
+	e.Use(tracingMiddleware)
+	e.Use(logging.NewMiddleware())
+	e.Use(metrics.Middleware())
+	e.Use(middleware.RecoverWithConfig(middleware.RecoverConfig{
+		LogErrorFunc: func(c echo.Context, err error, stack []byte) error {
+			logging.FromContext(c.Request().Context()).Error("panic recovered", "error", err, "stack", string(stack))
 
-	e.Use(middleware.Logger())
-	e.Use(middleware.Recover())
-	e.Use(middleware.CORS())
+			return err
+		},
+	}))
+	e.Use(middleware.CORSWithConfig(middleware.CORSConfig{
+		// 一覧APIのページ情報と、再試行に関するヘッダーをブラウザから読めるようにする
+		ExposeHeaders: []string{"Link", "Retry-After", "Idempotent-Replayed"},
+	}))
 
-	e.Use(handler.UserIDMiddleware)
+	e.GET("/livez", healthHandler.Livez)
+	e.GET("/readyz", healthHandler.Readyz)
+	// 既存のヘルスチェック設定のために残している
+	e.GET("/health", healthHandler.Livez)
 
-	e.GET("/health", func(c echo.Context) error {
-		return c.JSON(http.StatusOK, map[string]string{
-			"status": "ok",
-		})
-	})
+	// メトリクスは公開するポートでは配信せず、metrics.NewServer で別のポートから配信する
+
+	// traQからのBotイベントはユーザー認証を通らないため、api グループの外で
+	// 登録して検証トークンで認証する
+	e.POST("/api/v1/bot/traq", botHandler.HandleTraqEvent)
 
 	api := e.Group("/api/v1")
-	api.Use(handler.UserIDMiddleware)
+	api.Use(userIDMiddleware, rateLimitMiddleware)
 	{
+		// 作成系のAPIは Idempotency-Key で再試行でき、1日あたりの上限を設ける。
+		// 再送がクォータを消費しないよう、Idempotency-Key の確認を先に行う
+		reviewQuota := quotaMiddleware(ratelimit.QuotaReviews)
+		shopQuota := quotaMiddleware(ratelimit.QuotaShops)
+		imageQuota := quotaMiddleware(ratelimit.QuotaImages)
+
 		images := api.Group("/images")
 		{
 			images.GET("/:id", fileHandler.GetImage)
@@ -40,22 +84,35 @@ func NewRouter(
 		shops := api.Group("/shops")
 		{
 			shops.GET("", shopHandler.GetShops)
-			shops.POST("", shopHandler.CreateShop)
+			shops.POST("", shopHandler.CreateShop, idempotencyMiddleware, shopQuota)
+			shops.GET("/duplicates", shopHandler.GetDuplicateShops, adminMiddleware)
 			shops.GET("/:id", shopHandler.GetShopDetail)
 			shops.PUT("/:id", shopHandler.UpdateShop)
 			shops.DELETE("/:id", shopHandler.Delete)
-			shops.POST("/:id/images", shopHandler.ShopImgUpload)
+			shops.POST("/:id/images", shopHandler.ShopImgUpload, idempotencyMiddleware, imageQuota)
 			shops.DELETE("/:id/images", shopHandler.DeletePicture)
+			shops.GET("/:id/menu", menuHandler.GetMenu)
+			shops.POST("/:id/menu", menuHandler.CreateMenuItem)
+			shops.PUT("/:id/menu/:itemId", menuHandler.UpdateMenuItem)
+			shops.DELETE("/:id/menu/:itemId", menuHandler.DeleteMenuItem)
+			shops.POST("/:id/menu/:itemId/image", menuHandler.UploadMenuItemImage, idempotencyMiddleware, imageQuota)
+			shops.GET("/:id/closure-reports", shopStatusHandler.GetClosureReports)
+			shops.POST("/:id/closure-reports", shopStatusHandler.CreateClosureReport)
+			shops.PUT("/:id/status", shopStatusHandler.UpdateShopStatus, adminMiddleware)
+			shops.POST("/:id/merge", shopHandler.MergeShop, adminMiddleware)
+			shops.GET("/:id/edit-proposals", editProposalHandler.GetShopEditProposals)
+			shops.POST("/:id/edit-proposals", editProposalHandler.CreateEditProposal)
 		}
 
 		reviews := api.Group("/reviews")
 		{
 			reviews.GET("", reviewHandler.GetReviews)
-			reviews.POST("", reviewHandler.CreateReview)
+			reviews.POST("", reviewHandler.CreateReview, idempotencyMiddleware, reviewQuota)
 			reviews.GET("/:id", reviewHandler.GetReview)
 			reviews.PUT("/:id", reviewHandler.UpdateReview)
 			reviews.DELETE("/:id", reviewHandler.DeleteReview)
-			reviews.POST("/:id/images", reviewHandler.UploadImage)
+			reviews.POST("/:id/images", reviewHandler.UploadImage, idempotencyMiddleware, imageQuota)
+			reviews.POST("/:id/reports", moderationHandler.CreateReviewReport)
 		}
 
 		stations := api.Group("/stations")
@@ -67,6 +124,70 @@ func NewRouter(
 			stations.DELETE("/:id", stationHandler.DeleteStation)
 			stations.GET("/:id/shops", stationHandler.GetShopAroundStation)
 		}
+
+		tags := api.Group("/tags")
+		{
+			tags.GET("", tagHandler.GetTags)
+			tags.POST("", tagHandler.CreateTag)
+			tags.GET("/:id", tagHandler.GetTag)
+			// カテゴリの管理とタグの統合・改名は管理者のみ
+			tags.POST("/categories", tagHandler.CreateCategory, adminMiddleware)
+			tags.PUT("/:id", tagHandler.UpdateTag, adminMiddleware)
+			tags.DELETE("/:id", tagHandler.DeleteTag, adminMiddleware)
+			tags.POST("/:id/merge", tagHandler.MergeTag, adminMiddleware)
+		}
+
+		api.GET("/payment-methods", paymentMethodHandler.GetPaymentMethods)
+
+		// 承認・却下できるのは店舗の登録者と管理者
+		editProposals := api.Group("/edit-proposals")
+		{
+			editProposals.GET("", editProposalHandler.GetEditProposalQueue)
+			editProposals.GET("/:id", editProposalHandler.GetEditProposal)
+			editProposals.POST("/:id/accept", editProposalHandler.AcceptEditProposal)
+			editProposals.POST("/:id/reject", editProposalHandler.RejectEditProposal)
+		}
+
+		users := api.Group("/users")
+		{
+			users.GET("/me", userHandler.GetMe)
+			users.PUT("/me", userHandler.UpdateMe)
+			users.POST("/me/avatar", userHandler.UploadAvatar, idempotencyMiddleware, imageQuota)
+			users.PUT("/me/favorites/:shopId", userHandler.AddFavoriteShop)
+			users.DELETE("/me/favorites/:shopId", userHandler.RemoveFavoriteShop)
+			users.GET("/:id", userHandler.GetUser)
+			users.PUT("/:id/follow", userHandler.Follow)
+			users.DELETE("/:id/follow", userHandler.Unfollow)
+		}
+
+		api.GET("/feed", feedHandler.GetFeed)
+		api.GET("/stream", streamHandler.Stream)
+
+		exports := api.Group("/export")
+		{
+			exports.GET("/shops", exportHandler.ExportShops)
+			exports.GET("/reviews", exportHandler.ExportReviews)
+			exports.GET("/stations", exportHandler.ExportStations)
+		}
+
+		moderation := api.Group("/moderation", adminMiddleware)
+		{
+			moderation.GET("/reports", moderationHandler.GetModerationQueue)
+			moderation.POST("/reviews/:id/actions", moderationHandler.ModerateReview)
+			moderation.GET("/logs", moderationHandler.GetModerationLogs)
+		}
+
+		api.GET("/audit-logs", auditHandler.GetAuditLogs, adminMiddleware)
+
+		webhooks := api.Group("/webhooks", adminMiddleware)
+		{
+			webhooks.GET("", webhookHandler.GetWebhooks)
+			webhooks.POST("", webhookHandler.CreateWebhook)
+			webhooks.GET("/:id", webhookHandler.GetWebhook)
+			webhooks.PUT("/:id", webhookHandler.UpdateWebhook)
+			webhooks.DELETE("/:id", webhookHandler.DeleteWebhook)
+			webhooks.GET("/:id/deliveries", webhookHandler.GetDeliveries)
+		}
 	}
 
 	return e
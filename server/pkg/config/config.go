@@ -3,6 +3,9 @@ package config
 import (
 	"fmt"
 	"os"
+	"strconv"
+	"strings"
+	"time"
 
 	"github.com/go-sql-driver/mysql"
 )
@@ -16,6 +19,51 @@ type AWSConfig struct {
 	PathStyle  bool
 }
 
+type ReviewPolicyConfig struct {
+	// レビュー本文の最大文字数（バイト数ではない）
+	MaxChars int
+	// NGワードの一覧と、1行に1語を書いたファイルのパス
+	NGWords     []string
+	NGWordsFile string
+	// NGワードを含む場合の扱い（reject または queue）
+	NGWordAction string
+	// 同じ文字の連続とURLの数の上限
+	MaxRepeatedChars int
+	MaxURLs          int
+	// 連投・URLスパムの場合の扱い（reject または queue）
+	SpamAction string
+}
+
+type RateLimitConfig struct {
+	// 読み取り（GET）と書き込みリクエストの既定のトークンバケット。
+	// "毎秒の補充数:容量" の形式で、空にすると制限しない
+	Read  string
+	Write string
+	// ルートごとの上書き。"METHOD /api/v1/path=毎秒の補充数:容量" の形式
+	Routes []string
+	// ユーザーごとの1日あたりの作成数の上限。0以下で無制限
+	ReviewsPerDay int
+	ShopsPerDay   int
+	ImagesPerDay  int
+}
+
+type TracingConfig struct {
+	// otlp, stdout のいずれか。空にするとトレースを送らない
+	Exporter string
+	// OTLP/HTTP の送信先（例: http://localhost:4318）
+	Endpoint    string
+	ServiceName string
+	// 記録するリクエストの割合（0〜1）
+	SampleRatio float64
+}
+
+type TraqBotConfig struct {
+	// Botのイベント受信時に X-TRAQ-BOT-TOKEN ヘッダーと照合する
+	VerificationToken string
+	AccessToken       string
+	APIBaseURL        string
+}
+
 func getEnv(key, defaultValue string) string {
 	v, ok := os.LookupEnv(key)
 	if !ok {
@@ -25,10 +73,140 @@ func getEnv(key, defaultValue string) string {
 	return v
 }
 
+func getEnvInt(key string, defaultValue int) int {
+	v, err := strconv.Atoi(getEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+
+	return v
+}
+
+func getEnvDuration(key string, defaultValue time.Duration) time.Duration {
+	v, err := time.ParseDuration(getEnv(key, ""))
+	if err != nil {
+		return defaultValue
+	}
+
+	return v
+}
+
+func getEnvList(key string) []string {
+	var values []string
+	for _, v := range strings.Split(getEnv(key, ""), ",") {
+		if v = strings.TrimSpace(v); v != "" {
+			values = append(values, v)
+		}
+	}
+
+	return values
+}
+
 func AppAddr() string {
 	return getEnv("APP_ADDR", ":8080")
 }
 
+// MetricsAddr returns the address serving the Prometheus metrics. It must be
+// a port not published outside the cluster, since the metrics need no auth.
+func MetricsAddr() string {
+	return getEnv("METRICS_ADDR", ":9090")
+}
+
+// LogLevel returns the minimum level of the logs written: debug, info, warn
+// or error.
+func LogLevel() string {
+	return getEnv("LOG_LEVEL", "info")
+}
+
+// AdminUsers returns the traQ IDs allowed to use the admin APIs.
+func AdminUsers() []string {
+	return getEnvList("ADMIN_USERS")
+}
+
+// ClosureReportThreshold returns how many distinct users must report the same
+// closure before the status of a shop changes automatically.
+func ClosureReportThreshold() int {
+	const defaultThreshold = 3
+	if v := getEnvInt("CLOSURE_REPORT_THRESHOLD", defaultThreshold); v > 0 {
+		return v
+	}
+
+	return defaultThreshold
+}
+
+// ReviewPolicy returns the content policy applied to review content.
+func ReviewPolicy() *ReviewPolicyConfig {
+	return &ReviewPolicyConfig{
+		MaxChars:         getEnvInt("REVIEW_MAX_CHARS", 2000),
+		NGWords:          getEnvList("REVIEW_NG_WORDS"),
+		NGWordsFile:      getEnv("REVIEW_NG_WORDS_FILE", ""),
+		NGWordAction:     getEnv("REVIEW_NG_WORD_ACTION", "reject"),
+		MaxRepeatedChars: getEnvInt("REVIEW_MAX_REPEATED_CHARS", 15),
+		MaxURLs:          getEnvInt("REVIEW_MAX_URLS", 2),
+		SpamAction:       getEnv("REVIEW_SPAM_ACTION", "queue"),
+	}
+}
+
+// RateLimit returns the per-user request rate limits and daily write quotas.
+func RateLimit() *RateLimitConfig {
+	return &RateLimitConfig{
+		Read:          getEnv("RATE_LIMIT_READ", "10:50"),
+		Write:         getEnv("RATE_LIMIT_WRITE", "1:20"),
+		Routes:        getEnvList("RATE_LIMIT_ROUTES"),
+		ReviewsPerDay: getEnvInt("QUOTA_REVIEWS_PER_DAY", 50),
+		ShopsPerDay:   getEnvInt("QUOTA_SHOPS_PER_DAY", 20),
+		ImagesPerDay:  getEnvInt("QUOTA_IMAGES_PER_DAY", 200),
+	}
+}
+
+// IdempotencyKeyTTL returns how long the response to a request with an
+// Idempotency-Key header is replayed to retries.
+func IdempotencyKeyTTL() time.Duration {
+	const defaultTTL = 24 * time.Hour
+	if v := getEnvDuration("IDEMPOTENCY_KEY_TTL", defaultTTL); v > 0 {
+		return v
+	}
+
+	return defaultTTL
+}
+
+// HealthCheckTimeout returns how long each check of the liveness and
+// readiness probes may take before it counts as failed.
+func HealthCheckTimeout() time.Duration {
+	const defaultTimeout = 2 * time.Second
+	if v := getEnvDuration("HEALTH_CHECK_TIMEOUT", defaultTimeout); v > 0 {
+		return v
+	}
+
+	return defaultTimeout
+}
+
+// ShutdownTimeout returns how long the server waits for in-flight requests
+// on SIGTERM, and then for the remaining traces to be sent, before exiting.
+func ShutdownTimeout() time.Duration {
+	const defaultTimeout = 10 * time.Second
+	if v := getEnvDuration("SHUTDOWN_TIMEOUT", defaultTimeout); v > 0 {
+		return v
+	}
+
+	return defaultTimeout
+}
+
+// Tracing returns where to export OpenTelemetry traces.
+func Tracing() *TracingConfig {
+	sampleRatio, err := strconv.ParseFloat(getEnv("TRACING_SAMPLE_RATIO", "1"), 64)
+	if err != nil || sampleRatio < 0 || sampleRatio > 1 {
+		sampleRatio = 1
+	}
+
+	return &TracingConfig{
+		Exporter:    getEnv("TRACING_EXPORTER", ""),
+		Endpoint:    getEnv("OTEL_EXPORTER_OTLP_ENDPOINT", "http://localhost:4318"),
+		ServiceName: getEnv("OTEL_SERVICE_NAME", "backend"),
+		SampleRatio: sampleRatio,
+	}
+}
+
 func AWS() *AWSConfig {
 	pathStyleStr := getEnv("AWS_S3_FORCE_PATH_STYLE", "false")
 	pathStyle := pathStyleStr == "true"
@@ -43,6 +221,14 @@ func AWS() *AWSConfig {
 	}
 }
 
+func TraqBot() *TraqBotConfig {
+	return &TraqBotConfig{
+		VerificationToken: getEnv("TRAQ_BOT_VERIFICATION_TOKEN", ""),
+		AccessToken:       getEnv("TRAQ_BOT_ACCESS_TOKEN", ""),
+		APIBaseURL:        getEnv("TRAQ_API_BASE_URL", "https://q.trap.jp/api/v3"),
+	}
+}
+
 func MySQL() *mysql.Config {
 	c := mysql.NewConfig()
 
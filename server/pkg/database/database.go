@@ -1,15 +1,25 @@
 package database
 
 import (
+	"github.com/XSAM/otelsql"
 	"github.com/go-sql-driver/mysql"
 	"github.com/jmoiron/sqlx"
+	semconv "go.opentelemetry.io/otel/semconv/v1.26.0"
 )
 
 func Setup(mysqlConfig *mysql.Config) (*sqlx.DB, error) {
-	db, err := sqlx.Connect("mysql", mysqlConfig.FormatDSN())
+	// クエリごとにスパンを作るため、計装したドライバーで接続する
+	sqlDB, err := otelsql.Open("mysql", mysqlConfig.FormatDSN(),
+		otelsql.WithAttributes(semconv.DBSystemMySQL),
+		otelsql.WithSpanOptions(otelsql.SpanOptions{
+			OmitConnResetSession: true,
+			OmitRows:             true,
+		}),
+	)
 	if err != nil {
 		return nil, err
 	}
+	db := sqlx.NewDb(sqlDB, "mysql")
 
 	if err := db.Ping(); err != nil {
 		return nil, err
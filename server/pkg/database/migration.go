@@ -1,9 +1,14 @@
 package database
 
 import (
+	"context"
 	"database/sql"
 	"embed"
 	"fmt"
+	"io/fs"
+
+	// Go migrations register themselves with goose on import
+	_ "backend/pkg/database/migrations"
 
 	"github.com/pressly/goose/v3"
 )
@@ -24,3 +29,37 @@ func migrateTables(db *sql.DB) error {
 
 	return nil
 }
+
+// MigrationChecker reports whether the database has every migration of this
+// build applied. A database migrated further by a newer build still passes.
+type MigrationChecker struct {
+	provider *goose.Provider
+}
+
+func NewMigrationChecker(db *sql.DB) (*MigrationChecker, error) {
+	migrations, err := fs.Sub(embedMigrations, "migrations")
+	if err != nil {
+		return nil, fmt.Errorf("open migrations: %w", err)
+	}
+
+	provider, err := goose.NewProvider(goose.DialectMySQL, db, migrations)
+	if err != nil {
+		return nil, fmt.Errorf("create migration provider: %w", err)
+	}
+
+	return &MigrationChecker{
+		provider: provider,
+	}, nil
+}
+
+func (m *MigrationChecker) Check(ctx context.Context) error {
+	current, target, err := m.provider.GetVersions(ctx)
+	if err != nil {
+		return fmt.Errorf("get migration versions: %w", err)
+	}
+	if current < target {
+		return fmt.Errorf("database is at migration %d, want %d", current, target)
+	}
+
+	return nil
+}
@@ -0,0 +1,6 @@
+-- +goose up
+-- カーソルページネーション用のインデックス
+CREATE INDEX idx_reviews_created_at_id ON reviews (created_at, id);
+
+-- +goose down
+DROP INDEX idx_reviews_created_at_id ON reviews;
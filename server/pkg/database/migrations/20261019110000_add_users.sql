@@ -0,0 +1,20 @@
+-- +goose up
+-- users テーブル
+CREATE TABLE users (
+  id VARCHAR(255) PRIMARY KEY,
+  display_name VARCHAR(255) NOT NULL,
+  avatar_image_id VARCHAR(255),
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- 既存の投稿者・登録者を取り込む
+INSERT IGNORE INTO users (id, display_name)
+SELECT DISTINCT author, author FROM reviews;
+
+INSERT IGNORE INTO users (id, display_name)
+SELECT DISTINCT registerer, registerer FROM shops
+WHERE registerer IS NOT NULL AND registerer <> '';
+
+-- +goose down
+DROP TABLE IF EXISTS users;
@@ -0,0 +1,37 @@
+-- +goose up
+-- events テーブル（アクティビティのアウトボックス）
+CREATE TABLE events (
+  id VARCHAR(255) PRIMARY KEY,
+  type VARCHAR(64) NOT NULL,
+  actor VARCHAR(255),
+  shop_id VARCHAR(255),
+  review_id VARCHAR(255),
+  station_id VARCHAR(255),
+  image_id VARCHAR(255),
+  payload JSON,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_events_shop_id (shop_id),
+  INDEX idx_events_station_id (station_id),
+  INDEX idx_events_actor (actor)
+);
+
+-- user_follows テーブル
+CREATE TABLE user_follows (
+  follower VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  followee VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (follower, followee)
+);
+
+-- favorite_shops テーブル
+CREATE TABLE favorite_shops (
+  user_id VARCHAR(255) NOT NULL REFERENCES users(id) ON DELETE CASCADE,
+  shop_id VARCHAR(255) NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (user_id, shop_id)
+);
+
+-- +goose down
+DROP TABLE IF EXISTS favorite_shops;
+DROP TABLE IF EXISTS user_follows;
+DROP TABLE IF EXISTS events;
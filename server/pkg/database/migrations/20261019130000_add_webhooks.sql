@@ -0,0 +1,42 @@
+-- +goose up
+-- webhooks テーブル
+CREATE TABLE webhooks (
+  id VARCHAR(255) PRIMARY KEY,
+  url TEXT NOT NULL,
+  secret VARCHAR(255) NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_by VARCHAR(255),
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP
+);
+
+-- webhook_events 中間テーブル
+CREATE TABLE webhook_events (
+  webhook_id VARCHAR(255) NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event_type VARCHAR(64) NOT NULL,
+  PRIMARY KEY (webhook_id, event_type)
+);
+
+-- webhook_deliveries テーブル（配信キュー兼配信ログ）
+-- payloadは署名した内容をそのまま再送するためJSON型ではなくTEXTで保存する
+CREATE TABLE webhook_deliveries (
+  id VARCHAR(255) PRIMARY KEY,
+  webhook_id VARCHAR(255) NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+  event_type VARCHAR(64) NOT NULL,
+  payload MEDIUMTEXT NOT NULL,
+  status VARCHAR(16) NOT NULL,
+  attempts INTEGER NOT NULL DEFAULT 0,
+  next_attempt_at TIMESTAMP NULL,
+  last_error TEXT,
+  response_status INTEGER,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  delivered_at TIMESTAMP NULL,
+  INDEX idx_webhook_deliveries_due (status, next_attempt_at),
+  INDEX idx_webhook_deliveries_webhook (webhook_id, created_at)
+);
+
+-- +goose down
+DROP TABLE IF EXISTS webhook_deliveries;
+DROP TABLE IF EXISTS webhook_events;
+DROP TABLE IF EXISTS webhooks;
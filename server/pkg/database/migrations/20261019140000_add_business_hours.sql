@@ -0,0 +1,24 @@
+-- +goose up
+-- shop_business_hours テーブル
+-- 営業時間は Asia/Tokyo の曜日と0時からの分で表す。日をまたぐ営業は close_minute が1440を超える
+CREATE TABLE shop_business_hours (
+  shop_id VARCHAR(255) NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  weekday TINYINT NOT NULL CHECK (weekday >= 0 AND weekday <= 6),
+  open_minute SMALLINT NOT NULL,
+  close_minute SMALLINT NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (shop_id, weekday, open_minute)
+);
+
+-- shop_closures テーブル（臨時休業日）
+CREATE TABLE shop_closures (
+  shop_id VARCHAR(255) NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  date DATE NOT NULL,
+  note VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (shop_id, date)
+);
+
+-- +goose down
+DROP TABLE IF EXISTS shop_closures;
+DROP TABLE IF EXISTS shop_business_hours;
@@ -0,0 +1,27 @@
+-- +goose up
+-- menu_items テーブル
+CREATE TABLE menu_items (
+  id VARCHAR(255) PRIMARY KEY,
+  shop_id VARCHAR(255) NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  name VARCHAR(255) NOT NULL,
+  price INTEGER NOT NULL CHECK (price >= 0),
+  category VARCHAR(255) NOT NULL DEFAULT '',
+  image_id VARCHAR(255),
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  INDEX idx_menu_items_shop_id (shop_id)
+);
+
+-- review_dishes テーブル（レビューで食べたメニューとその評価）
+CREATE TABLE review_dishes (
+  review_id VARCHAR(255) NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+  menu_item_id VARCHAR(255) NOT NULL REFERENCES menu_items(id) ON DELETE CASCADE,
+  rating INTEGER NOT NULL CHECK (rating >= 0 AND rating <= 3),
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (review_id, menu_item_id),
+  INDEX idx_review_dishes_menu_item_id (menu_item_id)
+);
+
+-- +goose down
+DROP TABLE IF EXISTS review_dishes;
+DROP TABLE IF EXISTS menu_items;
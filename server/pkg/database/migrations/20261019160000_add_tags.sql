@@ -0,0 +1,25 @@
+-- +goose up
+-- tags テーブル（管理者が管理するカテゴリとユーザーが自由に付けるタグ）
+CREATE TABLE tags (
+  id VARCHAR(255) PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  kind VARCHAR(16) NOT NULL,
+  parent_id VARCHAR(255) REFERENCES tags(id),
+  created_by VARCHAR(255) NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP ON UPDATE CURRENT_TIMESTAMP,
+  UNIQUE KEY uq_tags_name (name),
+  INDEX idx_tags_parent_id (parent_id)
+);
+
+-- shop_tags テーブル
+CREATE TABLE shop_tags (
+  shop_id VARCHAR(255) NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  tag_id VARCHAR(255) NOT NULL REFERENCES tags(id) ON DELETE CASCADE,
+  PRIMARY KEY (shop_id, tag_id),
+  INDEX idx_shop_tags_tag_id (tag_id)
+);
+
+-- +goose down
+DROP TABLE IF EXISTS shop_tags;
+DROP TABLE IF EXISTS tags;
@@ -0,0 +1,85 @@
+-- +goose up
+-- payment_methods テーブル（支払い方法のカタログ）
+CREATE TABLE payment_methods (
+  code VARCHAR(64) PRIMARY KEY,
+  name VARCHAR(255) NOT NULL,
+  category VARCHAR(32) NOT NULL,
+  sort_order INTEGER NOT NULL DEFAULT 0
+);
+
+-- payment_method_aliases テーブル（表記ゆれ）
+CREATE TABLE payment_method_aliases (
+  alias VARCHAR(255) PRIMARY KEY,
+  code VARCHAR(64) NOT NULL REFERENCES payment_methods(code) ON DELETE CASCADE,
+  INDEX idx_payment_method_aliases_code (code)
+);
+
+INSERT INTO payment_methods (code, name, category, sort_order) VALUES
+  ('cash', '現金', 'cash', 10),
+  ('credit_card', 'クレジットカード', 'credit_card', 20),
+  ('visa', 'VISA', 'credit_card', 21),
+  ('mastercard', 'Mastercard', 'credit_card', 22),
+  ('jcb', 'JCB', 'credit_card', 23),
+  ('amex', 'American Express', 'credit_card', 24),
+  ('diners', 'Diners Club', 'credit_card', 25),
+  ('paypay', 'PayPay', 'qr_code', 30),
+  ('rakuten_pay', '楽天ペイ', 'qr_code', 31),
+  ('d_barai', 'd払い', 'qr_code', 32),
+  ('au_pay', 'au PAY', 'qr_code', 33),
+  ('merpay', 'メルペイ', 'qr_code', 34),
+  ('alipay', 'Alipay', 'qr_code', 35),
+  ('wechat_pay', 'WeChat Pay', 'qr_code', 36),
+  ('transport_ic', '交通系IC', 'ic_card', 40),
+  ('id', 'iD', 'ic_card', 41),
+  ('quicpay', 'QUICPay', 'ic_card', 42),
+  ('nanaco', 'nanaco', 'ic_card', 43),
+  ('waon', 'WAON', 'ic_card', 44),
+  ('rakuten_edy', '楽天Edy', 'ic_card', 45);
+
+INSERT INTO payment_method_aliases (alias, code) VALUES
+  ('げんきん', 'cash'),
+  ('クレカ', 'credit_card'),
+  ('クレジット', 'credit_card'),
+  ('credit', 'credit_card'),
+  ('ビザ', 'visa'),
+  ('master', 'mastercard'),
+  ('マスター', 'mastercard'),
+  ('マスターカード', 'mastercard'),
+  ('アメックス', 'amex'),
+  ('アメリカンエキスプレス', 'amex'),
+  ('ダイナース', 'diners'),
+  ('ダイナースクラブ', 'diners'),
+  ('ペイペイ', 'paypay'),
+  ('rakutenpay', 'rakuten_pay'),
+  ('楽天pay', 'rakuten_pay'),
+  ('dbarai', 'd_barai'),
+  ('ドコモ払い', 'd_barai'),
+  ('auペイ', 'au_pay'),
+  ('エーユーペイ', 'au_pay'),
+  ('アリペイ', 'alipay'),
+  ('支付宝', 'alipay'),
+  ('wechat', 'wechat_pay'),
+  ('微信支付', 'wechat_pay'),
+  ('交通系', 'transport_ic'),
+  ('交通系電子マネー', 'transport_ic'),
+  ('Suica', 'transport_ic'),
+  ('スイカ', 'transport_ic'),
+  ('PASMO', 'transport_ic'),
+  ('パスモ', 'transport_ic'),
+  ('ICOCA', 'transport_ic'),
+  ('kitaca', 'transport_ic'),
+  ('manaca', 'transport_ic'),
+  ('TOICA', 'transport_ic'),
+  ('SUGOCA', 'transport_ic'),
+  ('nimoca', 'transport_ic'),
+  ('はやかけん', 'transport_ic'),
+  ('アイディ', 'id'),
+  ('クイックペイ', 'quicpay'),
+  ('ナナコ', 'nanaco'),
+  ('ワオン', 'waon'),
+  ('edy', 'rakuten_edy'),
+  ('エディ', 'rakuten_edy');
+
+-- +goose down
+DROP TABLE IF EXISTS payment_method_aliases;
+DROP TABLE IF EXISTS payment_methods;
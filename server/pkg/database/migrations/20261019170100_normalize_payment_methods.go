@@ -0,0 +1,104 @@
+// Package migrations holds the Go migrations. They are registered with goose
+// on import and run in version order together with the SQL files.
+package migrations
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"backend/pkg/textnorm"
+
+	"github.com/pressly/goose/v3"
+)
+
+func init() {
+	goose.AddMigrationContext(upNormalizePaymentMethods, downNormalizePaymentMethods)
+}
+
+// upNormalizePaymentMethods rewrites the free-text payment methods of shops
+// to the catalog codes. Values that match no catalog entry are left as they
+// are so that no information is lost.
+func upNormalizePaymentMethods(ctx context.Context, tx *sql.Tx) error {
+	codes := make(map[string]string)
+
+	rows, err := tx.QueryContext(ctx, `
+SELECT code, code FROM payment_methods
+UNION ALL
+SELECT name, code FROM payment_methods
+UNION ALL
+SELECT alias, code FROM payment_method_aliases
+`)
+	if err != nil {
+		return fmt.Errorf("failed to get payment method catalog: %w", err)
+	}
+	for rows.Next() {
+		var name, code string
+		if err := rows.Scan(&name, &code); err != nil {
+			_ = rows.Close()
+
+			return fmt.Errorf("failed to scan payment method: %w", err)
+		}
+		codes[textnorm.Fold(name)] = code
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("failed to read payment method catalog: %w", err)
+	}
+
+	type shopPaymentMethod struct {
+		shopID, paymentMethod string
+	}
+	var current []shopPaymentMethod
+
+	rows, err = tx.QueryContext(ctx, `SELECT shop_id, payment_method FROM shop_payment_methods`)
+	if err != nil {
+		return fmt.Errorf("failed to get shop payment methods: %w", err)
+	}
+	for rows.Next() {
+		var row shopPaymentMethod
+		if err := rows.Scan(&row.shopID, &row.paymentMethod); err != nil {
+			_ = rows.Close()
+
+			return fmt.Errorf("failed to scan shop payment method: %w", err)
+		}
+		current = append(current, row)
+	}
+	if err := rows.Close(); err != nil {
+		return fmt.Errorf("failed to read shop payment methods: %w", err)
+	}
+
+	for _, row := range current {
+		code, ok := codes[textnorm.Fold(row.paymentMethod)]
+		if !ok || code == row.paymentMethod {
+			continue
+		}
+
+		_, err := tx.ExecContext(
+			ctx,
+			`DELETE FROM shop_payment_methods WHERE shop_id = ? AND payment_method = ?`,
+			row.shopID,
+			row.paymentMethod,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to delete shop payment method: %w", err)
+		}
+
+		_, err = tx.ExecContext(
+			ctx,
+			`INSERT IGNORE INTO shop_payment_methods (shop_id, payment_method) VALUES (?, ?)`,
+			row.shopID,
+			code,
+		)
+		if err != nil {
+			return fmt.Errorf("failed to save shop payment method: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// downNormalizePaymentMethods does nothing: the original spellings are not
+// kept, and codes are valid free-text values.
+func downNormalizePaymentMethods(context.Context, *sql.Tx) error {
+	return nil
+}
@@ -0,0 +1,25 @@
+-- +goose up
+ALTER TABLE shops
+  ADD COLUMN status VARCHAR(32) NOT NULL DEFAULT 'open',
+  ADD COLUMN moved_to VARCHAR(255) NULL,
+  ADD CONSTRAINT fk_shops_moved_to FOREIGN KEY (moved_to) REFERENCES shops(id) ON DELETE SET NULL;
+
+-- closure_reports テーブル（閉店・移転などの報告）
+CREATE TABLE closure_reports (
+  id VARCHAR(255) PRIMARY KEY,
+  shop_id VARCHAR(255) NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  reporter VARCHAR(255) NOT NULL,
+  status VARCHAR(32) NOT NULL,
+  moved_to VARCHAR(255),
+  note VARCHAR(1024) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  resolved_at TIMESTAMP NULL,
+  INDEX idx_closure_reports_shop_id (shop_id, resolved_at, created_at)
+);
+
+-- +goose down
+DROP TABLE IF EXISTS closure_reports;
+ALTER TABLE shops
+  DROP FOREIGN KEY fk_shops_moved_to,
+  DROP COLUMN moved_to,
+  DROP COLUMN status;
@@ -0,0 +1,23 @@
+-- +goose up
+-- shop_redirects テーブル（統合された店舗の旧IDから統合先へのリダイレクト）
+CREATE TABLE shop_redirects (
+  from_shop_id VARCHAR(255) PRIMARY KEY,
+  to_shop_id VARCHAR(255) NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_shop_redirects_to_shop_id (to_shop_id)
+);
+
+-- shop_merges テーブル（店舗統合の監査記録）
+CREATE TABLE shop_merges (
+  id VARCHAR(255) PRIMARY KEY,
+  source_shop_id VARCHAR(255) NOT NULL,
+  target_shop_id VARCHAR(255) NOT NULL,
+  actor VARCHAR(255) NOT NULL,
+  source_snapshot JSON NOT NULL,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_shop_merges_target_shop_id (target_shop_id)
+);
+
+-- +goose down
+DROP TABLE IF EXISTS shop_merges;
+DROP TABLE IF EXISTS shop_redirects;
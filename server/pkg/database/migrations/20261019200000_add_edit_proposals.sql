@@ -0,0 +1,19 @@
+-- +goose up
+-- edit_proposals テーブル（店舗情報の編集提案と承認・却下の履歴）
+CREATE TABLE edit_proposals (
+  id VARCHAR(255) PRIMARY KEY,
+  shop_id VARCHAR(255) NOT NULL REFERENCES shops(id) ON DELETE CASCADE,
+  proposer VARCHAR(255) NOT NULL,
+  changes JSON NOT NULL,
+  note VARCHAR(1024) NOT NULL DEFAULT '',
+  status VARCHAR(16) NOT NULL DEFAULT 'pending',
+  reviewer VARCHAR(255),
+  review_note VARCHAR(1024) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  reviewed_at TIMESTAMP NULL,
+  INDEX idx_edit_proposals_shop_id (shop_id, created_at),
+  INDEX idx_edit_proposals_status (status, created_at)
+);
+
+-- +goose down
+DROP TABLE IF EXISTS edit_proposals;
@@ -0,0 +1,35 @@
+-- +goose up
+ALTER TABLE reviews
+  ADD COLUMN hidden BOOLEAN NOT NULL DEFAULT FALSE;
+
+-- review_reports テーブル（不適切なレビューの報告）
+CREATE TABLE review_reports (
+  id VARCHAR(255) PRIMARY KEY,
+  review_id VARCHAR(255) NOT NULL REFERENCES reviews(id) ON DELETE CASCADE,
+  reporter VARCHAR(255) NOT NULL,
+  reason VARCHAR(32) NOT NULL,
+  note VARCHAR(1024) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  resolved_at TIMESTAMP NULL,
+  INDEX idx_review_reports_review_id (review_id, resolved_at),
+  INDEX idx_review_reports_resolved_at (resolved_at, created_at)
+);
+
+-- moderation_logs テーブル（モデレーターの操作記録。レビューが削除されても残す）
+CREATE TABLE moderation_logs (
+  id VARCHAR(255) PRIMARY KEY,
+  review_id VARCHAR(255) NOT NULL,
+  author VARCHAR(255) NOT NULL,
+  moderator VARCHAR(255) NOT NULL,
+  action VARCHAR(16) NOT NULL,
+  note VARCHAR(1024) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_moderation_logs_review_id (review_id, created_at),
+  INDEX idx_moderation_logs_created_at (created_at)
+);
+
+-- +goose down
+DROP TABLE IF EXISTS moderation_logs;
+DROP TABLE IF EXISTS review_reports;
+ALTER TABLE reviews
+  DROP COLUMN hidden;
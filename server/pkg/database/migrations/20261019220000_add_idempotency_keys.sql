@@ -0,0 +1,17 @@
+-- +goose up
+-- idempotency_keys テーブル（Idempotency-Key 付きリクエストのレスポンス）
+CREATE TABLE idempotency_keys (
+  user_id VARCHAR(255) NOT NULL,
+  idempotency_key VARCHAR(255) NOT NULL,
+  request_hash CHAR(64) NOT NULL,
+  status_code INT NOT NULL DEFAULT 0,
+  content_type VARCHAR(255) NOT NULL DEFAULT '',
+  body MEDIUMBLOB,
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  expires_at TIMESTAMP NOT NULL,
+  PRIMARY KEY (user_id, idempotency_key),
+  INDEX idx_idempotency_keys_expires_at (expires_at)
+);
+
+-- +goose down
+DROP TABLE IF EXISTS idempotency_keys;
@@ -0,0 +1,19 @@
+-- +goose up
+-- audit_logs テーブル（作成・更新・削除の記録。追記のみで、対象が削除されても残す）
+CREATE TABLE audit_logs (
+  id VARCHAR(255) PRIMARY KEY,
+  actor VARCHAR(255) NOT NULL,
+  action VARCHAR(16) NOT NULL,
+  entity_type VARCHAR(32) NOT NULL,
+  entity_id VARCHAR(255) NOT NULL,
+  before_snapshot JSON NULL,
+  after_snapshot JSON NULL,
+  request_id VARCHAR(255) NOT NULL DEFAULT '',
+  created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+  INDEX idx_audit_logs_created_at (created_at),
+  INDEX idx_audit_logs_entity (entity_type, entity_id, created_at),
+  INDEX idx_audit_logs_actor (actor, created_at)
+);
+
+-- +goose down
+DROP TABLE IF EXISTS audit_logs;
@@ -0,0 +1,8 @@
+-- +goose up
+-- 予約したリクエストだけがレスポンスを保存・解放できるようにする
+ALTER TABLE idempotency_keys
+  ADD COLUMN reservation_id VARCHAR(255) NOT NULL DEFAULT '';
+
+-- +goose down
+ALTER TABLE idempotency_keys
+  DROP COLUMN reservation_id;
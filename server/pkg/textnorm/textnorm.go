@@ -0,0 +1,37 @@
+// Package textnorm folds user input so that spellings people consider the
+// same compare equal, e.g. "ＰａｙＰａｙ", "pay pay" and "PayPay", or
+// "ペイペイ" and "ぺいぺい".
+package textnorm
+
+import (
+	"strings"
+	"unicode"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// separators are dropped by Fold because they are written inconsistently.
+const separators = "-_.・･〜~/"
+
+// Fold returns the comparison key of s. It applies NFKC, which unifies
+// full-width and half-width forms, lowercases, maps katakana to hiragana and
+// drops white space and separators.
+func Fold(s string) string {
+	s = norm.NFKC.String(s)
+
+	var sb strings.Builder
+	sb.Grow(len(s))
+	for _, r := range s {
+		switch {
+		case unicode.IsSpace(r) || strings.ContainsRune(separators, r):
+			continue
+		case r >= 'ァ' && r <= 'ヶ':
+			r -= 'ァ' - 'ぁ'
+		default:
+			r = unicode.ToLower(r)
+		}
+		sb.WriteRune(r)
+	}
+
+	return sb.String()
+}